@@ -5,15 +5,21 @@ const (
 	// Request/validation
 	ErrorCodeValidation     = "VALIDATION_ERROR"
 	ErrorCodeBoundary       = "BOUNDARY_ERROR"
+	ErrorCodeCrate          = "CRATE_ERROR"
+	ErrorCodeObstacle       = "OBSTACLE_ERROR"
+	ErrorCodeNoPath         = "NO_PATH"
+	ErrorCodePathConflict   = "PATH_CONFLICT"
 	ErrorCodeRobotIdInvalid = "ROBOT_ID_INVALID"
 
 	// Lookup
-	ErrorCodeTaskNotFound  = "TASK_NOT_FOUND"
-	ErrorCodeRobotNotFound = "ROBOT_NOT_FOUND"
+	ErrorCodeTaskNotFound    = "TASK_NOT_FOUND"
+	ErrorCodeRobotNotFound   = "ROBOT_NOT_FOUND"
+	ErrorCodeWebhookNotFound = "WEBHOOK_NOT_FOUND"
 
 	// State
-	ErrorCodeRobotBusy       = "ROBOT_BUSY"
-	ErrorCodeTaskAlreadyDone = "TASK_ALREADY_TERMINAL"
+	ErrorCodeRobotBusy              = "ROBOT_BUSY"
+	ErrorCodeTaskAlreadyDone        = "TASK_ALREADY_TERMINAL"
+	ErrorCodeRobotAlreadyRegistered = "ROBOT_ALREADY_REGISTERED"
 
 	// Queue/capacity
 	ErrorCodeTaskQueueFull = "TASK_QUEUE_FULL"
@@ -21,6 +27,9 @@ const (
 	// Sdk
 	ErrorSDKFailedToCancel = "SDK_CANCEL_FAILED"
 
+	// Availability
+	ErrorCodeShuttingDown = "SHUTTING_DOWN"
+
 	// General
 	ErrorCodeInternal = "INTERNAL_ERROR"
 )
@@ -0,0 +1,19 @@
+package constant
+
+import "time"
+
+// Outbox relay defaults: how often pending entries are looked for, how many
+// are read at a time, and where they are published.
+const (
+	OutboxPollInterval = 50 * time.Millisecond
+	OutboxBatchSize    = 100
+	OutboxSinks        = OutboxSinkBus
+)
+
+// Outbox sinks that can be listed in OUTBOX_SINKS.
+const (
+	// OutboxSinkBus publishes the events on the in-process event bus
+	OutboxSinkBus = "bus"
+	// OutboxSinkLog writes one log line per event
+	OutboxSinkLog = "log"
+)
@@ -1,6 +1,32 @@
 package constant
 
+import "time"
+
 // Robot moves 1 unit per single command, like (0,0) after N will be (0,1)
 const (
 	RobotMoveUnit = 1
 )
+
+// RobotStepDuration is how long a robot takes to carry out a single command,
+// used to estimate how long a task will run.
+const RobotStepDuration = 2 * time.Second
+
+// MaxQueuedTasks is how many unfinished tasks (running plus queued) a robot
+// accepts at once, matching the depth of the SDK task queue.
+const MaxQueuedTasks = 5
+
+// MaxExpandedCommands caps how many single-letter commands a compact command
+// string such as "(NE)3" may expand to, so a large repeat count cannot flood the robot.
+const MaxExpandedCommands = 1000
+
+// TaskEventKeepAlive is how often an idle task event stream sends a comment,
+// so proxies do not close the connection while a robot is between updates.
+const TaskEventKeepAlive = 15 * time.Second
+
+// TaskMonitorTimeout is the longest a task is monitored; a task still running
+// by then is marked FAILED.
+const TaskMonitorTimeout = 30 * time.Minute
+
+// TaskCancelReportTimeout is how long cancelling a monitored task waits for
+// the SDK to report the positions the robot reached before it stopped.
+const TaskCancelReportTimeout = time.Second
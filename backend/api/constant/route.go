@@ -2,7 +2,23 @@ package constant
 
 // API route constants
 const (
-	RouteCreateTask     = "POST /api/robots/{robotId}/tasks"
-	RouteGetTaskById    = "GET /api/tasks/{taskId}"
-	RouteDeleteTaskById = "DELETE /api/tasks/{taskId}"
+	RouteCreateTask        = "POST /api/robots/{robotId}/tasks"
+	RoutePreviewTask       = "POST /api/robots/{robotId}/tasks:preview"
+	RouteBatchMove         = "POST /api/fleet/moves"
+	RouteFleetTelemetry    = "GET /api/fleet/telemetry"
+	RouteGetTaskById       = "GET /api/tasks/{taskId}"
+	RouteDeleteTaskById    = "DELETE /api/tasks/{taskId}"
+	RouteTaskEvents        = "GET /api/tasks/{taskId}/events"
+	RouteTaskTrajectory    = "GET /api/tasks/{taskId}/trajectory"
+	RouteGetTaskMonitors   = "GET /api/monitors"
+	RouteGetRobots         = "GET /api/robots"
+	RouteGetRobotById      = "GET /api/robots/{robotId}"
+	RouteRegisterRobot     = "POST /api/robots"
+	RouteDecommissionRobot = "DELETE /api/robots/{robotId}"
+	RouteRegisterWebhook   = "POST /api/webhooks"
+	RouteDeleteWebhook     = "DELETE /api/webhooks/{webhookId}"
+	RouteWebhookDeliveries = "GET /api/webhooks/{webhookId}/deliveries"
+	RouteGetWarehouse      = "GET /api/warehouse"
+	RouteGetCrates         = "GET /api/warehouse/crates"
+	RouteGetCell           = "GET /api/warehouse/cells/{x}/{y}"
 )
@@ -0,0 +1,20 @@
+package constant
+
+import "time"
+
+// Shutdown defaults: how long in-flight HTTP requests get to finish, how long
+// running tasks are waited for, and what happens to them.
+const (
+	ShutdownHTTPTimeout = 10 * time.Second
+	ShutdownTaskTimeout = 30 * time.Second
+	ShutdownTaskPolicy  = ShutdownTasksWait
+)
+
+// What happens to running tasks on shutdown, set with SHUTDOWN_TASK_POLICY.
+const (
+	// ShutdownTasksWait waits for them to end; those still running at the
+	// deadline are left to the robots and recovered on the next start
+	ShutdownTasksWait = "wait"
+	// ShutdownTasksCancel cancels them through the SDK
+	ShutdownTasksCancel = "cancel"
+)
@@ -0,0 +1,12 @@
+package constant
+
+// Task stores that can be selected with TASK_STORE.
+const (
+	// TaskStoreMemory keeps tasks in memory; they are lost on restart
+	TaskStoreMemory = "memory"
+	// TaskStoreSQLite keeps tasks in the SQLite database at SQLITE_PATH
+	TaskStoreSQLite = "sqlite"
+)
+
+// SQLitePath is where the SQLite task store lives unless SQLITE_PATH says otherwise.
+const SQLitePath = "warehouse.db"
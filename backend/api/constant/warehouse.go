@@ -1,6 +1,6 @@
 package constant
 
-// Warehouse configuration
+// Default warehouse configuration, used when WAREHOUSE_* settings are not provided
 const (
 	WarehouseSizeX = 10
 	WarehouseSizeY = 10
@@ -0,0 +1,15 @@
+package constant
+
+import "time"
+
+// Webhook delivery defaults: attempts per payload, the wait after the first
+// failed attempt (doubling after each further one), and the timeout of one attempt.
+const (
+	WebhookMaxAttempts    = 5
+	WebhookInitialBackoff = 1 * time.Second
+	WebhookTimeout        = 5 * time.Second
+)
+
+// WebhookAttemptHistory is how many delivery attempts each webhook's log
+// keeps; older attempts are dropped.
+const WebhookAttemptHistory = 200
@@ -0,0 +1,22 @@
+package controller
+
+import "net/http"
+
+// IBatchMoveController processes POST /fleet/moves requests.
+//
+// Request:
+//   - Body:   dtos.BatchMoveRequest (JSON), one destination per robot in priority order.
+//
+// Responses:
+//   - 201 Created: every robot got a route, returns dtos.BatchMovePlan.
+//   - 400 Bad Request: invalid JSON, no moves, a missing or repeated robot ID, or a
+//     robot already at its destination.
+//   - 404 Not Found: a robot does not exist.
+//   - 422 Unprocessable Entity: no conflict-free joint plan exists; nothing was enqueued.
+//   - 429 Too many requests: a robot's task queue is full.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IBatchMoveController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	batchMove "warehouse-robots/backend/api/service"
+)
+
+// BatchMoveControllerImpl handles HTTP requests that move several robots at once.
+type BatchMoveControllerImpl struct {
+	Service batchMove.IBatchMoveService
+	Helper  *helper.ControllerHelper
+}
+
+// NewBatchMoveController constructs a BatchMoveControllerImpl with the given service.
+func NewBatchMoveController(service batchMove.IBatchMoveService) IBatchMoveController {
+	return &BatchMoveControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *BatchMoveControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	var req dtos.BatchMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Invalid JSON format", err.Error())
+		return
+	}
+
+	if err := validateBatchMoveRequest(req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, err.Error(), "")
+		return
+	}
+
+	plan, err := c.Service.MoveRobots(req)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), helper.ErrorDetails(err))
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusCreated, plan)
+}
+
+// validateBatchMoveRequest ensures there is at least one move and every robot
+// appears once; destinations are checked by the service against the warehouse map.
+func validateBatchMoveRequest(req dtos.BatchMoveRequest) error {
+	if len(req.Moves) == 0 {
+		return fmt.Errorf("moves cannot be empty")
+	}
+
+	seen := make(map[string]bool, len(req.Moves))
+	for i, move := range req.Moves {
+		if move.RobotID == "" {
+			return fmt.Errorf("move %d has no robot_id", i)
+		}
+		if seen[move.RobotID] {
+			return fmt.Errorf("robot %s has more than one move", move.RobotID)
+		}
+		seen[move.RobotID] = true
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package controller
+
+import (
+	"testing"
+
+	"warehouse-robots/backend/api/dtos"
+)
+
+func TestValidateBatchMoveRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		moves   []dtos.BatchMove
+		wantErr bool
+	}{
+		{"two_robots", []dtos.BatchMove{{RobotID: "0"}, {RobotID: "1"}}, false},
+		{"no_moves", nil, true},
+		{"missing_robot_id", []dtos.BatchMove{{RobotID: "0"}, {}}, true},
+		{"robot_twice", []dtos.BatchMove{{RobotID: "0"}, {RobotID: "0"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBatchMoveRequest(dtos.BatchMoveRequest{Moves: tt.moves})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateBatchMoveRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -10,8 +10,9 @@ import "net/http"
 //
 // Responses:
 //   - 201 Created: on successful creation, returns dtos.TaskInfo.
-//   - 400 Bad Request: invalid JSON or invalid command sequence.
-//   - 429 Too many requests: the queue has not Terminated, so we cannot queue a new task.
+//   - 400 Bad Request: invalid JSON, invalid command sequence or impossible crate operation.
+//   - 409 Conflict: the route collides with another robot's reserved path.
+//   - 429 Too many requests: the robot already has constant.MaxQueuedTasks pending tasks.
 //   - 503 Service Unavailable: no robots available.
 //   - 500 Internal Server Error: unexpected failures.
 //
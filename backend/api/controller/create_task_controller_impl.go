@@ -9,6 +9,7 @@ import (
 	"warehouse-robots/backend/api/constant"
 	"warehouse-robots/backend/api/dtos"
 	"warehouse-robots/backend/api/helper"
+	"warehouse-robots/backend/api/parser"
 	createTask "warehouse-robots/backend/api/service"
 )
 
@@ -43,9 +44,9 @@ func (c *CreateTaskControllerImpl) Handle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := c.validateCommands(req.Commands); err != nil {
+	if err := validateRequest(req); err != nil {
 		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
-			constant.ErrorCodeValidation, err.Error(), "")
+			constant.ErrorCodeValidation, err.Error(), helper.ErrorDetails(err))
 		return
 	}
 
@@ -54,27 +55,34 @@ func (c *CreateTaskControllerImpl) Handle(w http.ResponseWriter, r *http.Request
 
 	if err != nil {
 		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
-		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), helper.ErrorDetails(err))
 		return
 	}
 
 	c.Helper.SendSuccessResponse(w, http.StatusCreated, taskInfo)
 }
 
-// validateCommands ensures the command string is non-empty and contains only
-// the supported movement directives: N, S, E, W (case-insensitive; whitespace ignored).
-func (c *CreateTaskControllerImpl) validateCommands(commands string) error {
-	commands = strings.ToUpper(strings.ReplaceAll(commands, " ", ""))
-
-	if len(commands) == 0 {
-		log.Printf("commands cannot be empty")
-		return fmt.Errorf("commands cannot be empty")
+// validateRequest ensures exactly one of commands or goto is given and, for
+// commands, that they are well-formed. Goto targets are checked by the service
+// once it knows the warehouse map. Shared with the preview controller.
+func validateRequest(req dtos.CreateTaskRequest) error {
+	if req.Goto != nil {
+		if strings.TrimSpace(req.Commands) != "" {
+			return fmt.Errorf("commands and goto cannot be combined")
+		}
+		return nil
 	}
+	return validateCommands(req.Commands)
+}
 
-	for i, cmd := range commands {
-		if cmd != 'N' && cmd != 'S' && cmd != 'E' && cmd != 'W' {
-			return fmt.Errorf("invalid command character '%c' at position %d. Only N, S, E, W are allowed", cmd, i+1)
-		}
+// validateCommands ensures the command string parses under the compact command
+// grammar (see parser.Expand): N, S, E, W moves plus G (grab) and D (drop),
+// with optional repeat counts and groups, e.g. "N9" or "(NE)3".
+// Syntax errors carry the offending position.
+func validateCommands(commands string) error {
+	if _, err := parser.Expand(commands); err != nil {
+		log.Printf("invalid commands %q: %v", commands, err)
+		return err
 	}
 	return nil
 }
@@ -53,8 +53,6 @@ func TestCreateTaskController_InvalidJSON(t *testing.T) {
 }
 
 func TestValidateCommands(t *testing.T) {
-	controller := &CreateTaskControllerImpl{}
-
 	tests := []struct {
 		commands    string
 		expectError bool
@@ -62,14 +60,24 @@ func TestValidateCommands(t *testing.T) {
 		{"NESW", false},
 		{"nesw", false},
 		{"N E S W", false},
+		{"GNDS", false},
+		{"g d", false},
+		{"N9", false},
+		{"(NE)3", false},
+		{"N2, (E S)2, W", false},
+		{"N0", true},
+		{"(NE", true},
+		{"NE)", true},
+		{"()", true},
+		{"9N", true},
 		{"", true},
 		{"   ", true},
 		{"NXS", true},
-		{"N123", true},
+		{"N123", false}, // N repeated 123 times
 	}
 
 	for _, test := range tests {
-		err := controller.validateCommands(test.commands)
+		err := validateCommands(test.commands)
 		if test.expectError && err == nil {
 			t.Errorf("Expected error for commands: %s", test.commands)
 		}
@@ -78,3 +86,29 @@ func TestValidateCommands(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         dtos.CreateTaskRequest
+		expectError bool
+	}{
+		{"commands_only", dtos.CreateTaskRequest{Commands: "NESW"}, false},
+		{"goto_only", dtos.CreateTaskRequest{Goto: &dtos.CellRef{X: 7, Y: 3}}, false},
+		{"goto_with_blank_commands", dtos.CreateTaskRequest{Commands: " ", Goto: &dtos.CellRef{X: 7, Y: 3}}, false},
+		{"goto_and_commands", dtos.CreateTaskRequest{Commands: "N", Goto: &dtos.CellRef{X: 7, Y: 3}}, true},
+		{"neither", dtos.CreateTaskRequest{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRequest(tt.req)
+			if tt.expectError && err == nil {
+				t.Errorf("Expected error for request: %+v", tt.req)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error for request: %+v, error: %v", tt.req, err)
+			}
+		})
+	}
+}
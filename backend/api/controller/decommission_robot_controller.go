@@ -0,0 +1,19 @@
+package controller
+
+import "net/http"
+
+// IDecommissionRobotController processes DELETE /robots/{robotId} requests.
+//
+// Request:
+//   - Path:   robotId (string) resolved via r.PathValue("robotId").
+//
+// Responses:
+//   - 204 No Content: the robot is out of service.
+//   - 404 Not Found: no robot is in service under the ID.
+//   - 409 Conflict: the robot still has unfinished tasks.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IDecommissionRobotController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
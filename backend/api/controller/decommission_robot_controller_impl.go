@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	decommissionRobot "warehouse-robots/backend/api/service"
+)
+
+type DecommissionRobotControllerImpl struct {
+	Service decommissionRobot.IRobotRegistryService
+	Helper  *helper.ControllerHelper
+}
+
+// NewDecommissionRobotController constructor
+func NewDecommissionRobotController(service decommissionRobot.IRobotRegistryService) IDecommissionRobotController {
+	return &DecommissionRobotControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *DecommissionRobotControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	robotId := r.PathValue("robotId")
+	if robotId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Robot ID is required", "")
+		return
+	}
+
+	if err := c.Service.DecommissionRobot(robotId); err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendNoContentResponse(w)
+}
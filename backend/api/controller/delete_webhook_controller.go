@@ -0,0 +1,18 @@
+package controller
+
+import "net/http"
+
+// IDeleteWebhookController processes DELETE /webhooks/{webhookId} requests.
+//
+// Request:
+//   - Path:   webhookId (string) resolved via r.PathValue("webhookId").
+//
+// Responses:
+//   - 204 No Content: the webhook is unregistered; its delivery log stays available.
+//   - 404 Not Found: no webhook with the ID.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IDeleteWebhookController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
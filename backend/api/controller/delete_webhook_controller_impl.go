@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	deleteWebhook "warehouse-robots/backend/api/service"
+)
+
+type DeleteWebhookControllerImpl struct {
+	Service deleteWebhook.IWebhookService
+	Helper  *helper.ControllerHelper
+}
+
+// NewDeleteWebhookController constructor
+func NewDeleteWebhookController(service deleteWebhook.IWebhookService) IDeleteWebhookController {
+	return &DeleteWebhookControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *DeleteWebhookControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	webhookId := r.PathValue("webhookId")
+
+	if webhookId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Webhook ID is required", "")
+		return
+	}
+
+	if err := c.Service.DeleteWebhook(webhookId); err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendNoContentResponse(w)
+}
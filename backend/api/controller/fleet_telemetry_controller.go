@@ -0,0 +1,27 @@
+package controller
+
+import "net/http"
+
+// IFleetTelemetryController serves the live telemetry of the whole fleet over
+// a WebSocket.
+//
+// GET Request (WebSocket upgrade):
+//   - Query:  robots (optional) comma-separated robot IDs to follow.
+//   - Query:  types (optional) comma-separated event types: status, position, error, end.
+//
+// Messages:
+//   - Server to client: one TaskEvent JSON object per text message. Position
+//     updates of a robot are coalesced when the client reads slowly; a client
+//     that falls too far behind is disconnected with close code 1008.
+//   - Client to server: a FleetFilter JSON object replaces the filter; an
+//     invalid one is answered with an ErrorResponse message.
+//
+// Responses:
+//   - 101 Switching Protocols: the socket is open.
+//   - 400 Bad Request: unknown event type in the filter, or not a WebSocket request.
+//   - 403 Forbidden: the origin is not allowed.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IFleetTelemetryController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
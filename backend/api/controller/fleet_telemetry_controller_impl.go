@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	fleetTelemetry "warehouse-robots/backend/api/service"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// telemetryWriteWait is how long a single write to the client may take.
+	telemetryWriteWait = 10 * time.Second
+	// telemetryPongWait is how long the client may stay silent before the
+	// connection is considered dead; pings go out well within it.
+	telemetryPongWait  = 60 * time.Second
+	telemetryPingEvery = telemetryPongWait * 9 / 10
+	// telemetryMaxMessage caps the size of filter messages from the client.
+	telemetryMaxMessage = 4096
+)
+
+type FleetTelemetryControllerImpl struct {
+	Service  fleetTelemetry.IFleetTelemetryService
+	Helper   *helper.ControllerHelper
+	Upgrader websocket.Upgrader
+}
+
+// NewFleetTelemetryController constructor. allowedOrigins is the CORS origin
+// setting, which browsers do not apply to WebSockets, so it is checked here.
+func NewFleetTelemetryController(service fleetTelemetry.IFleetTelemetryService, allowedOrigins string) IFleetTelemetryController {
+	return &FleetTelemetryControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return originAllowed(r, allowedOrigins)
+			},
+		},
+	}
+}
+
+// Handle fleet telemetry controller handler
+func (c *FleetTelemetryControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	filter := dtos.FleetFilter{
+		RobotIDs: splitList(r.URL.Query().Get("robots")),
+		Types:    splitList(r.URL.Query().Get("types")),
+	}
+
+	feed, err := c.Service.SubscribeFleet(filter)
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+	defer feed.Close()
+
+	// Upgrade answers failed handshakes itself
+	conn, err := c.Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("fleet telemetry: upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// the reader handles filter changes and notices when the client goes away;
+	// replies go through the writer below, the only one allowed to write
+	replies := make(chan dtos.ErrorResponse, 1)
+	gone := make(chan struct{})
+	go c.readFilters(conn, feed, replies, gone)
+
+	ping := time.NewTicker(telemetryPingEvery)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-feed.Ready:
+			events, open := feed.Drain()
+			for _, event := range events {
+				if err := writeJSON(conn, event); err != nil {
+					return
+				}
+			}
+			if !open {
+				closeSocket(conn, websocket.ClosePolicyViolation, "fell behind, reconnect to resume")
+				return
+			}
+		case reply := <-replies:
+			if err := writeJSON(conn, reply); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(telemetryWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
+
+// readFilters applies the filters the client sends until the connection closes.
+func (c *FleetTelemetryControllerImpl) readFilters(conn *websocket.Conn, feed *fleetTelemetry.FleetFeed,
+	replies chan<- dtos.ErrorResponse, gone chan<- struct{}) {
+	defer close(gone)
+
+	conn.SetReadLimit(telemetryMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(telemetryPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(telemetryPongWait))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(telemetryPongWait))
+
+		var filter dtos.FleetFilter
+		err = json.Unmarshal(message, &filter)
+		if err == nil {
+			err = feed.SetFilter(filter)
+		}
+		if err != nil {
+			select {
+			case replies <- dtos.ErrorResponse{Code: constant.ErrorCodeValidation, Message: "Invalid filter", Details: err.Error()}:
+			default:
+				// the client has not read the previous reply yet
+			}
+		}
+	}
+}
+
+// writeJSON sends one text message.
+func writeJSON(conn *websocket.Conn, v any) error {
+	conn.SetWriteDeadline(time.Now().Add(telemetryWriteWait))
+	return conn.WriteJSON(v)
+}
+
+// closeSocket tells the client why the server is closing the connection.
+func closeSocket(conn *websocket.Conn, code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(telemetryWriteWait))
+}
+
+// originAllowed accepts requests without an Origin header (non-browser
+// clients), same-origin requests, and origins in the comma-separated list.
+func originAllowed(r *http.Request, allowedOrigins string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == "http://"+r.Host || origin == "https://"+r.Host {
+		return true
+	}
+	for _, allowed := range splitList(allowedOrigins) {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList splits a comma-separated query value, ignoring blanks.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
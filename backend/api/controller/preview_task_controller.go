@@ -0,0 +1,19 @@
+package controller
+
+import "net/http"
+
+// IPreviewTaskController processes POST /robots/{robotId}/tasks:preview requests.
+//
+// Request:
+//   - Path:   robotId (string) resolved via r.PathValue("robotId").
+//   - Body:   dtos.CreateTaskRequest (JSON), the same payload as task creation.
+//
+// Responses:
+//   - 200 OK: the request would be accepted, returns dtos.TaskPreview.
+//   - 4xx: the request would be rejected, with the same error as task creation.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Nothing is sent to the robot. Error bodies are standardized via ControllerHelper.
+type IPreviewTaskController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	previewTask "warehouse-robots/backend/api/service"
+)
+
+// PreviewTaskControllerImpl handles HTTP requests that dry-run a task so the
+// route can be shown before the operator commits to it.
+type PreviewTaskControllerImpl struct {
+	Service previewTask.IPreviewTaskService
+	Helper  *helper.ControllerHelper
+}
+
+// NewPreviewTaskController constructs a PreviewTaskControllerImpl with the given service.
+func NewPreviewTaskController(service previewTask.IPreviewTaskService) IPreviewTaskController {
+	return &PreviewTaskControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *PreviewTaskControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	robotId := r.PathValue("robotId")
+
+	var req dtos.CreateTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Invalid JSON format", err.Error())
+		return
+	}
+
+	if err := validateRequest(req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, err.Error(), helper.ErrorDetails(err))
+		return
+	}
+
+	preview, err := c.Service.PreviewTask(robotId, req)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), helper.ErrorDetails(err))
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusOK, preview)
+}
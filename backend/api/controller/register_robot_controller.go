@@ -0,0 +1,20 @@
+package controller
+
+import "net/http"
+
+// IRegisterRobotController processes POST /robots requests.
+//
+// Request:
+//   - Body:   dtos.RegisterRobotRequest (JSON) naming the robot to put back in service.
+//
+// Responses:
+//   - 201 Created: the robot is in service again, returns dtos.RobotInfo.
+//   - 400 Bad Request: invalid JSON or an empty ID.
+//   - 404 Not Found: no robot was ever known under the ID.
+//   - 409 Conflict: the robot is already in service.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IRegisterRobotController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	registerRobot "warehouse-robots/backend/api/service"
+)
+
+type RegisterRobotControllerImpl struct {
+	Service registerRobot.IRobotRegistryService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRegisterRobotController constructor
+func NewRegisterRobotController(service registerRobot.IRobotRegistryService) IRegisterRobotController {
+	return &RegisterRobotControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *RegisterRobotControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	var req dtos.RegisterRobotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Invalid JSON format", err.Error())
+		return
+	}
+
+	if req.ID == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Robot ID is required", "")
+		return
+	}
+
+	robotInfo, err := c.Service.RegisterRobot(req.ID)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusCreated, robotInfo)
+}
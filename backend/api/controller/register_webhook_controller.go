@@ -0,0 +1,19 @@
+package controller
+
+import "net/http"
+
+// IRegisterWebhookController processes POST /webhooks requests.
+//
+// Request:
+//   - Body:   dtos.RegisterWebhookRequest (JSON) with the callback URL, optional
+//     secret and the events to deliver.
+//
+// Responses:
+//   - 201 Created: the webhook is registered, returns dtos.WebhookInfo with its secret.
+//   - 400 Bad Request: invalid JSON, URL or event name.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IRegisterWebhookController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
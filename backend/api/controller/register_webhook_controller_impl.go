@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	registerWebhook "warehouse-robots/backend/api/service"
+)
+
+type RegisterWebhookControllerImpl struct {
+	Service registerWebhook.IWebhookService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRegisterWebhookController constructor
+func NewRegisterWebhookController(service registerWebhook.IWebhookService) IRegisterWebhookController {
+	return &RegisterWebhookControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *RegisterWebhookControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	var req dtos.RegisterWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Invalid JSON format", err.Error())
+		return
+	}
+
+	if req.URL == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Webhook URL is required", "")
+		return
+	}
+
+	webhookInfo, err := c.Service.RegisterWebhook(req)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusCreated, webhookInfo)
+}
@@ -0,0 +1,18 @@
+package controller
+
+import "net/http"
+
+// IRetrieveCellController handles HTTP requests to inspect a single warehouse cell.
+//
+// GET Request:
+//   - Path:   x and y resolved via r.PathValue("x") and r.PathValue("y").
+//
+// Responses:
+//   - 200 Success: dtos.CellInfo with the cell's crate and robots.
+//   - 400 Bad Request: coordinates are not unsigned integers or lie outside the warehouse.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveCellController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	retrieveWarehouse "warehouse-robots/backend/api/service"
+)
+
+type RetrieveCellControllerImpl struct {
+	Service retrieveWarehouse.IRetrieveWarehouseService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveCellController constructor
+func NewRetrieveCellController(service retrieveWarehouse.IRetrieveWarehouseService) IRetrieveCellController {
+	return &RetrieveCellControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveCellControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	// Get coordinates from request url.
+	x, errX := strconv.ParseUint(r.PathValue("x"), 10, 32)
+	y, errY := strconv.ParseUint(r.PathValue("y"), 10, 32)
+	if errX != nil || errY != nil {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Cell coordinates must be unsigned integers",
+			"x="+r.PathValue("x")+", y="+r.PathValue("y"))
+		return
+	}
+
+	cellInfo, err := c.Service.RetrieveCell(uint(x), uint(y))
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	// Return successful response (use 200 OK for GET requests)
+	c.Helper.SendSuccessResponse(w, http.StatusOK, cellInfo)
+}
@@ -0,0 +1,17 @@
+package controller
+
+import "net/http"
+
+// IRetrieveCratesController handles HTTP requests to list the crate inventory.
+//
+// GET Request:
+//   - Path:   no parameters.
+//
+// Responses:
+//   - 200 Success: array of dtos.CrateInfo for every crate on the floor.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveCratesController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/helper"
+	retrieveWarehouse "warehouse-robots/backend/api/service"
+)
+
+type RetrieveCratesControllerImpl struct {
+	Service retrieveWarehouse.IRetrieveWarehouseService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveCratesController constructor
+func NewRetrieveCratesController(service retrieveWarehouse.IRetrieveWarehouseService) IRetrieveCratesController {
+	return &RetrieveCratesControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveCratesControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	crateInfos, err := c.Service.RetrieveCrates()
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	// Return successful response (use 200 OK for GET requests)
+	c.Helper.SendSuccessResponse(w, http.StatusOK, crateInfos)
+}
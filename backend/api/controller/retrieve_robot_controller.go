@@ -0,0 +1,18 @@
+package controller
+
+import "net/http"
+
+// IRetrieveRobotController handles HTTP requests to retrieve a single robot.
+//
+// GET Request:
+//   - Path:   robotId resolved via r.PathValue("robotId").
+//
+// Responses:
+//   - 200 Success: if we successfully get the RobotInfo.
+//   - 404 Not Found: robot id does not match any robot in the warehouse.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveRobotController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	retrieveRobot "warehouse-robots/backend/api/service"
+)
+
+type RetrieveRobotControllerImpl struct {
+	Service retrieveRobot.IRetrieveRobotService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveRobotController constructor
+func NewRetrieveRobotController(service retrieveRobot.IRetrieveRobotService) IRetrieveRobotController {
+	return &RetrieveRobotControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveRobotControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	// Get robot id from request url.
+	robotId := r.PathValue("robotId")
+	if robotId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Robot ID is required", "")
+		return
+	}
+
+	robotInfo, err := c.Service.RetrieveRobotById(robotId)
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	// Return successful response (use 200 OK for GET requests)
+	c.Helper.SendSuccessResponse(w, http.StatusOK, robotInfo)
+}
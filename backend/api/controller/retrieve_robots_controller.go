@@ -0,0 +1,17 @@
+package controller
+
+import "net/http"
+
+// IRetrieveRobotsController handles HTTP requests to list every robot in the warehouse.
+//
+// GET Request:
+//   - Path:   no parameters.
+//
+// Responses:
+//   - 200 Success: array of dtos.RobotInfo.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveRobotsController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
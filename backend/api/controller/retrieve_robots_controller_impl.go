@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/helper"
+	retrieveRobot "warehouse-robots/backend/api/service"
+)
+
+type RetrieveRobotsControllerImpl struct {
+	Service retrieveRobot.IRetrieveRobotService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveRobotsController constructor
+func NewRetrieveRobotsController(service retrieveRobot.IRetrieveRobotService) IRetrieveRobotsController {
+	return &RetrieveRobotsControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveRobotsControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	robotInfos, err := c.Service.RetrieveRobots()
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	// Return successful response (use 200 OK for GET requests)
+	c.Helper.SendSuccessResponse(w, http.StatusOK, robotInfos)
+}
@@ -0,0 +1,17 @@
+package controller
+
+import "net/http"
+
+// IRetrieveTaskMonitorsController handles HTTP requests to list the running task monitors.
+//
+// GET Request:
+//   - Path:   no parameters.
+//
+// Responses:
+//   - 200 Success: array of dtos.TaskMonitorInfo, the longest running first.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveTaskMonitorsController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/helper"
+	retrieveTaskMonitors "warehouse-robots/backend/api/service"
+)
+
+type RetrieveTaskMonitorsControllerImpl struct {
+	Service retrieveTaskMonitors.IRetrieveTaskMonitorsService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveTaskMonitorsController constructor
+func NewRetrieveTaskMonitorsController(service retrieveTaskMonitors.IRetrieveTaskMonitorsService) IRetrieveTaskMonitorsController {
+	return &RetrieveTaskMonitorsControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveTaskMonitorsControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	monitors, err := c.Service.RetrieveTaskMonitors()
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusOK, monitors)
+}
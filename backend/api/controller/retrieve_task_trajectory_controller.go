@@ -0,0 +1,19 @@
+package controller
+
+import "net/http"
+
+// IRetrieveTaskTrajectoryController handles HTTP requests for the recorded
+// trajectory of a task.
+//
+// GET Request:
+//   - Path:   taskId resolved via r.PathValue("taskId").
+//
+// Responses:
+//   - 200 Success: the TaskTrajectory, with every position sample and status transition.
+//   - 404 Not Found: Task id not found in the database.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveTaskTrajectoryController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	retrieveTaskTrajectory "warehouse-robots/backend/api/service"
+)
+
+type RetrieveTaskTrajectoryControllerImpl struct {
+	Service retrieveTaskTrajectory.IRetrieveTaskTrajectoryService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveTaskTrajectoryController constructor
+func NewRetrieveTaskTrajectoryController(service retrieveTaskTrajectory.IRetrieveTaskTrajectoryService) IRetrieveTaskTrajectoryController {
+	return &RetrieveTaskTrajectoryControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveTaskTrajectoryControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	taskId := r.PathValue("taskId")
+
+	if taskId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Task ID is required", "")
+		return
+	}
+
+	trajectory, err := c.Service.RetrieveTaskTrajectory(taskId)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusOK, trajectory)
+}
@@ -0,0 +1,17 @@
+package controller
+
+import "net/http"
+
+// IRetrieveWarehouseController handles HTTP requests to describe the warehouse grid.
+//
+// GET Request:
+//   - Path:   no parameters.
+//
+// Responses:
+//   - 200 Success: dtos.WarehouseInfo with the grid size and coordinate range.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type IRetrieveWarehouseController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
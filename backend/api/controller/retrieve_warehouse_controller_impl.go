@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/helper"
+	retrieveWarehouse "warehouse-robots/backend/api/service"
+)
+
+type RetrieveWarehouseControllerImpl struct {
+	Service retrieveWarehouse.IRetrieveWarehouseService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveWarehouseController constructor
+func NewRetrieveWarehouseController(service retrieveWarehouse.IRetrieveWarehouseService) IRetrieveWarehouseController {
+	return &RetrieveWarehouseControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+func (c *RetrieveWarehouseControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	warehouseInfo, err := c.Service.RetrieveWarehouse()
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	// Return successful response (use 200 OK for GET requests)
+	c.Helper.SendSuccessResponse(w, http.StatusOK, warehouseInfo)
+}
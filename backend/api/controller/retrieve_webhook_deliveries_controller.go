@@ -0,0 +1,18 @@
+package controller
+
+import "net/http"
+
+// IRetrieveWebhookDeliveriesController processes GET /webhooks/{webhookId}/deliveries requests.
+//
+// Request:
+//   - Path:   webhookId (string) resolved via r.PathValue("webhookId").
+//
+// Responses:
+//   - 200 Success: every delivery attempt to the webhook, oldest first, as []dtos.WebhookDelivery.
+//   - 404 Not Found: no webhook was ever registered with the ID.
+//   - 500 Internal Server Error: unexpected failures.
+//
+// Error bodies are standardized via ControllerHelper.
+type IRetrieveWebhookDeliveriesController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"net/http"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/helper"
+	webhookDeliveries "warehouse-robots/backend/api/service"
+)
+
+type RetrieveWebhookDeliveriesControllerImpl struct {
+	Service webhookDeliveries.IWebhookService
+	Helper  *helper.ControllerHelper
+}
+
+// NewRetrieveWebhookDeliveriesController constructor
+func NewRetrieveWebhookDeliveriesController(service webhookDeliveries.IWebhookService) IRetrieveWebhookDeliveriesController {
+	return &RetrieveWebhookDeliveriesControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle for the endpoint
+func (c *RetrieveWebhookDeliveriesControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	webhookId := r.PathValue("webhookId")
+
+	if webhookId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Webhook ID is required", "")
+		return
+	}
+
+	deliveries, err := c.Service.ListDeliveries(webhookId)
+	if err != nil {
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+
+	c.Helper.SendSuccessResponse(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,22 @@
+package controller
+
+import "net/http"
+
+// ITaskEventsController streams the events of a task as server-sent events.
+//
+// GET Request:
+//   - Path:   taskId resolved via r.PathValue("taskId").
+//   - Header: Last-Event-ID (optional) resumes after the event with that ID.
+//
+// Responses:
+//   - 200 Success: a text/event-stream of "status", "position", "error" and
+//     finally "end" events, each with a TaskEvent JSON payload. The stream
+//     closes after the end event.
+//   - 400 Bad Request: Last-Event-ID is not a non-negative integer.
+//   - 404 Not Found: Task id not found in the database.
+//   - 500 Internal Server Error: the connection cannot stream.
+//
+// The controller translates service-layer errors into appropriate HTTP responses.
+type ITaskEventsController interface {
+	Handle(w http.ResponseWriter, r *http.Request)
+}
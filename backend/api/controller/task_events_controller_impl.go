@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/helper"
+	taskEvents "warehouse-robots/backend/api/service"
+)
+
+type TaskEventsControllerImpl struct {
+	Service taskEvents.ITaskEventService
+	Helper  *helper.ControllerHelper
+}
+
+// NewTaskEventsController constructor
+func NewTaskEventsController(service taskEvents.ITaskEventService) ITaskEventsController {
+	return &TaskEventsControllerImpl{
+		Service: service,
+		Helper:  helper.NewControllerHelper(),
+	}
+}
+
+// Handle task events controller handler
+func (c *TaskEventsControllerImpl) Handle(w http.ResponseWriter, r *http.Request) {
+	// Get task id from request url.
+	taskId := r.PathValue("taskId")
+
+	if taskId == "" {
+		c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+			constant.ErrorCodeValidation, "Task ID is required", "")
+		return
+	}
+
+	var lastEventID int64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || id < 0 {
+			c.Helper.SendErrorResponse(w, http.StatusBadRequest,
+				constant.ErrorCodeValidation, "Last-Event-ID must be a non-negative integer", header)
+			return
+		}
+		lastEventID = id
+	}
+
+	feed, err := c.Service.SubscribeTaskEvents(taskId, lastEventID)
+	if err != nil {
+		// Map error to appropriate HTTP status and error code
+		statusCode, errorCode := helper.MapErrorToHTTPStatus(err)
+		c.Helper.SendErrorResponse(w, statusCode, errorCode, err.Error(), "")
+		return
+	}
+	defer feed.Close()
+
+	flusher := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range feed.Backlog {
+		if err := writeTaskEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := flusher.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(constant.TaskEventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event, ok := <-feed.Events:
+			if !ok {
+				return
+			}
+			if err := writeTaskEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+		if err := flusher.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeTaskEvent writes one event in the server-sent events format. Events
+// without an ID, i.e. ones reconstructed from the task record, leave the
+// client's last event ID as it was.
+func writeTaskEvent(w io.Writer, event dtos.TaskEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if event.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,22 @@
+package dao
+
+import (
+	"warehouse-robots/backend/api/model"
+)
+
+type ICrateRepository interface {
+	// List returns every crate currently on the floor
+	List() ([]*model.Crate, error)
+
+	// GetByCell returns the crate on the given cell, or nil if the cell is empty
+	GetByCell(x, y uint) (*model.Crate, error)
+
+	// Place puts a crate on its cell, failing if the cell is already occupied
+	Place(crate *model.Crate) error
+
+	// Pick removes the crate from the given cell and records it as carried by the robot
+	Pick(robotID string, x, y uint) (*model.Crate, error)
+
+	// Drop puts the crate carried by the robot onto the given cell
+	Drop(robotID string, x, y uint) error
+}
@@ -0,0 +1,126 @@
+package dao
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"warehouse-robots/backend/api/model"
+)
+
+// cellKey identifies a single cell on the warehouse grid.
+type cellKey struct {
+	x, y uint
+}
+
+// InMemoryCrateRepository is a thread-safe in-memory implementation of ICrateRepository.
+// Crates on the floor are keyed by cell; crates being carried are keyed by robot ID
+// so their identity survives a grab/drop round trip.
+type InMemoryCrateRepository struct {
+	cells   map[cellKey]*model.Crate
+	carried map[string]*model.Crate
+	mu      sync.RWMutex // protects concurrent reads/writes to cells and carried
+}
+
+func NewInMemoryCrateRepository() ICrateRepository {
+	return &InMemoryCrateRepository{
+		cells:   make(map[cellKey]*model.Crate),
+		carried: make(map[string]*model.Crate),
+	}
+}
+
+// List returns a copy of every crate on the floor, ordered by (x, y).
+func (r *InMemoryCrateRepository) List() ([]*model.Crate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	crates := make([]*model.Crate, 0, len(r.cells))
+	for _, crate := range r.cells {
+		crateCopy := *crate
+		crates = append(crates, &crateCopy)
+	}
+
+	sort.Slice(crates, func(i, j int) bool {
+		if crates[i].X != crates[j].X {
+			return crates[i].X < crates[j].X
+		}
+		return crates[i].Y < crates[j].Y
+	})
+
+	return crates, nil
+}
+
+// GetByCell returns a copy of the crate on the cell, or nil if the cell is empty.
+func (r *InMemoryCrateRepository) GetByCell(x, y uint) (*model.Crate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	crate, exists := r.cells[cellKey{x, y}]
+	if !exists {
+		return nil, nil
+	}
+
+	crateCopy := *crate
+	return &crateCopy, nil
+}
+
+// Place stores a copy of the crate on its cell.
+func (r *InMemoryCrateRepository) Place(crate *model.Crate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := cellKey{crate.X, crate.Y}
+	if _, exists := r.cells[key]; exists {
+		return fmt.Errorf("cell (%d,%d) already holds a crate", crate.X, crate.Y)
+	}
+
+	crateCopy := *crate
+	r.cells[key] = &crateCopy
+
+	return nil
+}
+
+// Pick moves the crate on the cell into the robot's hands.
+func (r *InMemoryCrateRepository) Pick(robotID string, x, y uint) (*model.Crate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, holding := r.carried[robotID]; holding {
+		return nil, fmt.Errorf("robot %s already carries a crate", robotID)
+	}
+
+	key := cellKey{x, y}
+	crate, exists := r.cells[key]
+	if !exists {
+		return nil, fmt.Errorf("no crate at (%d,%d)", x, y)
+	}
+
+	delete(r.cells, key)
+	r.carried[robotID] = crate
+
+	crateCopy := *crate
+	return &crateCopy, nil
+}
+
+// Drop puts the robot's crate onto the cell.
+// A robot that started out carrying a crate the inventory never saw drops an
+// anonymous crate.
+func (r *InMemoryCrateRepository) Drop(robotID string, x, y uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := cellKey{x, y}
+	if _, exists := r.cells[key]; exists {
+		return fmt.Errorf("cell (%d,%d) already holds a crate", x, y)
+	}
+
+	crate, holding := r.carried[robotID]
+	if !holding {
+		crate = &model.Crate{}
+	}
+
+	delete(r.carried, robotID)
+	crate.X, crate.Y = x, y
+	r.cells[key] = crate
+
+	return nil
+}
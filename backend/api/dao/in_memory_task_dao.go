@@ -1,23 +1,34 @@
 package dao
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
+	"sort"
 	"sync"
 	"time"
 	"warehouse-robots/backend/api/model"
 )
 
 // InMemoryTaskRepository is a thread-safe in-memory implementation of ITaskRepository.
-// It uses a sync.RWMutex to protect concurrent access to the tasks map.
+// Every change is appended to the task's history; the tasks map is the
+// projection of those events, kept up to date as they are appended.
+// It uses a sync.RWMutex to protect concurrent access to the history, the tasks map and the outbox.
 // This should be replaced with a real database in prod.
 type InMemoryTaskRepository struct {
-	tasks map[string]*model.Task
-	mu    sync.RWMutex // protects concurrent reads/writes to tasks
+	tasks      map[string]*model.Task
+	history    map[string][]*model.TaskHistoryEvent
+	steps      map[string]int       // position samples recorded per task
+	outbox     []*model.OutboxEntry // pending entries, oldest first
+	lastOutbox int64
+	mu         sync.RWMutex // protects concurrent reads/writes to all fields
 }
 
 func NewInMemoryTaskRepository() ITaskRepository {
 	return &InMemoryTaskRepository{
-		tasks: make(map[string]*model.Task),
+		tasks:   make(map[string]*model.Task),
+		history: make(map[string][]*model.TaskHistoryEvent),
+		steps:   make(map[string]int),
 	}
 }
 
@@ -32,9 +43,12 @@ func (r *InMemoryTaskRepository) Create(task *model.Task) error {
 		return fmt.Errorf("task %s already exists", task.TaskID)
 	}
 
-	// Store a copy to avoid external modifications
-	taskCopy := *task
-	r.tasks[task.TaskID] = &taskCopy
+	r.append(&model.TaskHistoryEvent{
+		TaskID:   task.TaskID,
+		Type:     model.TaskHistoryCreated,
+		At:       time.Now(),
+		Snapshot: task.Clone(),
+	})
 
 	return nil
 }
@@ -46,17 +60,11 @@ func (r *InMemoryTaskRepository) GetById(taskID string) (*model.Task, error) {
 
 	task, exists := r.tasks[taskID]
 	if !exists {
-		return nil, fmt.Errorf("task %s not found", taskID)
+		return nil, fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
 	}
 
 	// Return a copy to avoid race conditions
-	taskCopy := *task
-	if task.CurrentPosition != nil {
-		posCopy := *task.CurrentPosition
-		taskCopy.CurrentPosition = &posCopy
-	}
-
-	return &taskCopy, nil
+	return task.Clone(), nil
 }
 
 // Update replaces an existing task with the provided one.
@@ -66,12 +74,16 @@ func (r *InMemoryTaskRepository) Update(task *model.Task) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.tasks[task.TaskID]; !exists {
-		return fmt.Errorf("task %s not found", task.TaskID)
+		return fmt.Errorf("task %s: %w", task.TaskID, model.ErrTaskNotFound)
 	}
 
 	task.UpdatedAt = time.Now()
-	taskCopy := *task
-	r.tasks[task.TaskID] = &taskCopy
+	r.append(&model.TaskHistoryEvent{
+		TaskID:   task.TaskID,
+		Type:     model.TaskHistoryReplaced,
+		At:       task.UpdatedAt,
+		Snapshot: task.Clone(),
+	})
 
 	return nil
 }
@@ -84,15 +96,31 @@ func (r *InMemoryTaskRepository) GetByRobotId(robotID string) ([]*model.Task, er
 	var tasks []*model.Task
 	for _, task := range r.tasks {
 		if task.RobotID == robotID {
-			taskCopy := *task
-			if task.CurrentPosition != nil {
-				posCopy := *task.CurrentPosition
-				taskCopy.CurrentPosition = &posCopy
-			}
-			tasks = append(tasks, &taskCopy)
+			tasks = append(tasks, task.Clone())
+		}
+	}
+
+	return tasks, nil
+}
+
+// GetByStatus returns the tasks with the given status, oldest first.
+func (r *InMemoryTaskRepository) GetByStatus(status model.TaskStatus) ([]*model.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tasks []*model.Task
+	for _, task := range r.tasks {
+		if task.Status == status {
+			tasks = append(tasks, task.Clone())
 		}
 	}
 
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].TaskID < tasks[j].TaskID
+	})
 	return tasks, nil
 }
 
@@ -103,14 +131,21 @@ func (r *InMemoryTaskRepository) UpdateStatus(taskID string, status model.TaskSt
 
 	task, exists := r.tasks[taskID]
 	if !exists {
-		return fmt.Errorf("task %s not found", taskID)
+		return fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
 	}
 
-	task.Status = status
-	if errorMsg != "" {
-		task.Error = errorMsg
+	changed := task.Status != status
+	task = r.append(&model.TaskHistoryEvent{
+		TaskID: taskID,
+		Type:   model.TaskHistoryStatus,
+		At:     time.Now(),
+		Status: status,
+		Error:  errorMsg,
+	})
+
+	if event := model.OutboxEventFor(status); event != "" && changed {
+		r.appendOutbox(event, task)
 	}
-	task.UpdatedAt = time.Now()
 
 	return nil
 }
@@ -120,14 +155,111 @@ func (r *InMemoryTaskRepository) UpdatePosition(taskID string, position *model.P
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	task, exists := r.tasks[taskID]
+	if _, exists := r.tasks[taskID]; !exists {
+		return fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
+	}
+
+	event := &model.TaskHistoryEvent{
+		TaskID: taskID,
+		Type:   model.TaskHistoryPosition,
+		At:     time.Now(),
+		Step:   r.steps[taskID],
+		Status: status,
+	}
+	if position != nil {
+		posCopy := *position
+		event.Position = &posCopy
+	}
+	r.steps[taskID]++
+	task := r.append(event)
+
+	r.appendOutbox(model.EventTaskPositionUpdated, task)
+
+	return nil
+}
+
+// History returns copies of the task's events, oldest first.
+func (r *InMemoryTaskRepository) History(taskID string) ([]*model.TaskHistoryEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events, exists := r.history[taskID]
 	if !exists {
-		return fmt.Errorf("task %s not found", taskID)
+		return nil, fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
 	}
 
-	task.CurrentPosition = position
-	task.Status = status
-	task.UpdatedAt = time.Now()
+	history := make([]*model.TaskHistoryEvent, 0, len(events))
+	for _, event := range events {
+		eventCopy := *event
+		if event.Snapshot != nil {
+			eventCopy.Snapshot = event.Snapshot.Clone()
+		}
+		if event.Position != nil {
+			posCopy := *event.Position
+			eventCopy.Position = &posCopy
+		}
+		history = append(history, &eventCopy)
+	}
+	return history, nil
+}
+
+// append numbers the event, adds it to the task's history and applies it to
+// the projection, which it returns; r.mu must be held.
+func (r *InMemoryTaskRepository) append(event *model.TaskHistoryEvent) *model.Task {
+	events := r.history[event.TaskID]
+	event.Seq = int64(len(events)) + 1
+	r.history[event.TaskID] = append(events, event)
+
+	task := event.Apply(r.tasks[event.TaskID])
+	r.tasks[event.TaskID] = task
+	return task
+}
+
+// appendOutbox records an outbox entry describing the task as it is now; r.mu must be held.
+func (r *InMemoryTaskRepository) appendOutbox(event string, task *model.Task) {
+	r.lastOutbox++
+	entry := &model.OutboxEntry{
+		ID:        r.lastOutbox,
+		Event:     event,
+		TaskID:    task.TaskID,
+		RobotID:   task.RobotID,
+		Status:    task.Status,
+		Error:     task.Error,
+		CreatedAt: task.UpdatedAt,
+	}
+	if task.CurrentPosition != nil {
+		posCopy := *task.CurrentPosition
+		entry.Position = &posCopy
+	}
+	r.outbox = append(r.outbox, entry)
+}
+
+// PendingOutbox returns copies of up to limit undelivered entries, oldest first.
+func (r *InMemoryTaskRepository) PendingOutbox(limit int) ([]*model.OutboxEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var entries []*model.OutboxEntry
+	for _, entry := range r.outbox {
+		if len(entries) == limit {
+			break
+		}
+		entryCopy := *entry
+		entries = append(entries, &entryCopy)
+	}
+	return entries, nil
+}
 
+// MarkOutboxDelivered removes the delivered entry, so the outbox only holds
+// what is still owed.
+func (r *InMemoryTaskRepository) MarkOutboxDelivered(id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, found := sort.Find(len(r.outbox), func(i int) int { return cmp.Compare(id, r.outbox[i].ID) })
+	if !found {
+		return fmt.Errorf("outbox entry %d not found", id)
+	}
+	r.outbox = slices.Delete(r.outbox, i, i+1)
 	return nil
 }
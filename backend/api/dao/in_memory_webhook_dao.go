@@ -0,0 +1,121 @@
+package dao
+
+import (
+	"fmt"
+	"sync"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// InMemoryWebhookRepository is a thread-safe in-memory implementation of IWebhookRepository.
+// Webhooks and their delivery attempts are stored as copies, so callers cannot
+// mutate what was recorded. Everything is lost on restart; see
+// SQLiteWebhookRepository for a store that keeps them.
+type InMemoryWebhookRepository struct {
+	webhooks map[string]*model.Webhook
+	order    []string // registration order, for stable listings
+	attempts map[string][]model.WebhookAttempt
+	deleted  map[string]bool // IDs of deleted webhooks, which stay taken
+	mu       sync.RWMutex    // protects concurrent reads/writes to all fields
+}
+
+func NewInMemoryWebhookRepository() IWebhookRepository {
+	return &InMemoryWebhookRepository{
+		webhooks: make(map[string]*model.Webhook),
+		attempts: make(map[string][]model.WebhookAttempt),
+		deleted:  make(map[string]bool),
+	}
+}
+
+// Create stores a copy of the webhook.
+func (r *InMemoryWebhookRepository) Create(webhook *model.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[webhook.ID]; exists || r.deleted[webhook.ID] {
+		return fmt.Errorf("%w: %s", ErrWebhookExists, webhook.ID)
+	}
+
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	r.order = append(r.order, webhook.ID)
+	return nil
+}
+
+// GetById returns a copy of the webhook.
+func (r *InMemoryWebhookRepository) GetById(webhookID string) (*model.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, exists := r.webhooks[webhookID]
+	if !exists {
+		return nil, model.ErrWebhookNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+// List returns copies of every webhook in registration order.
+func (r *InMemoryWebhookRepository) List() ([]*model.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*model.Webhook, 0, len(r.order))
+	for _, id := range r.order {
+		if webhook, exists := r.webhooks[id]; exists {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	return webhooks, nil
+}
+
+// Delete removes the webhook; its delivery log stays available.
+func (r *InMemoryWebhookRepository) Delete(webhookID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[webhookID]; !exists {
+		return model.ErrWebhookNotFound
+	}
+	delete(r.webhooks, webhookID)
+	r.deleted[webhookID] = true
+	for i, id := range r.order {
+		if id == webhookID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// RecordAttempt appends a copy of the attempt to the webhook's log, dropping
+// the oldest attempts beyond constant.WebhookAttemptHistory.
+func (r *InMemoryWebhookRepository) RecordAttempt(attempt *model.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts := append(r.attempts[attempt.WebhookID], *attempt)
+	if excess := len(attempts) - constant.WebhookAttemptHistory; excess > 0 {
+		attempts = append([]model.WebhookAttempt(nil), attempts[excess:]...)
+	}
+	r.attempts[attempt.WebhookID] = attempts
+	return nil
+}
+
+// ListAttempts returns copies of the webhook's delivery attempts, oldest first.
+func (r *InMemoryWebhookRepository) ListAttempts(webhookID string) ([]*model.WebhookAttempt, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attempts := make([]*model.WebhookAttempt, 0, len(r.attempts[webhookID]))
+	for _, attempt := range r.attempts[webhookID] {
+		attemptCopy := attempt
+		attempts = append(attempts, &attemptCopy)
+	}
+	return attempts, nil
+}
+
+// copyWebhook returns a deep copy of the webhook.
+func copyWebhook(webhook *model.Webhook) *model.Webhook {
+	webhookCopy := *webhook
+	webhookCopy.Events = append([]string(nil), webhook.Events...)
+	return &webhookCopy
+}
@@ -0,0 +1,154 @@
+package dao
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// sqliteMigrations are applied in order, each in its own transaction, and
+// recorded in schema_migrations. Append new migrations; never edit one that
+// has shipped.
+var sqliteMigrations = []string{
+	// 1: tasks
+	`CREATE TABLE tasks (
+		task_id                 TEXT PRIMARY KEY,
+		robot_id                TEXT NOT NULL,
+		commands                TEXT NOT NULL,
+		target_x                INTEGER,
+		target_y                INTEGER,
+		sdk_task_id             TEXT NOT NULL DEFAULT '',
+		planned_start_x         INTEGER,
+		planned_start_y         INTEGER,
+		planned_start_has_crate INTEGER,
+		status                  TEXT NOT NULL,
+		position_x              INTEGER,
+		position_y              INTEGER,
+		position_has_crate      INTEGER,
+		error                   TEXT NOT NULL DEFAULT '',
+		created_at              INTEGER NOT NULL,
+		updated_at              INTEGER NOT NULL
+	);
+	CREATE INDEX idx_tasks_robot_id ON tasks (robot_id);
+	CREATE INDEX idx_tasks_updated_at ON tasks (updated_at);`,
+
+	// 2: outbox of task lifecycle events
+	`CREATE TABLE task_outbox (
+		id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+		event              TEXT NOT NULL,
+		task_id            TEXT NOT NULL,
+		robot_id           TEXT NOT NULL,
+		status             TEXT NOT NULL,
+		error              TEXT NOT NULL DEFAULT '',
+		position_x         INTEGER,
+		position_y         INTEGER,
+		position_has_crate INTEGER,
+		created_at         INTEGER NOT NULL,
+		delivered_at       INTEGER
+	);
+	CREATE INDEX idx_task_outbox_pending ON task_outbox (id) WHERE delivered_at IS NULL;`,
+
+	// 3: append-only task history; tasks becomes its projection. Tasks stored
+	// before this migration start their history with a created event holding
+	// the task as it was then.
+	`CREATE TABLE task_events (
+		task_id                 TEXT NOT NULL,
+		seq                     INTEGER NOT NULL,
+		type                    TEXT NOT NULL,
+		at                      INTEGER NOT NULL,
+		step                    INTEGER,
+		robot_id                TEXT,
+		commands                TEXT,
+		target_x                INTEGER,
+		target_y                INTEGER,
+		sdk_task_id             TEXT,
+		planned_start_x         INTEGER,
+		planned_start_y         INTEGER,
+		planned_start_has_crate INTEGER,
+		status                  TEXT NOT NULL DEFAULT '',
+		position_x              INTEGER,
+		position_y              INTEGER,
+		position_has_crate      INTEGER,
+		error                   TEXT NOT NULL DEFAULT '',
+		created_at              INTEGER,
+		updated_at              INTEGER,
+		PRIMARY KEY (task_id, seq)
+	);
+	INSERT INTO task_events (task_id, seq, type, at, robot_id, commands, target_x, target_y, sdk_task_id,
+		planned_start_x, planned_start_y, planned_start_has_crate, status,
+		position_x, position_y, position_has_crate, error, created_at, updated_at)
+	SELECT task_id, 1, 'created', updated_at, robot_id, commands, target_x, target_y, sdk_task_id,
+		planned_start_x, planned_start_y, planned_start_has_crate, status,
+		position_x, position_y, position_has_crate, error, created_at, updated_at
+	FROM tasks;`,
+
+	// 4: webhooks and their delivery logs. A deleted webhook keeps its row,
+	// marked deleted_at, so its ID stays taken and its log stays readable.
+	`CREATE TABLE webhooks (
+		webhook_id TEXT PRIMARY KEY,
+		url        TEXT NOT NULL,
+		secret     TEXT NOT NULL,
+		events     TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		deleted_at INTEGER
+	);
+	CREATE TABLE webhook_attempts (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id  TEXT NOT NULL,
+		delivery_id TEXT NOT NULL,
+		task_id     TEXT NOT NULL,
+		event       TEXT NOT NULL,
+		attempt     INTEGER NOT NULL,
+		status_code INTEGER NOT NULL,
+		error       TEXT NOT NULL DEFAULT '',
+		succeeded   INTEGER NOT NULL,
+		duration    INTEGER NOT NULL,
+		at          INTEGER NOT NULL
+	);
+	CREATE INDEX idx_webhook_attempts_webhook ON webhook_attempts (webhook_id, id);`,
+
+	// 5: delivered outbox entries are deleted rather than kept; drop the ones
+	// marked delivered before
+	`DELETE FROM task_outbox WHERE delivered_at IS NOT NULL;`,
+}
+
+// migrateSQLite brings the schema up to date.
+func migrateSQLite(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at INTEGER NOT NULL DEFAULT (unixepoch())
+	)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if current > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than this build (%d)", current, len(sqliteMigrations))
+	}
+
+	for version := current + 1; version <= len(sqliteMigrations); version++ {
+		if err := applySQLiteMigration(db, version); err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// applySQLiteMigration runs one migration and records it in the same transaction.
+func applySQLiteMigration(db *sql.DB, version int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(sqliteMigrations[version-1]); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,487 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+	"warehouse-robots/backend/api/model"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registered as "sqlite"
+)
+
+// SQLiteTaskRepository is an ITaskRepository stored in a SQLite database, so
+// the task history survives a restart. Every change is appended to
+// task_events, and the tasks table holds the projection of those events,
+// written in the same transaction. The schema is migrated when the repository
+// is opened. Writes go through a single connection, which SQLite serialises
+// anyway, so a change, its history event and its outbox entry commit together.
+type SQLiteTaskRepository struct {
+	db *sql.DB
+}
+
+// taskColumns lists the tasks columns in the order scanTask reads them.
+const taskColumns = `task_id, robot_id, commands, target_x, target_y, sdk_task_id,
+	planned_start_x, planned_start_y, planned_start_has_crate, status,
+	position_x, position_y, position_has_crate, error, created_at, updated_at`
+
+// NewSQLiteTaskRepository opens (or creates) the database at path and
+// migrates it. ":memory:" gives a private in-memory database, e.g. for tests.
+func NewSQLiteTaskRepository(path string) (*SQLiteTaskRepository, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	// one connection: an in-memory database exists per connection, and SQLite
+	// only has one writer at a time anyway
+	db.SetMaxOpenConns(1)
+
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	return &SQLiteTaskRepository{db: db}, nil
+}
+
+// Close closes the database.
+func (r *SQLiteTaskRepository) Close() error {
+	return r.db.Close()
+}
+
+// Create inserts the task and starts its history, failing if the taskID already exists.
+func (r *SQLiteTaskRepository) Create(task *model.Task) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		targetX, targetY := cellColumns(task.Target)
+		startX, startY, startCrate := positionColumns(task.PlannedStart)
+		posX, posY, posCrate := positionColumns(task.CurrentPosition)
+
+		result, err := tx.Exec(`INSERT INTO tasks (`+taskColumns+`)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (task_id) DO NOTHING`,
+			task.TaskID, task.RobotID, task.Commands, targetX, targetY, task.SDKTaskID,
+			startX, startY, startCrate, task.Status,
+			posX, posY, posCrate, task.Error, task.CreatedAt.UnixNano(), task.UpdatedAt.UnixNano())
+		if err != nil {
+			return fmt.Errorf("create task %s: %w", task.TaskID, err)
+		}
+		if inserted, _ := result.RowsAffected(); inserted == 0 {
+			return fmt.Errorf("task %s already exists", task.TaskID)
+		}
+
+		return insertEvent(tx, &model.TaskHistoryEvent{
+			TaskID:   task.TaskID,
+			Seq:      1,
+			Type:     model.TaskHistoryCreated,
+			At:       time.Now(),
+			Snapshot: task,
+		})
+	})
+}
+
+// GetById retrieves a task by ID.
+func (r *SQLiteTaskRepository) GetById(taskID string) (*model.Task, error) {
+	task, err := scanTask(r.db.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE task_id = ?`, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get task %s: %w", taskID, err)
+	}
+	return task, nil
+}
+
+// GetByRobotId returns all tasks belonging to a given robot, oldest first.
+func (r *SQLiteTaskRepository) GetByRobotId(robotID string) ([]*model.Task, error) {
+	rows, err := r.db.Query(`SELECT `+taskColumns+` FROM tasks WHERE robot_id = ?
+		ORDER BY created_at, task_id`, robotID)
+	if err != nil {
+		return nil, fmt.Errorf("get tasks of robot %s: %w", robotID, err)
+	}
+	defer rows.Close()
+
+	var tasks []*model.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get tasks of robot %s: %w", robotID, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// GetByStatus returns the tasks with the given status, oldest first.
+func (r *SQLiteTaskRepository) GetByStatus(status model.TaskStatus) ([]*model.Task, error) {
+	rows, err := r.db.Query(`SELECT `+taskColumns+` FROM tasks WHERE status = ?
+		ORDER BY created_at, task_id`, string(status))
+	if err != nil {
+		return nil, fmt.Errorf("get %s tasks: %w", status, err)
+	}
+	defer rows.Close()
+
+	var tasks []*model.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get %s tasks: %w", status, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// Update replaces an existing task with the provided one.
+// The task must already exist in the repository.
+func (r *SQLiteTaskRepository) Update(task *model.Task) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		if _, err := getTask(tx, task.TaskID); err != nil {
+			return err
+		}
+
+		task.UpdatedAt = time.Now()
+		_, err := appendEvent(tx, nil, &model.TaskHistoryEvent{
+			TaskID:   task.TaskID,
+			Type:     model.TaskHistoryReplaced,
+			At:       task.UpdatedAt,
+			Snapshot: task,
+		})
+		return err
+	})
+}
+
+// UpdateStatus updates the status of an existing task, recording the
+// lifecycle event in the outbox in the same transaction.
+func (r *SQLiteTaskRepository) UpdateStatus(taskID string, status model.TaskStatus, errorMsg string) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		task, err := getTask(tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		changed := task.Status != status
+		task, err = appendEvent(tx, task, &model.TaskHistoryEvent{
+			TaskID: taskID,
+			Type:   model.TaskHistoryStatus,
+			At:     time.Now(),
+			Status: status,
+			Error:  errorMsg,
+		})
+		if err != nil {
+			return err
+		}
+
+		if event := model.OutboxEventFor(status); event != "" && changed {
+			return insertOutbox(tx, event, task)
+		}
+		return nil
+	})
+}
+
+// UpdatePosition updates the current position of the task and its status,
+// recording a TaskPositionUpdated entry in the outbox in the same transaction.
+func (r *SQLiteTaskRepository) UpdatePosition(taskID string, position *model.Position, status model.TaskStatus) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		task, err := getTask(tx, taskID)
+		if err != nil {
+			return err
+		}
+
+		var step int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM task_events WHERE task_id = ? AND type = ?`,
+			taskID, model.TaskHistoryPosition).Scan(&step); err != nil {
+			return err
+		}
+
+		task, err = appendEvent(tx, task, &model.TaskHistoryEvent{
+			TaskID:   taskID,
+			Type:     model.TaskHistoryPosition,
+			At:       time.Now(),
+			Step:     step,
+			Position: position,
+			Status:   status,
+		})
+		if err != nil {
+			return err
+		}
+		return insertOutbox(tx, model.EventTaskPositionUpdated, task)
+	})
+}
+
+// History returns the task's events, oldest first.
+func (r *SQLiteTaskRepository) History(taskID string) ([]*model.TaskHistoryEvent, error) {
+	rows, err := r.db.Query(`SELECT `+eventColumns+` FROM task_events WHERE task_id = ? ORDER BY seq`, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("get history of task %s: %w", taskID, err)
+	}
+	defer rows.Close()
+
+	var events []*model.TaskHistoryEvent
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("get history of task %s: %w", taskID, err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get history of task %s: %w", taskID, err)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
+	}
+	return events, nil
+}
+
+// PendingOutbox returns up to limit undelivered entries, oldest first.
+func (r *SQLiteTaskRepository) PendingOutbox(limit int) ([]*model.OutboxEntry, error) {
+	rows, err := r.db.Query(`SELECT id, event, task_id, robot_id, status, error,
+		position_x, position_y, position_has_crate, created_at
+		FROM task_outbox WHERE delivered_at IS NULL ORDER BY id LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*model.OutboxEntry
+	for rows.Next() {
+		var (
+			entry          model.OutboxEntry
+			posX, posY     sql.NullInt64
+			posCrate       sql.NullBool
+			createdAtNanos int64
+		)
+		if err := rows.Scan(&entry.ID, &entry.Event, &entry.TaskID, &entry.RobotID, &entry.Status, &entry.Error,
+			&posX, &posY, &posCrate, &createdAtNanos); err != nil {
+			return nil, fmt.Errorf("read outbox: %w", err)
+		}
+		entry.Position = positionFromColumns(posX, posY, posCrate)
+		entry.CreatedAt = time.Unix(0, createdAtNanos)
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// MarkOutboxDelivered deletes the delivered entry, so the table only holds
+// what is still owed.
+func (r *SQLiteTaskRepository) MarkOutboxDelivered(id int64) error {
+	result, err := r.db.Exec(`DELETE FROM task_outbox WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("mark outbox entry %d delivered: %w", id, err)
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return fmt.Errorf("outbox entry %d not found", id)
+	}
+	return nil
+}
+
+// inTx runs fn in a transaction, committing if it returns nil.
+func (r *SQLiteTaskRepository) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getTask reads the projection of a task within the transaction.
+func getTask(tx *sql.Tx, taskID string) (*model.Task, error) {
+	task, err := scanTask(tx.QueryRow(`SELECT `+taskColumns+` FROM tasks WHERE task_id = ?`, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("task %s: %w", taskID, model.ErrTaskNotFound)
+	}
+	return task, err
+}
+
+// appendEvent numbers the event, appends it to the task's history and writes
+// the projection it results in, which it returns.
+func appendEvent(tx *sql.Tx, task *model.Task, event *model.TaskHistoryEvent) (*model.Task, error) {
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(seq), 0) + 1 FROM task_events WHERE task_id = ?`,
+		event.TaskID).Scan(&event.Seq); err != nil {
+		return nil, err
+	}
+	if err := insertEvent(tx, event); err != nil {
+		return nil, err
+	}
+
+	task = event.Apply(task)
+	targetX, targetY := cellColumns(task.Target)
+	startX, startY, startCrate := positionColumns(task.PlannedStart)
+	posX, posY, posCrate := positionColumns(task.CurrentPosition)
+
+	_, err := tx.Exec(`UPDATE tasks SET robot_id = ?, commands = ?, target_x = ?, target_y = ?,
+		sdk_task_id = ?, planned_start_x = ?, planned_start_y = ?, planned_start_has_crate = ?,
+		status = ?, position_x = ?, position_y = ?, position_has_crate = ?, error = ?,
+		created_at = ?, updated_at = ?
+		WHERE task_id = ?`,
+		task.RobotID, task.Commands, targetX, targetY,
+		task.SDKTaskID, startX, startY, startCrate,
+		task.Status, posX, posY, posCrate, task.Error,
+		task.CreatedAt.UnixNano(), task.UpdatedAt.UnixNano(),
+		task.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("project task %s: %w", task.TaskID, err)
+	}
+	return task, nil
+}
+
+// eventColumns lists the task_events columns in the order scanEvent reads them.
+const eventColumns = `task_id, seq, type, at, step, robot_id, commands, target_x, target_y, sdk_task_id,
+	planned_start_x, planned_start_y, planned_start_has_crate, status,
+	position_x, position_y, position_has_crate, error, created_at, updated_at`
+
+// insertEvent writes a numbered history event.
+func insertEvent(tx *sql.Tx, event *model.TaskHistoryEvent) error {
+	var (
+		step                 sql.NullInt64
+		robotID, commands    sql.NullString
+		sdkTaskID            sql.NullString
+		targetX, targetY     sql.NullInt64
+		startX, startY       sql.NullInt64
+		startCrate           sql.NullBool
+		createdAt, updatedAt sql.NullInt64
+	)
+	status, errorMsg, position := event.Status, event.Error, event.Position
+	if snapshot := event.Snapshot; snapshot != nil {
+		robotID = sql.NullString{String: snapshot.RobotID, Valid: true}
+		commands = sql.NullString{String: snapshot.Commands, Valid: true}
+		sdkTaskID = sql.NullString{String: snapshot.SDKTaskID, Valid: true}
+		targetX, targetY = cellColumns(snapshot.Target)
+		startX, startY, startCrate = positionColumns(snapshot.PlannedStart)
+		createdAt = sql.NullInt64{Int64: snapshot.CreatedAt.UnixNano(), Valid: true}
+		updatedAt = sql.NullInt64{Int64: snapshot.UpdatedAt.UnixNano(), Valid: true}
+		status, errorMsg, position = snapshot.Status, snapshot.Error, snapshot.CurrentPosition
+	}
+	if event.Type == model.TaskHistoryPosition {
+		step = sql.NullInt64{Int64: int64(event.Step), Valid: true}
+	}
+	posX, posY, posCrate := positionColumns(position)
+
+	_, err := tx.Exec(`INSERT INTO task_events (`+eventColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.TaskID, event.Seq, event.Type, event.At.UnixNano(), step, robotID, commands, targetX, targetY, sdkTaskID,
+		startX, startY, startCrate, status,
+		posX, posY, posCrate, errorMsg, createdAt, updatedAt)
+	if err != nil {
+		return fmt.Errorf("record %s event of task %s: %w", event.Type, event.TaskID, err)
+	}
+	return nil
+}
+
+// scanEvent reads a row selected with eventColumns.
+func scanEvent(row rowScanner) (*model.TaskHistoryEvent, error) {
+	var (
+		event                      model.TaskHistoryEvent
+		atNanos                    int64
+		step                       sql.NullInt64
+		robotID, commands          sql.NullString
+		sdkTaskID                  sql.NullString
+		targetX, targetY           sql.NullInt64
+		startX, startY, posX, posY sql.NullInt64
+		startCrate, posCrate       sql.NullBool
+		createdAt, updatedAt       sql.NullInt64
+	)
+	if err := row.Scan(&event.TaskID, &event.Seq, &event.Type, &atNanos, &step, &robotID, &commands,
+		&targetX, &targetY, &sdkTaskID, &startX, &startY, &startCrate, &event.Status,
+		&posX, &posY, &posCrate, &event.Error, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	event.At = time.Unix(0, atNanos)
+	event.Position = positionFromColumns(posX, posY, posCrate)
+	event.Step = int(step.Int64)
+
+	if event.Type == model.TaskHistoryCreated || event.Type == model.TaskHistoryReplaced {
+		snapshot := &model.Task{
+			TaskID:          event.TaskID,
+			RobotID:         robotID.String,
+			Commands:        commands.String,
+			SDKTaskID:       sdkTaskID.String,
+			PlannedStart:    positionFromColumns(startX, startY, startCrate),
+			Status:          event.Status,
+			CurrentPosition: event.Position,
+			Error:           event.Error,
+			CreatedAt:       time.Unix(0, createdAt.Int64),
+			UpdatedAt:       time.Unix(0, updatedAt.Int64),
+		}
+		if targetX.Valid && targetY.Valid {
+			snapshot.Target = &model.Cell{X: uint(targetX.Int64), Y: uint(targetY.Int64)}
+		}
+		event.Snapshot = snapshot
+		event.Status, event.Error, event.Position = "", "", nil
+	}
+	return &event, nil
+}
+
+// insertOutbox records an outbox entry describing the task as it is now.
+func insertOutbox(tx *sql.Tx, event string, task *model.Task) error {
+	posX, posY, posCrate := positionColumns(task.CurrentPosition)
+	_, err := tx.Exec(`INSERT INTO task_outbox (event, task_id, robot_id, status, error,
+		position_x, position_y, position_has_crate, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		event, task.TaskID, task.RobotID, task.Status, task.Error,
+		posX, posY, posCrate, task.UpdatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("record %s of task %s in outbox: %w", event, task.TaskID, err)
+	}
+	return nil
+}
+
+// rowScanner is a *sql.Row or *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTask reads a row selected with taskColumns.
+func scanTask(row rowScanner) (*model.Task, error) {
+	var (
+		task                           model.Task
+		targetX, targetY               sql.NullInt64
+		startX, startY, posX, posY     sql.NullInt64
+		startCrate, posCrate           sql.NullBool
+		createdAtNanos, updatedAtNanos int64
+	)
+	if err := row.Scan(&task.TaskID, &task.RobotID, &task.Commands, &targetX, &targetY, &task.SDKTaskID,
+		&startX, &startY, &startCrate, &task.Status,
+		&posX, &posY, &posCrate, &task.Error, &createdAtNanos, &updatedAtNanos); err != nil {
+		return nil, err
+	}
+
+	if targetX.Valid && targetY.Valid {
+		task.Target = &model.Cell{X: uint(targetX.Int64), Y: uint(targetY.Int64)}
+	}
+	task.PlannedStart = positionFromColumns(startX, startY, startCrate)
+	task.CurrentPosition = positionFromColumns(posX, posY, posCrate)
+	task.CreatedAt = time.Unix(0, createdAtNanos)
+	task.UpdatedAt = time.Unix(0, updatedAtNanos)
+	return &task, nil
+}
+
+// cellColumns splits an optional cell into nullable columns.
+func cellColumns(cell *model.Cell) (x, y sql.NullInt64) {
+	if cell == nil {
+		return x, y
+	}
+	return sql.NullInt64{Int64: int64(cell.X), Valid: true}, sql.NullInt64{Int64: int64(cell.Y), Valid: true}
+}
+
+// positionColumns splits an optional position into nullable columns.
+func positionColumns(position *model.Position) (x, y sql.NullInt64, hasCrate sql.NullBool) {
+	if position == nil {
+		return x, y, hasCrate
+	}
+	return sql.NullInt64{Int64: int64(position.X), Valid: true},
+		sql.NullInt64{Int64: int64(position.Y), Valid: true},
+		sql.NullBool{Bool: position.HasCrate, Valid: true}
+}
+
+// positionFromColumns joins nullable columns back into an optional position.
+func positionFromColumns(x, y sql.NullInt64, hasCrate sql.NullBool) *model.Position {
+	if !x.Valid || !y.Valid {
+		return nil
+	}
+	return &model.Position{X: uint(x.Int64), Y: uint(y.Int64), HasCrate: hasCrate.Bool}
+}
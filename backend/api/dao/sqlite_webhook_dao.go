@@ -0,0 +1,153 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// SQLiteWebhookRepository is an IWebhookRepository stored in the database of a
+// SQLiteTaskRepository, so webhooks and their delivery logs survive a restart
+// along with the tasks. Closing the task repository closes it too.
+type SQLiteWebhookRepository struct {
+	db *sql.DB
+}
+
+// webhookColumns lists the webhooks columns in the order scanWebhook reads them.
+const webhookColumns = `webhook_id, url, secret, events, created_at`
+
+// NewSQLiteWebhookRepository stores webhooks in the task repository's database.
+func NewSQLiteWebhookRepository(tasks *SQLiteTaskRepository) *SQLiteWebhookRepository {
+	return &SQLiteWebhookRepository{db: tasks.db}
+}
+
+// Create inserts the webhook, failing with ErrWebhookExists if its ID is taken.
+func (r *SQLiteWebhookRepository) Create(webhook *model.Webhook) error {
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("encode events of webhook %s: %w", webhook.ID, err)
+	}
+
+	result, err := r.db.Exec(`INSERT INTO webhooks (`+webhookColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (webhook_id) DO NOTHING`,
+		webhook.ID, webhook.URL, webhook.Secret, string(events), webhook.CreatedAt.UnixNano())
+	if err != nil {
+		return fmt.Errorf("create webhook %s: %w", webhook.ID, err)
+	}
+	if inserted, _ := result.RowsAffected(); inserted == 0 {
+		return fmt.Errorf("%w: %s", ErrWebhookExists, webhook.ID)
+	}
+	return nil
+}
+
+// GetById returns the webhook, or ErrWebhookNotFound if it is unknown or deleted.
+func (r *SQLiteWebhookRepository) GetById(webhookID string) (*model.Webhook, error) {
+	webhook, err := scanWebhook(r.db.QueryRow(`SELECT `+webhookColumns+` FROM webhooks
+		WHERE webhook_id = ? AND deleted_at IS NULL`, webhookID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrWebhookNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook %s: %w", webhookID, err)
+	}
+	return webhook, nil
+}
+
+// List returns every webhook in registration order.
+func (r *SQLiteWebhookRepository) List() ([]*model.Webhook, error) {
+	rows, err := r.db.Query(`SELECT ` + webhookColumns + ` FROM webhooks
+		WHERE deleted_at IS NULL ORDER BY rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*model.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list webhooks: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// Delete marks the webhook deleted; its delivery log stays available.
+func (r *SQLiteWebhookRepository) Delete(webhookID string) error {
+	result, err := r.db.Exec(`UPDATE webhooks SET deleted_at = ?
+		WHERE webhook_id = ? AND deleted_at IS NULL`, time.Now().UnixNano(), webhookID)
+	if err != nil {
+		return fmt.Errorf("delete webhook %s: %w", webhookID, err)
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return model.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// RecordAttempt appends the attempt to the webhook's log and drops the oldest
+// attempts beyond constant.WebhookAttemptHistory, in one transaction.
+func (r *SQLiteWebhookRepository) RecordAttempt(attempt *model.WebhookAttempt) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO webhook_attempts (webhook_id, delivery_id, task_id, event,
+		attempt, status_code, error, succeeded, duration, at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		attempt.WebhookID, attempt.DeliveryID, attempt.TaskID, attempt.Event, attempt.Attempt,
+		attempt.StatusCode, attempt.Error, attempt.Succeeded, int64(attempt.Duration), attempt.At.UnixNano()); err != nil {
+		return fmt.Errorf("record attempt of webhook %s: %w", attempt.WebhookID, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM webhook_attempts WHERE webhook_id = ? AND id NOT IN (
+		SELECT id FROM webhook_attempts WHERE webhook_id = ? ORDER BY id DESC LIMIT ?)`,
+		attempt.WebhookID, attempt.WebhookID, constant.WebhookAttemptHistory); err != nil {
+		return fmt.Errorf("trim attempts of webhook %s: %w", attempt.WebhookID, err)
+	}
+	return tx.Commit()
+}
+
+// ListAttempts returns the webhook's delivery attempts, oldest first.
+func (r *SQLiteWebhookRepository) ListAttempts(webhookID string) ([]*model.WebhookAttempt, error) {
+	rows, err := r.db.Query(`SELECT webhook_id, delivery_id, task_id, event, attempt, status_code,
+		error, succeeded, duration, at FROM webhook_attempts WHERE webhook_id = ? ORDER BY id`, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("list attempts of webhook %s: %w", webhookID, err)
+	}
+	defer rows.Close()
+
+	attempts := make([]*model.WebhookAttempt, 0)
+	for rows.Next() {
+		var attempt model.WebhookAttempt
+		var duration, at int64
+		if err := rows.Scan(&attempt.WebhookID, &attempt.DeliveryID, &attempt.TaskID, &attempt.Event,
+			&attempt.Attempt, &attempt.StatusCode, &attempt.Error, &attempt.Succeeded, &duration, &at); err != nil {
+			return nil, fmt.Errorf("list attempts of webhook %s: %w", webhookID, err)
+		}
+		attempt.Duration = time.Duration(duration)
+		attempt.At = time.Unix(0, at)
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, rows.Err()
+}
+
+// scanWebhook reads a row selected with webhookColumns.
+func scanWebhook(row rowScanner) (*model.Webhook, error) {
+	var webhook model.Webhook
+	var events string
+	var createdAt int64
+	if err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &createdAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("decode events of webhook %s: %w", webhook.ID, err)
+	}
+	webhook.CreatedAt = time.Unix(0, createdAt)
+	return &webhook, nil
+}
@@ -15,12 +15,28 @@ type ITaskRepository interface {
 	// one robot could have multiple tasks
 	GetByRobotId(robotID string) ([]*model.Task, error)
 
+	// GetByStatus returns the tasks with the given status, oldest first
+	GetByStatus(status model.TaskStatus) ([]*model.Task, error)
+
 	// Update updates an existing task
 	Update(task *model.Task) error
 
-	// UpdatePosition updates only the position and status
+	// UpdatePosition updates only the position and status, recording a
+	// TaskPositionUpdated entry in the outbox in the same operation
 	UpdatePosition(taskID string, position *model.Position, status model.TaskStatus) error
 
-	// UpdateStatus updates only the status and error if any
+	// UpdateStatus updates only the status and error if any. A change to
+	// COMPLETED, FAILED or CANCELLED records the matching entry in the outbox
+	// in the same operation
 	UpdateStatus(taskID string, status model.TaskStatus, errorMsg string) error
+
+	// History returns the task's append-only history, oldest first. The task
+	// returned by GetById is the projection of these events (model.ReplayTask)
+	History(taskID string) ([]*model.TaskHistoryEvent, error)
+
+	// PendingOutbox returns up to limit undelivered outbox entries, oldest first
+	PendingOutbox(limit int) ([]*model.OutboxEntry, error)
+
+	// MarkOutboxDelivered marks the outbox entry as delivered and removes it
+	MarkOutboxDelivered(id int64) error
 }
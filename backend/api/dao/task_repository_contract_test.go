@@ -0,0 +1,388 @@
+package dao
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/model"
+)
+
+// Every ITaskRepository implementation runs the same contract.
+
+func TestInMemoryTaskRepository_Contract(t *testing.T) {
+	runTaskRepositoryContract(t, func(t *testing.T) ITaskRepository {
+		return NewInMemoryTaskRepository()
+	})
+}
+
+func TestSQLiteTaskRepository_Contract(t *testing.T) {
+	runTaskRepositoryContract(t, func(t *testing.T) ITaskRepository {
+		repo, err := NewSQLiteTaskRepository(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { repo.Close() })
+		return repo
+	})
+}
+
+func TestSQLiteTaskRepository_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	repo, err := NewSQLiteTaskRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = repo.Create(newContractTask("task_1", "robot"))
+	_ = repo.UpdatePosition("task_1", &model.Position{X: 3, Y: 4, HasCrate: true}, model.TaskStatusPending)
+	_ = repo.UpdateStatus("task_1", model.TaskStatusCompleted, "")
+	repo.Close()
+
+	// reopening runs the migrations again, which must be a no-op
+	repo, err = NewSQLiteTaskRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	tasks, err := repo.GetByRobotId("robot")
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("expected the task to survive, got %+v (%v)", tasks, err)
+	}
+	if got := tasks[0].CurrentPosition; got == nil || *got != (model.Position{X: 3, Y: 4, HasCrate: true}) {
+		t.Errorf("expected the last position (3,4) with a crate, got %+v", got)
+	}
+	if pending, _ := repo.PendingOutbox(10); len(pending) != 2 {
+		t.Errorf("expected the undelivered outbox entries to survive, got %d", len(pending))
+	}
+}
+
+func TestSQLiteTaskRepository_MigratesHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	// a database written before the task history existed
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at INTEGER NOT NULL DEFAULT (unixepoch()))`); err != nil {
+		t.Fatal(err)
+	}
+	for version := 1; version <= 2; version++ {
+		if err := applySQLiteMigration(db, version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO tasks (` + taskColumns + `)
+		VALUES ('task_1', 'robot', 'NN', NULL, NULL, '', 0, 0, 1, 'COMPLETED', 0, 2, 1, '', 1, 2)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	repo, err := NewSQLiteTaskRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer repo.Close()
+
+	events, err := repo.History("task_1")
+	if err != nil || len(events) != 1 || events[0].Type != model.TaskHistoryCreated {
+		t.Fatalf("expected the stored task to start its history, got %+v (%v)", events, err)
+	}
+	replayed := model.ReplayTask(events)
+	if replayed.Status != model.TaskStatusCompleted || replayed.CurrentPosition == nil || replayed.CurrentPosition.Y != 2 {
+		t.Errorf("expected the task as stored, got %+v", replayed)
+	}
+
+	// the history carries on from there
+	_ = repo.UpdatePosition("task_1", &model.Position{X: 0, Y: 3}, model.TaskStatusPending)
+	if events, _ := repo.History("task_1"); len(events) != 2 || events[1].Seq != 2 {
+		t.Errorf("expected the next event to be number 2, got %+v", events)
+	}
+}
+
+func newContractTask(taskID, robotID string) *model.Task {
+	now := time.Now()
+	return &model.Task{
+		TaskID:       taskID,
+		RobotID:      robotID,
+		Commands:     "NE",
+		Target:       &model.Cell{X: 1, Y: 1},
+		PlannedStart: &model.Position{X: 0, Y: 0},
+		Status:       model.TaskStatusPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+func runTaskRepositoryContract(t *testing.T, newRepo func(t *testing.T) ITaskRepository) {
+	t.Run("create_and_get", func(t *testing.T) {
+		repo := newRepo(t)
+		task := newContractTask("task_1", "robot")
+		if err := repo.Create(task); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Create(task); err == nil {
+			t.Error("expected creating the same task twice to fail")
+		}
+
+		got, err := repo.GetById("task_1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.RobotID != "robot" || got.Commands != "NE" || got.Status != model.TaskStatusPending ||
+			got.Target == nil || *got.Target != (model.Cell{X: 1, Y: 1}) ||
+			got.PlannedStart == nil || *got.PlannedStart != (model.Position{}) ||
+			got.CurrentPosition != nil || !got.CreatedAt.Equal(task.CreatedAt) {
+			t.Errorf("expected the stored task back, got %+v", got)
+		}
+
+		if _, err := repo.GetById("missing"); !errors.Is(err, model.ErrTaskNotFound) {
+			t.Errorf("expected ErrTaskNotFound for an unknown task, got %v", err)
+		}
+	})
+
+	t.Run("returns_copies", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+		_ = repo.UpdatePosition("task_1", &model.Position{X: 1}, model.TaskStatusPending)
+
+		got, _ := repo.GetById("task_1")
+		got.Status = model.TaskStatusFailed
+		got.CurrentPosition.X = 9
+
+		again, _ := repo.GetById("task_1")
+		if again.Status != model.TaskStatusPending || again.CurrentPosition.X != 1 {
+			t.Errorf("expected callers not to mutate the stored task, got %+v", again)
+		}
+	})
+
+	t.Run("get_by_robot", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+		_ = repo.Create(newContractTask("task_2", "other"))
+		_ = repo.Create(newContractTask("task_3", "robot"))
+
+		tasks, err := repo.GetByRobotId("robot")
+		if err != nil || len(tasks) != 2 {
+			t.Fatalf("expected the 2 tasks of robot, got %d (%v)", len(tasks), err)
+		}
+		for _, task := range tasks {
+			if task.RobotID != "robot" {
+				t.Errorf("expected only tasks of robot, got %+v", task)
+			}
+		}
+		if tasks, _ := repo.GetByRobotId("nobody"); len(tasks) != 0 {
+			t.Errorf("expected no tasks for an unknown robot, got %+v", tasks)
+		}
+	})
+
+	t.Run("get_by_status", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+		_ = repo.Create(newContractTask("task_2", "other"))
+		_ = repo.Create(newContractTask("task_3", "robot"))
+		_ = repo.UpdateStatus("task_2", model.TaskStatusCompleted, "")
+
+		tasks, err := repo.GetByStatus(model.TaskStatusPending)
+		if err != nil || len(tasks) != 2 || tasks[0].TaskID != "task_1" || tasks[1].TaskID != "task_3" {
+			t.Fatalf("expected task_1 and task_3, oldest first, got %+v (%v)", tasks, err)
+		}
+		if tasks, _ := repo.GetByStatus(model.TaskStatusFailed); len(tasks) != 0 {
+			t.Errorf("expected no FAILED tasks, got %+v", tasks)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		task := newContractTask("task_1", "robot")
+		_ = repo.Create(task)
+
+		task.SDKTaskID = "sdk_7"
+		task.Commands = "NEN"
+		before := task.UpdatedAt
+		if err := repo.Update(task); err != nil {
+			t.Fatal(err)
+		}
+		if task.UpdatedAt.Before(before) {
+			t.Error("expected Update to refresh UpdatedAt")
+		}
+		got, _ := repo.GetById("task_1")
+		if got.SDKTaskID != "sdk_7" || got.Commands != "NEN" || got.SDKID() != "sdk_7" {
+			t.Errorf("expected the updated task, got %+v", got)
+		}
+
+		if err := repo.Update(newContractTask("missing", "robot")); err == nil {
+			t.Error("expected updating an unknown task to fail")
+		}
+	})
+
+	t.Run("update_status", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+
+		if err := repo.UpdateStatus("task_1", model.TaskStatusFailed, "boom"); err != nil {
+			t.Fatal(err)
+		}
+		// an empty message keeps the previous one
+		_ = repo.UpdateStatus("task_1", model.TaskStatusFailed, "")
+		got, _ := repo.GetById("task_1")
+		if got.Status != model.TaskStatusFailed || got.Error != "boom" {
+			t.Errorf("expected FAILED with the error, got %+v", got)
+		}
+
+		if err := repo.UpdateStatus("missing", model.TaskStatusCompleted, ""); err == nil {
+			t.Error("expected updating an unknown task to fail")
+		}
+	})
+
+	t.Run("update_position", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+
+		if err := repo.UpdatePosition("task_1", &model.Position{X: 2, Y: 3, HasCrate: true}, model.TaskStatusPending); err != nil {
+			t.Fatal(err)
+		}
+		got, _ := repo.GetById("task_1")
+		if got.CurrentPosition == nil || *got.CurrentPosition != (model.Position{X: 2, Y: 3, HasCrate: true}) {
+			t.Errorf("expected the position (2,3) with a crate, got %+v", got.CurrentPosition)
+		}
+
+		if err := repo.UpdatePosition("missing", &model.Position{}, model.TaskStatusPending); err == nil {
+			t.Error("expected updating an unknown task to fail")
+		}
+	})
+
+	t.Run("outbox", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+		_ = repo.UpdatePosition("task_1", &model.Position{X: 1}, model.TaskStatusPending)
+		_ = repo.UpdateStatus("task_1", model.TaskStatusCancelled, "cancelled by user")
+		_ = repo.UpdateStatus("task_1", model.TaskStatusCancelled, "")
+
+		pending, err := repo.PendingOutbox(10)
+		if err != nil || len(pending) != 2 {
+			t.Fatalf("expected a position and a cancellation entry, got %+v (%v)", pending, err)
+		}
+		position, cancelled := pending[0], pending[1]
+		if position.Event != model.EventTaskPositionUpdated || position.Position == nil || position.Position.X != 1 {
+			t.Errorf("expected the position entry first, got %+v", position)
+		}
+		if cancelled.Event != model.EventTaskCancelled || cancelled.RobotID != "robot" ||
+			cancelled.Error != "cancelled by user" || cancelled.ID <= position.ID {
+			t.Errorf("expected the cancellation entry second, got %+v", cancelled)
+		}
+
+		if limited, _ := repo.PendingOutbox(1); len(limited) != 1 || limited[0].ID != position.ID {
+			t.Errorf("expected the limit to keep the oldest entry, got %+v", limited)
+		}
+
+		if err := repo.MarkOutboxDelivered(cancelled.ID); err != nil {
+			t.Fatal(err)
+		}
+		if pending, _ := repo.PendingOutbox(10); len(pending) != 1 || pending[0].ID != position.ID {
+			t.Errorf("expected only the position entry pending, got %+v", pending)
+		}
+		if err := repo.MarkOutboxDelivered(position.ID); err != nil {
+			t.Fatal(err)
+		}
+		if pending, _ := repo.PendingOutbox(10); len(pending) != 0 {
+			t.Errorf("expected nothing pending, got %+v", pending)
+		}
+	})
+
+	t.Run("outbox_delivered_removed", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractTask("task_1", "robot"))
+		for x := uint(1); x <= 3; x++ {
+			_ = repo.UpdatePosition("task_1", &model.Position{X: x}, model.TaskStatusPending)
+		}
+		pending, _ := repo.PendingOutbox(10)
+		if len(pending) != 3 || outboxSize(t, repo) != 3 {
+			t.Fatalf("expected three entries, got %+v", pending)
+		}
+
+		// delivered out of order: the entry in the middle goes first
+		if err := repo.MarkOutboxDelivered(pending[1].ID); err != nil {
+			t.Fatal(err)
+		}
+		if size := outboxSize(t, repo); size != 2 {
+			t.Errorf("expected the delivered entry removed, %d entries left", size)
+		}
+		if err := repo.MarkOutboxDelivered(pending[1].ID); err == nil {
+			t.Error("expected a removed entry to be unknown")
+		}
+
+		_ = repo.MarkOutboxDelivered(pending[0].ID)
+		_ = repo.MarkOutboxDelivered(pending[2].ID)
+		if size := outboxSize(t, repo); size != 0 {
+			t.Errorf("expected an empty outbox, %d entries left", size)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		repo := newRepo(t)
+		task := newContractTask("task_1", "robot")
+		_ = repo.Create(task)
+		_ = repo.UpdatePosition("task_1", &model.Position{X: 0, Y: 0}, model.TaskStatusPending)
+		_ = repo.UpdatePosition("task_1", &model.Position{X: 0, Y: 1, HasCrate: true}, model.TaskStatusPending)
+		stored, _ := repo.GetById("task_1")
+		stored.SDKTaskID = "sdk_2"
+		_ = repo.Update(stored)
+		_ = repo.UpdateStatus("task_1", model.TaskStatusFailed, "blocked")
+
+		events, err := repo.History("task_1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		types := []model.TaskHistoryType{model.TaskHistoryCreated, model.TaskHistoryPosition,
+			model.TaskHistoryPosition, model.TaskHistoryReplaced, model.TaskHistoryStatus}
+		if len(events) != len(types) {
+			t.Fatalf("expected %d events, got %d", len(types), len(events))
+		}
+		for i, event := range events {
+			if event.Seq != int64(i+1) || event.Type != types[i] || event.At.IsZero() {
+				t.Errorf("expected event %d to be %s, got %+v", i+1, types[i], event)
+			}
+		}
+		if second := events[2]; second.Step != 1 || second.Position == nil || !second.Position.HasCrate {
+			t.Errorf("expected the second sample at step 1 with a crate, got %+v", second)
+		}
+		if failed := events[4]; failed.Status != model.TaskStatusFailed || failed.Error != "blocked" {
+			t.Errorf("expected the transition to FAILED, got %+v", failed)
+		}
+
+		// the stored task is the projection of its history
+		replayed := model.ReplayTask(events)
+		stored, _ = repo.GetById("task_1")
+		if replayed == nil || replayed.Status != stored.Status || replayed.Error != stored.Error ||
+			replayed.SDKTaskID != "sdk_2" || *replayed.CurrentPosition != *stored.CurrentPosition ||
+			!replayed.UpdatedAt.Equal(stored.UpdatedAt) {
+			t.Errorf("expected replaying the history to give %+v, got %+v", stored, replayed)
+		}
+
+		if _, err := repo.History("missing"); err == nil {
+			t.Error("expected an error for an unknown task")
+		}
+	})
+}
+
+// outboxSize counts every entry the repository still stores, delivered or not.
+func outboxSize(t *testing.T, repo ITaskRepository) int {
+	switch repo := repo.(type) {
+	case *InMemoryTaskRepository:
+		repo.mu.RLock()
+		defer repo.mu.RUnlock()
+		return len(repo.outbox)
+	case *SQLiteTaskRepository:
+		var size int
+		if err := repo.db.QueryRow(`SELECT COUNT(*) FROM task_outbox`).Scan(&size); err != nil {
+			t.Fatal(err)
+		}
+		return size
+	}
+	t.Fatalf("unknown repository %T", repo)
+	return 0
+}
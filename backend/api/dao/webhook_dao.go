@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"errors"
+	"warehouse-robots/backend/api/model"
+)
+
+// ErrWebhookExists is returned by Create for an ID that is taken, also by a
+// webhook that was deleted, since its delivery log is kept.
+var ErrWebhookExists = errors.New("webhook already exists")
+
+type IWebhookRepository interface {
+	// Create adds a webhook; returns ErrWebhookExists if its ID is taken
+	Create(webhook *model.Webhook) error
+
+	// GetById returns the webhook, or ErrWebhookNotFound
+	GetById(webhookID string) (*model.Webhook, error)
+
+	// List returns every webhook in registration order
+	List() ([]*model.Webhook, error)
+
+	// Delete removes the webhook, keeping its delivery log; returns ErrWebhookNotFound if unknown
+	Delete(webhookID string) error
+
+	// RecordAttempt appends a delivery attempt to the webhook's log, which
+	// keeps the last constant.WebhookAttemptHistory attempts
+	RecordAttempt(attempt *model.WebhookAttempt) error
+
+	// ListAttempts returns the webhook's delivery attempts, oldest first
+	ListAttempts(webhookID string) ([]*model.WebhookAttempt, error)
+}
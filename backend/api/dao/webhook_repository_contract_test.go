@@ -0,0 +1,145 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// Every IWebhookRepository implementation runs the same contract.
+
+func TestInMemoryWebhookRepository_Contract(t *testing.T) {
+	runWebhookRepositoryContract(t, func(t *testing.T) IWebhookRepository {
+		return NewInMemoryWebhookRepository()
+	})
+}
+
+func TestSQLiteWebhookRepository_Contract(t *testing.T) {
+	runWebhookRepositoryContract(t, func(t *testing.T) IWebhookRepository {
+		tasks, err := NewSQLiteTaskRepository(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { tasks.Close() })
+		return NewSQLiteWebhookRepository(tasks)
+	})
+}
+
+func TestSQLiteWebhookRepository_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	tasks, err := NewSQLiteTaskRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhooks := NewSQLiteWebhookRepository(tasks)
+	_ = webhooks.Create(newContractWebhook("webhook_1"))
+	_ = webhooks.RecordAttempt(&model.WebhookAttempt{WebhookID: "webhook_1", DeliveryID: "delivery_1",
+		Attempt: 1, StatusCode: 200, Succeeded: true, Duration: 15 * time.Millisecond, At: time.Now()})
+	tasks.Close()
+
+	tasks, err = NewSQLiteTaskRepository(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tasks.Close()
+	webhooks = NewSQLiteWebhookRepository(tasks)
+
+	webhook, err := webhooks.GetById("webhook_1")
+	if err != nil || webhook.URL != "http://example.com/hook" || len(webhook.Events) != 1 {
+		t.Fatalf("expected the webhook to survive, got %+v (%v)", webhook, err)
+	}
+	attempts, _ := webhooks.ListAttempts("webhook_1")
+	if len(attempts) != 1 || !attempts[0].Succeeded || attempts[0].Duration != 15*time.Millisecond {
+		t.Errorf("expected the delivery log to survive, got %+v", attempts)
+	}
+}
+
+func newContractWebhook(id string) *model.Webhook {
+	return &model.Webhook{
+		ID:        id,
+		URL:       "http://example.com/hook",
+		Secret:    "secret",
+		Events:    []string{model.WebhookEventTaskCompleted},
+		CreatedAt: time.Now(),
+	}
+}
+
+func runWebhookRepositoryContract(t *testing.T, newRepo func(t *testing.T) IWebhookRepository) {
+	t.Run("create_get_list", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, id := range []string{"webhook_2", "webhook_1"} {
+			if err := repo.Create(newContractWebhook(id)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := repo.Create(newContractWebhook("webhook_1")); !errors.Is(err, ErrWebhookExists) {
+			t.Errorf("expected ErrWebhookExists for a taken ID, got %v", err)
+		}
+
+		webhook, err := repo.GetById("webhook_1")
+		if err != nil || webhook.Secret != "secret" || len(webhook.Events) != 1 || webhook.Events[0] != model.WebhookEventTaskCompleted {
+			t.Errorf("expected webhook_1 as stored, got %+v (%v)", webhook, err)
+		}
+		if _, err := repo.GetById("webhook_9"); !errors.Is(err, model.ErrWebhookNotFound) {
+			t.Errorf("expected ErrWebhookNotFound, got %v", err)
+		}
+
+		webhooks, _ := repo.List()
+		if len(webhooks) != 2 || webhooks[0].ID != "webhook_2" || webhooks[1].ID != "webhook_1" {
+			t.Errorf("expected both webhooks in registration order, got %+v", webhooks)
+		}
+	})
+
+	t.Run("delete_keeps_log", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractWebhook("webhook_1"))
+		_ = repo.RecordAttempt(&model.WebhookAttempt{WebhookID: "webhook_1", DeliveryID: "delivery_1", Attempt: 1, At: time.Now()})
+
+		if err := repo.Delete("webhook_1"); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Delete("webhook_1"); !errors.Is(err, model.ErrWebhookNotFound) {
+			t.Errorf("expected a second delete to fail with ErrWebhookNotFound, got %v", err)
+		}
+		if _, err := repo.GetById("webhook_1"); !errors.Is(err, model.ErrWebhookNotFound) {
+			t.Errorf("expected a deleted webhook not to be found, got %v", err)
+		}
+		if webhooks, _ := repo.List(); len(webhooks) != 0 {
+			t.Errorf("expected no webhooks listed, got %+v", webhooks)
+		}
+		if attempts, _ := repo.ListAttempts("webhook_1"); len(attempts) != 1 {
+			t.Errorf("expected the delivery log to stay, got %+v", attempts)
+		}
+		if err := repo.Create(newContractWebhook("webhook_1")); !errors.Is(err, ErrWebhookExists) {
+			t.Errorf("expected the ID of a deleted webhook to stay taken, got %v", err)
+		}
+	})
+
+	t.Run("attempt_log_capped", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.Create(newContractWebhook("webhook_1"))
+		for i := 1; i <= constant.WebhookAttemptHistory+5; i++ {
+			if err := repo.RecordAttempt(&model.WebhookAttempt{WebhookID: "webhook_1",
+				DeliveryID: fmt.Sprintf("delivery_%d", i), Attempt: 1, At: time.Now()}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_ = repo.RecordAttempt(&model.WebhookAttempt{WebhookID: "webhook_2", DeliveryID: "other", Attempt: 1, At: time.Now()})
+
+		attempts, _ := repo.ListAttempts("webhook_1")
+		if len(attempts) != constant.WebhookAttemptHistory {
+			t.Fatalf("expected the log capped at %d attempts, got %d", constant.WebhookAttemptHistory, len(attempts))
+		}
+		if attempts[0].DeliveryID != "delivery_6" || attempts[len(attempts)-1].DeliveryID != fmt.Sprintf("delivery_%d", constant.WebhookAttemptHistory+5) {
+			t.Errorf("expected the oldest attempts dropped, got %s..%s", attempts[0].DeliveryID, attempts[len(attempts)-1].DeliveryID)
+		}
+		if other, _ := repo.ListAttempts("webhook_2"); len(other) != 1 {
+			t.Errorf("expected other webhooks' logs untouched, got %+v", other)
+		}
+	})
+}
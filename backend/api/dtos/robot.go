@@ -14,9 +14,11 @@ type RobotState struct {
 	HasCrate bool `json:"has_crate"`
 }
 
-// CreateTaskRequest is the payload for creating a facades task
+// CreateTaskRequest is the payload for creating a facades task.
+// Exactly one of Commands or Goto must be set; for Goto the server plans the route.
 type CreateTaskRequest struct {
-	Commands string
+	Commands string   `json:"commands,omitempty"`
+	Goto     *CellRef `json:"goto,omitempty"`
 }
 
 // TaskInfo contains information about a facades task (single facades system)
@@ -24,6 +26,7 @@ type TaskInfo struct {
 	TaskID       string      `json:"task_id"`
 	RobotID      string      `json:"robot_id"`
 	Commands     string      `json:"commands"`
+	Goto         *CellRef    `json:"goto,omitempty"`
 	Status       TaskStatus  `json:"status"`
 	CurrentState *RobotState `json:"current_state,omitempty"`
 	Error        string      `json:"error,omitempty"`
@@ -31,6 +34,120 @@ type TaskInfo struct {
 	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
+// TaskEvent is one server-sent event of a task's event stream. Type is one of
+// "status", "position", "error" or "end"; "end" is always the last event.
+type TaskEvent struct {
+	ID       int64       `json:"id"`
+	TaskID   string      `json:"task_id"`
+	RobotID  string      `json:"robot_id,omitempty"`
+	Type     string      `json:"type"`
+	Status   TaskStatus  `json:"status"`
+	Position *RobotState `json:"position,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	At       time.Time   `json:"at"`
+}
+
+// TaskTrajectory is the recorded history of a task: every position the robot
+// reported while working on it and every status the task went through.
+type TaskTrajectory struct {
+	TaskID      string             `json:"task_id"`
+	RobotID     string             `json:"robot_id"`
+	Status      TaskStatus         `json:"status"`
+	Points      []TrajectoryPoint  `json:"points"`
+	Transitions []StatusTransition `json:"transitions"`
+}
+
+// TrajectoryPoint is one position sample of a task; step 0 is the cell the
+// task started from.
+type TrajectoryPoint struct {
+	Step     int       `json:"step"`
+	X        uint      `json:"x"`
+	Y        uint      `json:"y"`
+	HasCrate bool      `json:"has_crate"`
+	At       time.Time `json:"at"`
+}
+
+// StatusTransition is a status the task entered, starting with its creation.
+type StatusTransition struct {
+	Status TaskStatus `json:"status"`
+	Error  string     `json:"error,omitempty"`
+	At     time.Time  `json:"at"`
+}
+
+// TaskMonitorInfo describes a running task monitor: how long it has been
+// watching the task, when the robot last reported on it and how long the task
+// has left before it times out.
+type TaskMonitorInfo struct {
+	TaskID             string     `json:"task_id"`
+	RobotID            string     `json:"robot_id,omitempty"`
+	StartedAt          time.Time  `json:"started_at"`
+	AgeMs              int64      `json:"age_ms"`
+	LastEventAt        *time.Time `json:"last_event_at,omitempty"`
+	Events             int        `json:"events"`
+	RemainingTimeoutMs int64      `json:"remaining_timeout_ms"`
+}
+
+// FleetFilter selects the events a fleet telemetry connection receives; an
+// empty list lets everything through.
+type FleetFilter struct {
+	RobotIDs []string `json:"robots,omitempty"`
+	Types    []string `json:"types,omitempty"`
+}
+
+// TaskPreview is the simulated outcome of a task request that has not been enqueued.
+// Trajectory holds the robot state before the first command and after every command.
+type TaskPreview struct {
+	RobotID             string       `json:"robot_id"`
+	Commands            string       `json:"commands"`
+	Goto                *CellRef     `json:"goto,omitempty"`
+	Trajectory          []RobotState `json:"trajectory"`
+	FinalPosition       RobotState   `json:"final_position"`
+	PathLength          int          `json:"path_length"`
+	EstimatedDurationMs int64        `json:"estimated_duration_ms"`
+}
+
+// BatchMoveRequest asks for several robots to be sent to their destinations at
+// once; the order of Moves is the planning priority.
+type BatchMoveRequest struct {
+	Moves []BatchMove `json:"moves"`
+}
+
+// BatchMove is the destination of one robot in a batch move.
+type BatchMove struct {
+	RobotID string  `json:"robot_id"`
+	Goto    CellRef `json:"goto"`
+}
+
+// BatchMovePlan is the outcome of an accepted batch move: one task per robot,
+// in request order, and the number of steps until the last robot arrives.
+type BatchMovePlan struct {
+	Tasks               []BatchMoveTask `json:"tasks"`
+	Makespan            int             `json:"makespan"`
+	EstimatedDurationMs int64           `json:"estimated_duration_ms"`
+}
+
+// BatchMoveTask is the task enqueued for one robot of a batch move.
+type BatchMoveTask struct {
+	RobotID    string  `json:"robot_id"`
+	TaskID     string  `json:"task_id"`
+	Commands   string  `json:"commands"`
+	Goto       CellRef `json:"goto"`
+	PathLength int     `json:"path_length"`
+}
+
+// RobotInfo contains the live state of a robot and the task it is working on, if any
+type RobotInfo struct {
+	ID            string     `json:"id"`
+	Position      RobotState `json:"position"`
+	ActiveTaskID  string     `json:"active_task_id,omitempty"`
+	QueuedTaskIDs []string   `json:"queued_task_ids,omitempty"`
+}
+
+// RegisterRobotRequest names a decommissioned robot to put back in service
+type RegisterRobotRequest struct {
+	ID string `json:"id"`
+}
+
 // ErrorResponse is the standard error response
 type ErrorResponse struct {
 	Code    string `json:"code"`
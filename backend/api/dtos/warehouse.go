@@ -0,0 +1,57 @@
+package dtos
+
+// WarehouseInfo describes the warehouse grid. Valid cells run from
+// (origin_x, origin_y) to (max_x, max_y) inclusive, minus walls, shelves,
+// blocked cells and no-go zones.
+type WarehouseInfo struct {
+	Width         uint            `json:"width"`
+	Height        uint            `json:"height"`
+	OriginX       uint            `json:"origin_x"`
+	OriginY       uint            `json:"origin_y"`
+	MaxX          uint            `json:"max_x"`
+	MaxY          uint            `json:"max_y"`
+	Walls         []CellRef       `json:"walls"`
+	Shelves       []CellRef       `json:"shelves"`
+	BlockedCells  []CellRef       `json:"blocked_cells"`
+	NoGoZones     []NoGoZoneInfo  `json:"no_go_zones"`
+	CrateSlots    []CellRef       `json:"crate_slots"`
+	ChargingDocks []CellRef       `json:"charging_docks"`
+	RobotHomes    []RobotHomeInfo `json:"robot_homes"`
+}
+
+// RobotHomeInfo is the cell a robot parks on when the warehouse starts
+type RobotHomeInfo struct {
+	RobotID string `json:"robot_id"`
+	X       uint   `json:"x"`
+	Y       uint   `json:"y"`
+}
+
+// CellRef is a bare (x, y) coordinate
+type CellRef struct {
+	X uint `json:"x"`
+	Y uint `json:"y"`
+}
+
+// NoGoZoneInfo is a named rectangle robots must not enter; min and max are inclusive
+type NoGoZoneInfo struct {
+	Name string  `json:"name"`
+	Min  CellRef `json:"min"`
+	Max  CellRef `json:"max"`
+}
+
+// CrateInfo describes a crate on the warehouse floor
+type CrateInfo struct {
+	ID  string `json:"id,omitempty"`
+	SKU string `json:"sku,omitempty"`
+	X   uint   `json:"x"`
+	Y   uint   `json:"y"`
+}
+
+// CellInfo describes the contents of a single warehouse cell
+type CellInfo struct {
+	X           uint       `json:"x"`
+	Y           uint       `json:"y"`
+	Obstruction string     `json:"obstruction,omitempty"`
+	Crate       *CrateInfo `json:"crate,omitempty"`
+	RobotIDs    []string   `json:"robot_ids"`
+}
@@ -0,0 +1,36 @@
+package dtos
+
+import (
+	"time"
+)
+
+// RegisterWebhookRequest registers a callback URL for task end events.
+// Events lists "task.completed", "task.failed" and/or "task.cancelled"; empty
+// means all. Without a secret the server generates one.
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookInfo describes a registered webhook. The secret is only returned on registration.
+type WebhookInfo struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery is one attempt to deliver a payload to a webhook.
+type WebhookDelivery struct {
+	DeliveryID string    `json:"delivery_id"`
+	TaskID     string    `json:"task_id"`
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	At         time.Time `json:"at"`
+}
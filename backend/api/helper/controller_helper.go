@@ -52,18 +52,32 @@ func MapErrorToHTTPStatus(err error) (int, string) {
 		return http.StatusBadRequest, constant.ErrorCodeRobotIdInvalid
 	case errors.Is(err, model.ErrBoundary):
 		return http.StatusBadRequest, constant.ErrorCodeBoundary
+	case errors.Is(err, model.ErrCrate):
+		return http.StatusBadRequest, constant.ErrorCodeCrate
+	case errors.Is(err, model.ErrObstacle):
+		return http.StatusBadRequest, constant.ErrorCodeObstacle
 
 	// 404
 	case errors.Is(err, model.ErrTaskNotFound):
 		return http.StatusNotFound, constant.ErrorCodeTaskNotFound
 	case errors.Is(err, model.ErrRobotNotFound):
 		return http.StatusNotFound, constant.ErrorCodeRobotNotFound
+	case errors.Is(err, model.ErrWebhookNotFound):
+		return http.StatusNotFound, constant.ErrorCodeWebhookNotFound
 
 	// 409
 	case errors.Is(err, model.ErrRobotBusy):
 		return http.StatusConflict, constant.ErrorCodeRobotBusy
 	case errors.Is(err, model.ErrTaskProcessed): // already terminal
 		return http.StatusConflict, constant.ErrorCodeTaskAlreadyDone
+	case errors.Is(err, model.ErrRobotAlreadyRegistered):
+		return http.StatusConflict, constant.ErrorCodeRobotAlreadyRegistered
+	case errors.Is(err, model.ErrPathConflict): // another robot's reserved path
+		return http.StatusConflict, constant.ErrorCodePathConflict
+
+	// 422
+	case errors.Is(err, model.ErrNoPath):
+		return http.StatusUnprocessableEntity, constant.ErrorCodeNoPath
 
 	// 429
 	case errors.Is(err, model.ErrTaskQueueFull):
@@ -73,8 +87,23 @@ func MapErrorToHTTPStatus(err error) (int, string) {
 	case errors.Is(err, model.ErrSDKFailedToCancel):
 		return http.StatusBadGateway, constant.ErrorSDKFailedToCancel
 
+	// 503
+	case errors.Is(err, model.ErrShuttingDown): // no new tasks while draining
+		return http.StatusServiceUnavailable, constant.ErrorCodeShuttingDown
+
 	// 500
 	default:
 		return http.StatusInternalServerError, constant.ErrorCodeInternal
 	}
 }
+
+// ErrorDetails extracts additional context for ErrorResponse.details from a
+// service error, such as the failing step of a rejected command plan or the
+// position of a command syntax error.
+func ErrorDetails(err error) string {
+	var detailed interface{ Details() string }
+	if errors.As(err, &detailed) {
+		return detailed.Details()
+	}
+	return ""
+}
@@ -0,0 +1,258 @@
+package manager
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"warehouse-robots/backend/api/model"
+)
+
+// OverflowPolicy decides what happens to an event for a subscriber whose
+// buffer is full.
+type OverflowPolicy int
+
+const (
+	// DropWhenFull discards the event for that subscriber and counts it as dropped.
+	DropWhenFull OverflowPolicy = iota
+	// BlockWhenFull makes the publisher wait until the subscriber catches up.
+	// Only for subscribers that must not miss events and handle them quickly.
+	BlockWhenFull
+)
+
+const defaultEventBuffer = 64
+
+// SubscribeOptions configure a subscription to the event bus.
+type SubscribeOptions struct {
+	// Events lists the event names to receive, e.g. model.EventTaskCompleted;
+	// empty means all of them.
+	Events []string
+	// Buffer is how many events may wait per shard; 64 if zero.
+	Buffer int
+	// Shards is how many events are handled in parallel; 1 if zero. Events of
+	// the same task or robot always go to the same shard, so they stay in order.
+	Shards int
+	// Overflow decides what happens when a shard's buffer is full.
+	Overflow OverflowPolicy
+}
+
+// EventBus is an in-process publish/subscribe bus for domain events. Services
+// and the task monitor publish what happened once it has been persisted;
+// notifications, streaming, metrics or audit subscribe without the publishers
+// knowing about them.
+//
+// Every subscriber has its own bounded buffers and goroutines, so a slow
+// subscriber never holds up the others. Events with the same stream ID (task
+// or robot) are handled in the order they were published.
+//
+// A nil bus discards everything, so publishing is optional.
+type EventBus struct {
+	mu          sync.Mutex
+	subscribers []*EventSubscription // replaced, never modified, so Publish can read it unlocked
+	closed      bool
+	wg          sync.WaitGroup
+}
+
+// EventSubscription is a handler registered on the bus.
+type EventSubscription struct {
+	name     string
+	events   map[string]bool
+	handler  func(model.DomainEvent)
+	shards   []chan model.DomainEvent
+	overflow OverflowPolicy
+	dropped  atomic.Int64
+	done     chan struct{}
+	once     sync.Once
+	bus      *EventBus
+}
+
+// NewEventBus creates a bus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{}
+}
+
+// Subscribe registers the handler for the events selected by the options.
+// The name identifies the subscriber in logs. A handler must not publish
+// events that come back to its own BlockWhenFull subscription.
+func (b *EventBus) Subscribe(name string, handler func(model.DomainEvent), opts SubscribeOptions) *EventSubscription {
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = defaultEventBuffer
+	}
+	shards := max(opts.Shards, 1)
+
+	sub := &EventSubscription{
+		name:     name,
+		events:   make(map[string]bool, len(opts.Events)),
+		handler:  handler,
+		shards:   make([]chan model.DomainEvent, shards),
+		overflow: opts.Overflow,
+		done:     make(chan struct{}),
+		bus:      b,
+	}
+	for _, event := range opts.Events {
+		sub.events[event] = true
+	}
+	for i := range sub.shards {
+		sub.shards[i] = make(chan model.DomainEvent, buffer)
+	}
+
+	if b == nil {
+		sub.stop()
+		return sub
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		sub.stop()
+		return sub
+	}
+
+	subscribers := make([]*EventSubscription, 0, len(b.subscribers)+1)
+	subscribers = append(subscribers, b.subscribers...)
+	b.subscribers = append(subscribers, sub)
+
+	for _, shard := range sub.shards {
+		b.wg.Add(1)
+		go sub.run(shard, &b.wg)
+	}
+	return sub
+}
+
+// Publish hands the event to every subscriber that wants it.
+func (b *EventBus) Publish(event model.DomainEvent) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	subscribers := b.subscribers
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.offer(event)
+	}
+}
+
+// Close stops accepting events and waits until the subscribers have handled
+// the events already published, or the context ends.
+func (b *EventBus) Close(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.closed = true
+	subscribers := b.subscribers
+	b.subscribers = nil
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.stop()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close removes the subscription; events already buffered are still handled.
+// It is safe to call more than once, also from the handler.
+func (sub *EventSubscription) Close() {
+	if b := sub.bus; b != nil {
+		b.mu.Lock()
+		subscribers := make([]*EventSubscription, 0, len(b.subscribers))
+		for _, other := range b.subscribers {
+			if other != sub {
+				subscribers = append(subscribers, other)
+			}
+		}
+		b.subscribers = subscribers
+		b.mu.Unlock()
+	}
+	sub.stop()
+}
+
+// Dropped returns how many events were discarded because the subscriber fell behind.
+func (sub *EventSubscription) Dropped() int64 {
+	return sub.dropped.Load()
+}
+
+// stop tells the shard goroutines to finish what is buffered and exit.
+func (sub *EventSubscription) stop() {
+	sub.once.Do(func() { close(sub.done) })
+}
+
+// offer queues the event on the shard of its stream, applying the overflow policy.
+func (sub *EventSubscription) offer(event model.DomainEvent) {
+	if len(sub.events) > 0 && !sub.events[event.EventName()] {
+		return
+	}
+
+	shard := sub.shards[0]
+	if len(sub.shards) > 1 {
+		hash := fnv.New32a()
+		hash.Write([]byte(event.StreamID()))
+		shard = sub.shards[hash.Sum32()%uint32(len(sub.shards))]
+	}
+
+	if sub.overflow == BlockWhenFull {
+		select {
+		case shard <- event:
+		case <-sub.done:
+		}
+		return
+	}
+
+	select {
+	case shard <- event:
+	case <-sub.done:
+	default:
+		if dropped := sub.dropped.Add(1); dropped == 1 || dropped%100 == 0 {
+			log.Printf("event bus: %s fell behind, %d event(s) dropped so far", sub.name, dropped)
+		}
+	}
+}
+
+// run handles the events of one shard until the subscription stops, then
+// handles whatever is still buffered.
+func (sub *EventSubscription) run(shard chan model.DomainEvent, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case event := <-shard:
+			sub.handle(event)
+		case <-sub.done:
+			for {
+				select {
+				case event := <-shard:
+					sub.handle(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle runs the handler, keeping a panicking handler from taking down the bus.
+func (sub *EventSubscription) handle(event model.DomainEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("event bus: %s panicked on %s of %s: %v", sub.name, event.EventName(), event.StreamID(), r)
+		}
+	}()
+	sub.handler(event)
+}
@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestEventBus_OrderedPerTask(t *testing.T) {
+	bus := NewEventBus()
+
+	var mu sync.Mutex
+	steps := make(map[string][]int)
+	bus.Subscribe("recorder", func(event model.DomainEvent) {
+		e := event.(model.TaskPositionUpdated)
+		mu.Lock()
+		steps[e.TaskID] = append(steps[e.TaskID], int(e.Position.X))
+		mu.Unlock()
+	}, SubscribeOptions{Shards: 4, Buffer: 8, Overflow: BlockWhenFull})
+
+	var publishers sync.WaitGroup
+	for task := range 8 {
+		publishers.Add(1)
+		go func() {
+			defer publishers.Done()
+			for step := range 100 {
+				bus.Publish(model.TaskPositionUpdated{TaskID: fmt.Sprintf("task_%d", task), Position: model.Position{X: uint(step)}})
+			}
+		}()
+	}
+	publishers.Wait()
+
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for task, got := range steps {
+		if len(got) != 100 {
+			t.Fatalf("expected 100 events for %s, got %d", task, len(got))
+		}
+		for i, step := range got {
+			if step != i {
+				t.Fatalf("expected the events of %s in order, got step %d at %d", task, step, i)
+			}
+		}
+	}
+}
+
+func TestEventBus_SlowSubscriberDrops(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close(context.Background())
+
+	release := make(chan struct{})
+	slow := bus.Subscribe("slow", func(model.DomainEvent) { <-release }, SubscribeOptions{Buffer: 2})
+
+	var fast sync.WaitGroup
+	fast.Add(10)
+	bus.Subscribe("fast", func(model.DomainEvent) { fast.Done() }, SubscribeOptions{})
+
+	for range 10 {
+		bus.Publish(model.TaskCreated{TaskID: "a"})
+	}
+	fast.Wait() // the slow subscriber did not hold up the fast one
+
+	// one event in the handler, two buffered
+	if dropped := slow.Dropped(); dropped < 7 {
+		t.Errorf("expected at least 7 events dropped for the slow subscriber, got %d", dropped)
+	}
+	close(release)
+}
+
+func TestEventBus_EventFilter(t *testing.T) {
+	bus := NewEventBus()
+
+	var got []string
+	bus.Subscribe("outcomes", func(event model.DomainEvent) {
+		got = append(got, event.EventName())
+	}, SubscribeOptions{Events: []string{model.EventTaskCompleted, model.EventTaskFailed}})
+
+	bus.Publish(model.TaskCreated{TaskID: "a"})
+	bus.Publish(model.TaskPositionUpdated{TaskID: "a"})
+	bus.Publish(model.TaskFailed{TaskID: "a"})
+	bus.Publish(model.RobotStateChanged{RobotID: "robot"})
+
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != model.EventTaskFailed {
+		t.Errorf("expected only TaskFailed, got %v", got)
+	}
+}
+
+func TestEventBus_Unsubscribe(t *testing.T) {
+	bus := NewEventBus()
+	defer bus.Close(context.Background())
+
+	handled := make(chan model.DomainEvent, 4)
+	sub := bus.Subscribe("audit", func(event model.DomainEvent) { handled <- event }, SubscribeOptions{})
+
+	bus.Publish(model.TaskCreated{TaskID: "a"})
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the event to be handled")
+	}
+
+	sub.Close()
+	sub.Close()
+	bus.Publish(model.TaskCreated{TaskID: "b"})
+	select {
+	case event := <-handled:
+		t.Errorf("expected no events after unsubscribing, got %+v", event)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestEventBus_Nil(t *testing.T) {
+	var bus *EventBus
+	bus.Publish(model.TaskCreated{TaskID: "a"})
+	sub := bus.Subscribe("nobody", func(model.DomainEvent) {}, SubscribeOptions{})
+	sub.Close()
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
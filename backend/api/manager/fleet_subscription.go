@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"sync"
+	"warehouse-robots/backend/api/model"
+)
+
+// fleetBacklog is how many events a fleet subscriber may have waiting before
+// it is dropped. Position updates do not count towards it beyond one per
+// robot, since they are coalesced.
+const fleetBacklog = 256
+
+// FleetFilter selects the events a fleet subscriber receives. An empty set
+// lets everything through.
+type FleetFilter struct {
+	RobotIDs map[string]bool
+	Types    map[model.TaskEventType]bool
+}
+
+// matches reports whether the event passes the filter.
+func (f FleetFilter) matches(event model.TaskEvent) bool {
+	if len(f.RobotIDs) > 0 && !f.RobotIDs[event.RobotID] {
+		return false
+	}
+	if len(f.Types) > 0 && !f.Types[event.Type] {
+		return false
+	}
+	return true
+}
+
+// FleetSubscription receives the events of every task, across all robots.
+//
+// Publishing never waits for the subscriber: a robot's position update
+// replaces one of the same robot that has not been drained yet, and a
+// subscriber that lets other events pile up past the backlog is dropped.
+type FleetSubscription struct {
+	mu      sync.Mutex
+	filter  FleetFilter
+	pending []model.TaskEvent
+	closed  bool
+	ready   chan struct{}
+	stream  *TaskEventStream
+}
+
+// SubscribeFleet registers a subscriber for the events of all tasks that pass the filter.
+func (s *TaskEventStream) SubscribeFleet(filter FleetFilter) *FleetSubscription {
+	sub := &FleetSubscription{
+		filter: filter,
+		ready:  make(chan struct{}, 1),
+		stream: s,
+	}
+	if s == nil {
+		sub.closed = true
+		close(sub.ready)
+		return sub
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fleet[sub] = struct{}{}
+	return sub
+}
+
+// Ready is signalled when events are waiting, and closed once the
+// subscription has been dropped or closed.
+func (sub *FleetSubscription) Ready() <-chan struct{} {
+	return sub.ready
+}
+
+// Drain takes the waiting events, oldest first. open is false once the
+// subscription has been dropped or closed and nothing more will arrive.
+func (sub *FleetSubscription) Drain() (events []model.TaskEvent, open bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	events, sub.pending = sub.pending, nil
+	return events, !sub.closed
+}
+
+// SetFilter replaces the filter for events published from now on.
+func (sub *FleetSubscription) SetFilter(filter FleetFilter) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.filter = filter
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (sub *FleetSubscription) Close() {
+	if sub.stream == nil {
+		return
+	}
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	sub.stream.unsubscribeFleet(sub)
+}
+
+// offer queues the event for the subscriber, coalescing position updates.
+// It returns false if the subscriber has fallen too far behind.
+func (sub *FleetSubscription) offer(event model.TaskEvent) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed || !sub.filter.matches(event) {
+		return true
+	}
+
+	if event.Type == model.TaskEventPosition {
+		// only the latest position of a robot matters; dropping the stale one
+		// rather than overwriting it in place keeps the robot's events in order
+		for i, waiting := range sub.pending {
+			if waiting.Type == model.TaskEventPosition && waiting.RobotID == event.RobotID {
+				sub.pending = append(sub.pending[:i], sub.pending[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if len(sub.pending) >= fleetBacklog {
+		return false
+	}
+	sub.pending = append(sub.pending, event)
+
+	select {
+	case sub.ready <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// unsubscribeFleet removes the subscriber and closes it; s.mu must be held.
+func (s *TaskEventStream) unsubscribeFleet(sub *FleetSubscription) {
+	if _, ok := s.fleet[sub]; !ok {
+		return
+	}
+	delete(s.fleet, sub)
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.closed = true
+	close(sub.ready)
+}
@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestFleetSubscription_CoalescesPositions(t *testing.T) {
+	stream := NewTaskEventStream()
+	sub := stream.SubscribeFleet(FleetFilter{})
+	defer sub.Close()
+
+	position := func(robotID string, y uint) model.TaskEvent {
+		return model.TaskEvent{TaskID: "task-" + robotID, RobotID: robotID, Type: model.TaskEventPosition,
+			Position: &model.Position{Y: y}}
+	}
+	stream.Publish(position("a", 1))
+	stream.Publish(position("b", 1))
+	stream.Publish(position("a", 2))
+	stream.Publish(model.TaskEvent{TaskID: "task-a", RobotID: "a", Type: model.TaskEventEnd, Status: model.TaskStatusCompleted})
+
+	<-sub.Ready()
+	events, open := sub.Drain()
+	if !open {
+		t.Fatal("expected the subscription to stay open")
+	}
+
+	// a's stale position is gone, and its latest one still precedes its end
+	var got []string
+	for _, event := range events {
+		label := event.RobotID + ":" + string(event.Type)
+		if event.Position != nil {
+			label += fmt.Sprintf(":%d", event.Position.Y)
+		}
+		got = append(got, label)
+	}
+	want := []string{"b:position:1", "a:position:2", "a:end"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestFleetSubscription_Filter(t *testing.T) {
+	stream := NewTaskEventStream()
+	sub := stream.SubscribeFleet(FleetFilter{
+		RobotIDs: map[string]bool{"a": true},
+		Types:    map[model.TaskEventType]bool{model.TaskEventEnd: true},
+	})
+	defer sub.Close()
+
+	stream.Publish(model.TaskEvent{TaskID: "1", RobotID: "a", Type: model.TaskEventPosition})
+	stream.Publish(model.TaskEvent{TaskID: "2", RobotID: "b", Type: model.TaskEventEnd})
+	stream.Publish(model.TaskEvent{TaskID: "1", RobotID: "a", Type: model.TaskEventEnd})
+
+	events, _ := sub.Drain()
+	if len(events) != 1 || events[0].TaskID != "1" || events[0].Type != model.TaskEventEnd {
+		t.Errorf("expected only the end of robot a's task, got %+v", events)
+	}
+}
+
+func TestFleetSubscription_SlowConsumerDropped(t *testing.T) {
+	stream := NewTaskEventStream()
+	sub := stream.SubscribeFleet(FleetFilter{})
+
+	// lifecycle events cannot be coalesced, so they pile up
+	for i := 0; i <= fleetBacklog; i++ {
+		stream.Publish(model.TaskEvent{TaskID: fmt.Sprintf("task-%d", i), Type: model.TaskEventStatus})
+	}
+
+	events, open := sub.Drain()
+	if open || len(events) != fleetBacklog {
+		t.Errorf("expected a dropped subscription with %d events, got open=%t and %d events", fleetBacklog, open, len(events))
+	}
+	// Ready is closed after any pending signal
+	for range sub.Ready() {
+	}
+	sub.Close()
+}
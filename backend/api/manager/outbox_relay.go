@@ -0,0 +1,197 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/model"
+)
+
+// OutboxSink receives the events the outbox relay publishes. Delivery is at
+// least once: after a crash, or when another sink failed, a sink may be sent
+// an event it already received.
+type OutboxSink interface {
+	Send(event model.DomainEvent) error
+}
+
+// OutboxSinkFunc adapts a function to an OutboxSink.
+type OutboxSinkFunc func(event model.DomainEvent) error
+
+// Send calls the function.
+func (f OutboxSinkFunc) Send(event model.DomainEvent) error {
+	return f(event)
+}
+
+// BusSink publishes the events on the event bus.
+func BusSink(bus *EventBus) OutboxSink {
+	return OutboxSinkFunc(func(event model.DomainEvent) error {
+		bus.Publish(event)
+		return nil
+	})
+}
+
+// LogSink writes a log line per event.
+func LogSink() OutboxSink {
+	return OutboxSinkFunc(func(event model.DomainEvent) error {
+		log.Printf("outbox: %s %s at %s", event.EventName(), event.StreamID(), event.OccurredAt().Format(time.RFC3339Nano))
+		return nil
+	})
+}
+
+// OutboxRelay publishes the entries the task repository records in its outbox
+// and marks them delivered once every sink accepted them. Entries are relayed
+// oldest first; when a sink fails, the relay stops and tries again from that
+// entry at the next poll, so the events of a task are never reordered.
+type OutboxRelay struct {
+	repository dao.ITaskRepository
+	sinks      []namedSink
+	interval   time.Duration
+	batchSize  int
+
+	// sinks that already accepted an entry that is still pending; only touched
+	// by the relay goroutine
+	sent    map[int64]map[string]bool
+	lastErr string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the polling goroutine exits; nil until Start
+}
+
+// namedSink is a sink with the name it is logged under.
+type namedSink struct {
+	name string
+	sink OutboxSink
+}
+
+// NewOutboxRelay creates a relay that looks for pending entries every
+// interval and reads up to batchSize of them at a time.
+func NewOutboxRelay(repository dao.ITaskRepository, interval time.Duration, batchSize int) *OutboxRelay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &OutboxRelay{
+		repository: repository,
+		interval:   interval,
+		batchSize:  batchSize,
+		sent:       make(map[int64]map[string]bool),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// AddSink registers a sink; sinks must be added before Start.
+func (r *OutboxRelay) AddSink(name string, sink OutboxSink) {
+	r.sinks = append(r.sinks, namedSink{name: name, sink: sink})
+}
+
+// Start relays pending entries until Shutdown.
+func (r *OutboxRelay) Start() {
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			r.flush()
+			select {
+			case <-ticker.C:
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// flush relays pending entries until the outbox is empty or an entry could
+// not be relayed.
+func (r *OutboxRelay) flush() {
+	for {
+		entries, err := r.repository.PendingOutbox(r.batchSize)
+		if err != nil {
+			r.report(fmt.Errorf("read outbox: %w", err))
+			return
+		}
+		if len(entries) == 0 {
+			r.report(nil)
+			return
+		}
+
+		for _, entry := range entries {
+			if err := r.relay(entry); err != nil {
+				r.report(fmt.Errorf("entry %d (%s of %s): %w", entry.ID, entry.Event, entry.TaskID, err))
+				return
+			}
+		}
+	}
+}
+
+// relay sends the entry to every sink that has not accepted it yet and marks
+// it delivered.
+func (r *OutboxRelay) relay(entry *model.OutboxEntry) error {
+	event := entry.DomainEvent()
+	if event == nil {
+		log.Printf("outbox: skipping entry %d with unknown event %q", entry.ID, entry.Event)
+		return r.repository.MarkOutboxDelivered(entry.ID)
+	}
+
+	for _, sink := range r.sinks {
+		if r.sent[entry.ID][sink.name] {
+			continue
+		}
+		if err := sink.sink.Send(event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.name, err)
+		}
+		if r.sent[entry.ID] == nil {
+			r.sent[entry.ID] = make(map[string]bool, len(r.sinks))
+		}
+		r.sent[entry.ID][sink.name] = true
+	}
+
+	if err := r.repository.MarkOutboxDelivered(entry.ID); err != nil {
+		return fmt.Errorf("mark delivered: %w", err)
+	}
+	delete(r.sent, entry.ID)
+	return nil
+}
+
+// report logs a failure once rather than at every poll, and a recovery after it.
+func (r *OutboxRelay) report(err error) {
+	switch {
+	case err != nil && err.Error() != r.lastErr:
+		r.lastErr = err.Error()
+		log.Printf("outbox: %v; retrying", err)
+	case err == nil && r.lastErr != "":
+		r.lastErr = ""
+		log.Printf("outbox: relaying again")
+	}
+}
+
+// Shutdown stops polling and relays what is still pending, unless the context
+// ends first. Entries left behind stay in the outbox for the next start.
+func (r *OutboxRelay) Shutdown(ctx context.Context) error {
+	r.cancel()
+
+	if r.done != nil {
+		select {
+		case <-r.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	flushed := make(chan struct{})
+	go func() {
+		r.flush()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestOutboxRelay_AtLeastOnce(t *testing.T) {
+	repo := dao.NewInMemoryTaskRepository()
+	_ = repo.Create(&model.Task{TaskID: "task", RobotID: "robot", Status: model.TaskStatusPending})
+	_ = repo.UpdatePosition("task", &model.Position{X: 1, Y: 2}, model.TaskStatusPending)
+	_ = repo.UpdateStatus("task", model.TaskStatusCompleted, "")
+	// not a change, so nothing is recorded
+	_ = repo.UpdateStatus("task", model.TaskStatusCompleted, "")
+
+	var audited, notified []model.DomainEvent
+	down := true
+	relay := NewOutboxRelay(repo, time.Hour, 10)
+	relay.AddSink("audit", OutboxSinkFunc(func(event model.DomainEvent) error {
+		audited = append(audited, event)
+		return nil
+	}))
+	relay.AddSink("notify", OutboxSinkFunc(func(event model.DomainEvent) error {
+		if down {
+			return errors.New("unavailable")
+		}
+		notified = append(notified, event)
+		return nil
+	}))
+
+	relay.flush()
+	if pending, _ := repo.PendingOutbox(10); len(pending) != 2 {
+		t.Fatalf("expected both entries to stay pending while a sink is down, got %d", len(pending))
+	}
+	if len(audited) != 1 {
+		t.Fatalf("expected the relay to stop at the failing entry, got %d audited", len(audited))
+	}
+
+	down = false
+	relay.flush()
+	if pending, _ := repo.PendingOutbox(10); len(pending) != 0 {
+		t.Fatalf("expected every entry delivered, got %+v", pending)
+	}
+	if len(audited) != 2 {
+		t.Errorf("expected the audit sink not to get the retried entry again, got %d events", len(audited))
+	}
+	if len(notified) != 2 || notified[0].EventName() != model.EventTaskPositionUpdated {
+		t.Fatalf("expected the position then the completion, got %+v", notified)
+	}
+	completed, ok := notified[1].(model.TaskCompleted)
+	if !ok || completed.RobotID != "robot" || completed.Position == nil || completed.Position.X != 1 {
+		t.Errorf("expected the completion of robot's task at (1,2), got %+v", notified[1])
+	}
+}
+
+func TestOutboxRelay_ShutdownFlushes(t *testing.T) {
+	repo := dao.NewInMemoryTaskRepository()
+	_ = repo.Create(&model.Task{TaskID: "task", RobotID: "robot", Status: model.TaskStatusPending})
+
+	bus := NewEventBus()
+	failed := make(chan model.DomainEvent, 1)
+	bus.Subscribe("test", func(event model.DomainEvent) { failed <- event }, SubscribeOptions{})
+
+	relay := NewOutboxRelay(repo, time.Hour, 10)
+	relay.AddSink("bus", BusSink(bus))
+	relay.Start()
+
+	_ = repo.UpdateStatus("task", model.TaskStatusFailed, "motor stalled")
+	if err := relay.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-failed:
+		if e, ok := event.(model.TaskFailed); !ok || e.Reason != "motor stalled" {
+			t.Errorf("expected TaskFailed with the reason, got %+v", event)
+		}
+	default:
+		t.Fatal("expected the pending entry to be relayed on shutdown")
+	}
+}
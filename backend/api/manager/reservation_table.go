@@ -0,0 +1,357 @@
+package manager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	"warehouse-robots/backend/api/model"
+	"warehouse-robots/backend/api/planner"
+)
+
+// Reservation is the space-time footprint of one accepted plan: the robot is
+// expected on Cells[i] at tick StartTick+i, one tick per command.
+type Reservation struct {
+	RobotID   string
+	TaskID    string
+	StartTick int64
+	Cells     []model.Cell
+}
+
+// endTick is the tick the robot reaches the last cell of the plan.
+func (r *Reservation) endTick() int64 {
+	return r.StartTick + int64(len(r.Cells)) - 1
+}
+
+// ReservationTable records which cell every robot occupies at every tick of its
+// accepted plans, so a new plan can be rejected before it puts two robots in the
+// same cell or has them swap cells head-on. Time is measured in ticks of one
+// robot step; a robot rests on the last cell of its last plan (or the cell it
+// was parked on) indefinitely.
+//
+// Reservations follow the robots: TaskMonitor re-anchors a plan on every position
+// it observes and releases it when the task reaches a terminal state.
+type ReservationTable struct {
+	mu     sync.Mutex
+	clock  func() time.Time
+	epoch  time.Time
+	step   time.Duration
+	plans  map[string][]*Reservation // per robot, in execution order
+	parked map[string]model.Cell     // where each robot rests once its plans run out
+}
+
+// NewReservationTable creates an empty table whose ticks are one robot step long.
+func NewReservationTable(step time.Duration) *ReservationTable {
+	return &ReservationTable{
+		clock:  time.Now,
+		epoch:  time.Now(),
+		step:   step,
+		plans:  make(map[string][]*Reservation),
+		parked: make(map[string]model.Cell),
+	}
+}
+
+// Park records the cell a robot rests on while it has no plans.
+func (t *ReservationTable) Park(robotID string, cell model.Cell) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.parked[robotID] = cell
+}
+
+// Unpark forgets where the robot rests. Used when planning a batch in which the
+// robot is about to be given a route of its own anyway.
+func (t *ReservationTable) Unpark(robotID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.parked, robotID)
+}
+
+// Check reports whether a plan for the robot, starting once its queued plans
+// are done, conflicts with any other robot. cells[0] is the start cell and
+// cells[i] the cell after command i-1.
+// Returns a *model.PlanError wrapping ErrPathConflict for the first conflict.
+func (t *ReservationTable) Check(robotID string, cells []model.Cell) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.check(robotID, t.nextStartTick(robotID), cells)
+}
+
+// Reserve appends a plan to the robot's reservations and returns it. Callers
+// are expected to Check first, under a lock that keeps the plan current.
+func (t *ReservationTable) Reserve(robotID, taskID string, cells []model.Cell) *Reservation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	reservation := &Reservation{
+		RobotID:   robotID,
+		TaskID:    taskID,
+		StartTick: t.nextStartTick(robotID),
+		Cells:     cells,
+	}
+	t.plans[robotID] = append(t.plans[robotID], reservation)
+	return reservation
+}
+
+// Advance re-anchors a plan once the robot is observed on its step-th cell, so
+// delays (or a fast robot) shift this plan and the ones queued after it.
+func (t *ReservationTable) Advance(taskID string, step int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	robotID, index := t.find(taskID)
+	if index < 0 {
+		return
+	}
+
+	plans := t.plans[robotID]
+	delta := t.now() - int64(step) - plans[index].StartTick
+	if delta == 0 {
+		return
+	}
+	for _, plan := range plans[index:] {
+		plan.StartTick += delta
+	}
+}
+
+// Release drops the plan of a task. If at is given and the robot has no other
+// plans left, the robot is parked there.
+func (t *ReservationTable) Release(taskID string, at *model.Cell) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	robotID, index := t.find(taskID)
+	if index < 0 {
+		return
+	}
+
+	plans := t.plans[robotID]
+	plans = append(plans[:index], plans[index+1:]...)
+	if len(plans) == 0 {
+		delete(t.plans, robotID)
+		if at != nil {
+			t.parked[robotID] = *at
+		}
+		return
+	}
+	t.plans[robotID] = plans
+}
+
+// Reservations returns a snapshot of every robot's plans.
+func (t *ReservationTable) Reservations() []Reservation {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var reservations []Reservation
+	for _, plans := range t.plans {
+		for _, plan := range plans {
+			reservations = append(reservations, *plan)
+		}
+	}
+	return reservations
+}
+
+// Clone returns an independent copy of the table sharing its clock, so a batch
+// of plans can be tried out and reserved one by one without touching the
+// original until the whole batch is known to fit.
+func (t *ReservationTable) Clone() *ReservationTable {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clone := &ReservationTable{
+		clock:  t.clock,
+		epoch:  t.epoch,
+		step:   t.step,
+		plans:  make(map[string][]*Reservation, len(t.plans)),
+		parked: make(map[string]model.Cell, len(t.parked)),
+	}
+	for robotID, plans := range t.plans {
+		for _, plan := range plans {
+			copied := *plan
+			clone.plans[robotID] = append(clone.plans[robotID], &copied)
+		}
+	}
+	for robotID, cell := range t.parked {
+		clone.parked[robotID] = cell
+	}
+	return clone
+}
+
+// Delay is how many ticks from now a new plan for the robot would start, i.e.
+// how long its queued plans still run.
+func (t *ReservationTable) Delay(robotID string) int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.nextStartTick(robotID) - t.now()
+}
+
+// PathConstraints describes every other robot to the space-time planner for a
+// new plan of the robot, starting once its queued plans are done.
+func (t *ReservationTable) PathConstraints(robotID string) planner.Constraints {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &pathConstraints{table: t, robotID: robotID, startTick: t.nextStartTick(robotID)}
+}
+
+// pathConstraints answers the planner from the table, translating the plan's
+// ticks into table ticks. It applies the same rules as check.
+type pathConstraints struct {
+	table     *ReservationTable
+	robotID   string
+	startTick int64
+}
+
+func (c *pathConstraints) Blocked(tick int, from, to model.Cell) bool {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	at := c.startTick + int64(tick)
+	for other := range c.table.robots() {
+		if other == c.robotID {
+			continue
+		}
+		after, okAfter := c.table.cellAt(other, at)
+		if okAfter && after == to {
+			return true
+		}
+		before, okBefore := c.table.cellAt(other, at-1)
+		if okBefore && okAfter && before == to && after == from {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *pathConstraints) Settled(tick int, cell model.Cell) bool {
+	c.table.mu.Lock()
+	defer c.table.mu.Unlock()
+
+	at := c.startTick + int64(tick)
+	for other := range c.table.robots() {
+		if other == c.robotID {
+			continue
+		}
+		if occupied, ok := c.table.cellAt(other, at); ok && occupied == cell {
+			return false
+		}
+		for _, plan := range c.table.plans[other] {
+			for i, planned := range plan.Cells {
+				if plan.StartTick+int64(i) > at && planned == cell {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// check looks for vertex and swap conflicts between the plan and every other robot.
+func (t *ReservationTable) check(robotID string, startTick int64, cells []model.Cell) error {
+	endTick := startTick + int64(len(cells)) - 1
+
+	for other := range t.robots() {
+		if other == robotID {
+			continue
+		}
+
+		for i, cell := range cells {
+			tick := startTick + int64(i)
+			if occupied, ok := t.cellAt(other, tick); ok && occupied == cell {
+				return conflict(i, cell, fmt.Sprintf("robot %s's reserved cell", other))
+			}
+			if i == 0 {
+				continue
+			}
+			before, okBefore := t.cellAt(other, tick-1)
+			after, okAfter := t.cellAt(other, tick)
+			if okBefore && okAfter && before == cell && after == cells[i-1] {
+				return conflict(i, cell, fmt.Sprintf("a head-on swap with robot %s", other))
+			}
+		}
+
+		// once done, the robot rests on its last cell, so nobody may pass through it later
+		last := cells[len(cells)-1]
+		for _, plan := range t.plans[other] {
+			for i, cell := range plan.Cells {
+				if plan.StartTick+int64(i) > endTick && cell == last {
+					return conflict(len(cells)-1, last, fmt.Sprintf("robot %s's later path", other))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// conflict builds the PlanError for the i-th cell of a plan; cell 0 is the
+// start, so it is reported against the first command.
+func conflict(i int, cell model.Cell, reason string) error {
+	step := i - 1
+	if step < 0 {
+		step = 0
+	}
+	return &model.PlanError{Err: model.ErrPathConflict, Step: step, X: int(cell.X), Y: int(cell.Y), Reason: reason}
+}
+
+// cellAt is where the robot is expected at the tick: on a plan, waiting between
+// plans on the last cell of the previous one, or parked.
+func (t *ReservationTable) cellAt(robotID string, tick int64) (model.Cell, bool) {
+	plans := t.plans[robotID]
+	if len(plans) == 0 {
+		cell, ok := t.parked[robotID]
+		return cell, ok
+	}
+
+	if tick < plans[0].StartTick {
+		return plans[0].Cells[0], true
+	}
+	for i, plan := range plans {
+		if tick <= plan.endTick() {
+			if tick < plan.StartTick {
+				// between plans, still on the previous plan's last cell
+				previous := plans[i-1]
+				return previous.Cells[len(previous.Cells)-1], true
+			}
+			return plan.Cells[tick-plan.StartTick], true
+		}
+	}
+	last := plans[len(plans)-1]
+	return last.Cells[len(last.Cells)-1], true
+}
+
+// nextStartTick is when a new plan for the robot would start: right after its
+// last queued plan, or now if it has none.
+func (t *ReservationTable) nextStartTick(robotID string) int64 {
+	now := t.now()
+	plans := t.plans[robotID]
+	if len(plans) == 0 {
+		return now
+	}
+	return max(plans[len(plans)-1].endTick(), now)
+}
+
+// find locates a task's plan, returning an index of -1 if there is none.
+func (t *ReservationTable) find(taskID string) (string, int) {
+	for robotID, plans := range t.plans {
+		for i, plan := range plans {
+			if plan.TaskID == taskID {
+				return robotID, i
+			}
+		}
+	}
+	return "", -1
+}
+
+// robots lists every robot with plans or a parked cell.
+func (t *ReservationTable) robots() map[string]bool {
+	robots := make(map[string]bool, len(t.parked))
+	for robotID := range t.parked {
+		robots[robotID] = true
+	}
+	for robotID := range t.plans {
+		robots[robotID] = true
+	}
+	return robots
+}
+
+func (t *ReservationTable) now() int64 {
+	return int64(t.clock().Sub(t.epoch) / t.step)
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/model"
+)
+
+// newTestTable returns a table whose clock only moves when the returned func is called.
+func newTestTable() (*ReservationTable, func(ticks int)) {
+	now := time.Unix(0, 0)
+	table := NewReservationTable(time.Second)
+	table.epoch = now
+	table.clock = func() time.Time { return now }
+	return table, func(ticks int) { now = now.Add(time.Duration(ticks) * time.Second) }
+}
+
+func cells(coords ...uint) []model.Cell {
+	var path []model.Cell
+	for i := 0; i+1 < len(coords); i += 2 {
+		path = append(path, model.Cell{X: coords[i], Y: coords[i+1]})
+	}
+	return path
+}
+
+func TestReservationTable_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		other    []model.Cell // robot "b" plan, nil to leave it parked at (5,5)
+		plan     []model.Cell // robot "a" plan
+		conflict bool
+	}{
+		{"disjoint_paths", cells(5, 5, 5, 6, 5, 7), cells(0, 0, 1, 0, 2, 0), false},
+		{"same_cell_same_tick", cells(2, 2, 2, 1, 2, 0), cells(0, 0, 1, 0, 2, 0), true},
+		{"same_cell_different_tick", cells(2, 0, 3, 0, 4, 0), cells(0, 0, 1, 0, 2, 0), false},
+		{"head_on_swap", cells(2, 0, 1, 0, 0, 1), cells(0, 0, 1, 0, 2, 0, 3, 0), true},
+		{"through_parked_robot", nil, cells(4, 5, 5, 5, 6, 5), true},
+		{"parked_on_later_path", cells(3, 3, 3, 2, 3, 1, 3, 0), cells(2, 0, 3, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, _ := newTestTable()
+			table.Park("b", model.Cell{X: 5, Y: 5})
+			if tt.other != nil {
+				table.Reserve("b", "task_b", tt.other)
+			}
+
+			err := table.Check("a", tt.plan)
+
+			if tt.conflict && !errors.Is(err, model.ErrPathConflict) {
+				t.Errorf("Check() expected ErrPathConflict, got %v", err)
+			}
+			if !tt.conflict && err != nil {
+				t.Errorf("Check() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestReservationTable_QueuedPlanStartsAfterPrevious(t *testing.T) {
+	table, _ := newTestTable()
+	table.Reserve("a", "task_1", cells(0, 0, 1, 0, 2, 0))
+	queued := table.Reserve("a", "task_2", cells(2, 0, 2, 1))
+
+	if queued.StartTick != 2 {
+		t.Errorf("queued plan starts at tick %d, expected 2", queued.StartTick)
+	}
+
+	// robot "b" crossing (2,1) at tick 3 meets the queued plan
+	if err := table.Check("b", cells(2, 3, 2, 2, 2, 1)); err == nil {
+		t.Errorf("Check() expected conflict with queued plan")
+	}
+}
+
+func TestReservationTable_AdvanceAndRelease(t *testing.T) {
+	table, advance := newTestTable()
+	table.Reserve("a", "task_1", cells(0, 0, 1, 0, 2, 0))
+
+	// robot "b" wants (1,0) at tick 2, after "a" should have passed through
+	plan := cells(1, 2, 1, 1, 1, 0, 1, 1)
+	if err := table.Check("b", plan); err != nil {
+		t.Fatalf("Check() unexpected error = %v", err)
+	}
+
+	// "a" is running late: only at its first step by tick 2
+	advance(2)
+	table.Advance("task_1", 1)
+	if err := table.Check("b", cells(1, 0, 1, 1)); err == nil {
+		t.Errorf("Check() expected conflict with delayed robot")
+	}
+
+	// once released, "a" is parked where it stopped
+	table.Release("task_1", &model.Cell{X: 1, Y: 0})
+	if len(table.Reservations()) != 0 {
+		t.Errorf("expected no reservations after release")
+	}
+	if err := table.Check("b", cells(1, 2, 1, 1, 1, 0)); err == nil {
+		t.Errorf("Check() expected conflict with parked robot")
+	}
+	if err := table.Check("b", cells(1, 2, 2, 2)); err != nil {
+		t.Errorf("Check() unexpected error = %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"warehouse-robots/backend/api/model"
+)
+
+// RegisteredRobot is an SDK robot handle under its stable ID.
+type RegisteredRobot struct {
+	ID    string
+	Robot model.Robot
+}
+
+// RobotRegistry maps stable robot IDs (serial numbers such as "robot-1") to SDK
+// robot handles and is the single place services resolve robots from, so IDs
+// do not change when the SDK reorders its robots.
+//
+// Decommissioned robots are remembered, so they can be registered again under
+// the same ID without going back to the SDK.
+type RobotRegistry struct {
+	mu     sync.RWMutex
+	robots map[string]model.Robot
+	active map[string]bool
+	order  []string // registration order, for stable listings
+}
+
+// NewRobotRegistry creates an empty registry.
+func NewRobotRegistry() *RobotRegistry {
+	return &RobotRegistry{
+		robots: make(map[string]model.Robot),
+		active: make(map[string]bool),
+	}
+}
+
+// Discover registers every robot the SDK reports. A robot that knows its own
+// serial number (model.IdentifiedRobot) keeps it; any other robot is named
+// "robot-N" after its position in the SDK list. That name depends on the order
+// the SDK lists its robots in, so it is only given out here, at startup.
+func (r *RobotRegistry) Discover(warehouse model.Warehouse) {
+	for i, robot := range warehouse.Robots() {
+		if err := r.Register(sdkRobotID(i, robot), robot); err != nil {
+			log.Printf("robot registry: skip SDK robot %d: %v", i, err)
+		}
+	}
+}
+
+// FindSDKRobot looks a robot up by its serial number among the robots the SDK
+// reports now, e.g. one that came online after Discover. Robots that do not
+// report a serial (model.IdentifiedRobot) are never found.
+func FindSDKRobot(warehouse model.Warehouse, id string) (model.Robot, bool) {
+	for _, robot := range warehouse.Robots() {
+		if identified, ok := robot.(model.IdentifiedRobot); ok && identified.GetID() != "" && identified.GetID() == id {
+			return robot, true
+		}
+	}
+	return nil, false
+}
+
+// sdkRobotID is the ID Discover gives the i-th robot in the SDK list.
+func sdkRobotID(i int, robot model.Robot) string {
+	if identified, ok := robot.(model.IdentifiedRobot); ok && identified.GetID() != "" {
+		return identified.GetID()
+	}
+	return fmt.Sprintf("robot-%d", i+1)
+}
+
+// Register puts a robot in service under the ID. A nil robot brings back a
+// decommissioned robot with its previous handle.
+//
+// Error Returns:
+//   - ErrRobotIDInvalid: the ID is empty.
+//   - ErrRobotAlreadyRegistered: a robot is in service under the ID.
+//   - ErrRobotNotFound: no robot given and none was decommissioned under the ID.
+func (r *RobotRegistry) Register(id string, robot model.Robot) error {
+	if id == "" {
+		return model.ErrRobotIDInvalid
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.active[id] {
+		return model.ErrRobotAlreadyRegistered
+	}
+
+	known, seen := r.robots[id]
+	if robot == nil {
+		if !seen {
+			return model.ErrRobotNotFound
+		}
+		robot = known
+	}
+
+	r.robots[id] = robot
+	r.active[id] = true
+	if !seen {
+		r.order = append(r.order, id)
+	}
+	return nil
+}
+
+// Decommission takes the robot out of service; it no longer resolves or lists.
+// Returns ErrRobotNotFound if no robot is in service under the ID.
+func (r *RobotRegistry) Decommission(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active[id] {
+		return model.ErrRobotNotFound
+	}
+	r.active[id] = false
+	return nil
+}
+
+// Get resolves a robot in service by its ID.
+// Returns ErrRobotNotFound for unknown or decommissioned robots.
+func (r *RobotRegistry) Get(id string) (model.Robot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if !r.active[id] {
+		return nil, model.ErrRobotNotFound
+	}
+	return r.robots[id], nil
+}
+
+// List returns the robots in service in registration order.
+func (r *RobotRegistry) List() []RegisteredRobot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	robots := make([]RegisteredRobot, 0, len(r.order))
+	for _, id := range r.order {
+		if r.active[id] {
+			robots = append(robots, RegisteredRobot{ID: id, Robot: r.robots[id]})
+		}
+	}
+	return robots
+}
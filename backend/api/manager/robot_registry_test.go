@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"errors"
+	"testing"
+	"warehouse-robots/backend/api/model"
+)
+
+type registryRobot struct {
+	model.Robot
+	serial string
+}
+
+// identifiedRobot also reports its serial number to the registry.
+type identifiedRobot struct {
+	registryRobot
+}
+
+func (r *identifiedRobot) GetID() string {
+	return r.serial
+}
+
+type registryWarehouse []model.Robot
+
+func (w registryWarehouse) Robots() []model.Robot {
+	return w
+}
+
+func TestRobotRegistry_Discover(t *testing.T) {
+	serial := &identifiedRobot{registryRobot{serial: "AX-7"}}
+	anonymous := &registryRobot{}
+
+	// the SDK lists the robots in a different order each time
+	for _, warehouse := range []registryWarehouse{{serial, anonymous}, {anonymous, serial}} {
+		registry := NewRobotRegistry()
+		registry.Discover(warehouse)
+
+		robot, err := registry.Get("AX-7")
+		if err != nil || robot != serial {
+			t.Errorf("expected AX-7 to resolve to its own handle, got %v (%v)", robot, err)
+		}
+		if len(registry.List()) != 2 {
+			t.Errorf("expected 2 robots, got %d", len(registry.List()))
+		}
+	}
+}
+
+func TestRobotRegistry_RegisterAndDecommission(t *testing.T) {
+	registry := NewRobotRegistry()
+	robot := &registryRobot{}
+
+	steps := []struct {
+		name string
+		run  func() error
+		err  error
+	}{
+		{"register", func() error { return registry.Register("robot-1", robot) }, nil},
+		{"register_twice", func() error { return registry.Register("robot-1", robot) }, model.ErrRobotAlreadyRegistered},
+		{"empty_id", func() error { return registry.Register("", robot) }, model.ErrRobotIDInvalid},
+		{"decommission", func() error { return registry.Decommission("robot-1") }, nil},
+		{"resolve_decommissioned", func() error { _, err := registry.Get("robot-1"); return err }, model.ErrRobotNotFound},
+		{"decommission_twice", func() error { return registry.Decommission("robot-1") }, model.ErrRobotNotFound},
+		{"bring_back_unknown", func() error { return registry.Register("robot-2", nil) }, model.ErrRobotNotFound},
+		{"bring_back", func() error { return registry.Register("robot-1", nil) }, nil},
+		{"resolve_again", func() error { _, err := registry.Get("robot-1"); return err }, nil},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); !errors.Is(err, step.err) {
+			t.Fatalf("%s: error = %v, expected %v", step.name, err, step.err)
+		}
+	}
+
+	if robots := registry.List(); len(robots) != 1 || robots[0].Robot != robot {
+		t.Errorf("expected robot-1 back with its handle, got %+v", robots)
+	}
+}
+
+func TestFindSDKRobot(t *testing.T) {
+	serial := &identifiedRobot{registryRobot{serial: "AX-7"}}
+	anonymous := &registryRobot{}
+	warehouse := registryWarehouse{anonymous, serial}
+
+	if robot, ok := FindSDKRobot(warehouse, "AX-7"); !ok || robot != serial {
+		t.Errorf("expected AX-7 by its serial, got %v", robot)
+	}
+	// a name derived from the SDK list order is not stable, so it never matches
+	if robot, ok := FindSDKRobot(warehouse, "robot-1"); ok {
+		t.Errorf("expected robot-1 to be unknown: the first robot has no serial, got %v", robot)
+	}
+	if _, ok := FindSDKRobot(warehouse, ""); ok {
+		t.Error("expected an empty ID to be unknown")
+	}
+}
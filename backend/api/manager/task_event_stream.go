@@ -0,0 +1,251 @@
+package manager
+
+import (
+	"sync"
+	"time"
+	"warehouse-robots/backend/api/model"
+)
+
+const (
+	// taskEventHistory is how many events are kept per task for clients that
+	// resume; enough for the longest task the API accepts.
+	taskEventHistory = 1024
+	// endedTaskHistory is how many ended tasks keep their history; older ones
+	// are forgotten, and subscribers fall back to the task record for them.
+	endedTaskHistory = 256
+	// endedTaskIDs is how many ended tasks are remembered as ended after their
+	// history is forgotten, so that an event delivered again for one of them
+	// (the outbox delivers at least once) does not reopen its stream.
+	endedTaskIDs = 64 * 1024
+	// subscriberBuffer is how many events a subscriber may fall behind before
+	// it is dropped.
+	subscriberBuffer = 64
+)
+
+// TaskEventStream keeps the events of every task and fans them out to
+// subscribers. Each task has its own sequence of event IDs and a bounded
+// history, so a subscriber that reconnects can pick up after the last event
+// it saw.
+//
+// A subscriber that does not keep up is dropped rather than slowing down the
+// task monitor; it can resume from its last event ID. Once a task has ended,
+// its subscribers are closed and any later event for it is ignored. Only the
+// most recent ended tasks keep their history, and only the IDs of a larger
+// number of them are remembered, so memory does not grow with every task the
+// service runs.
+//
+// Fleet subscribers (see SubscribeFleet) follow the events of all tasks.
+//
+// A nil stream discards everything, so publishing is optional.
+type TaskEventStream struct {
+	mu          sync.Mutex
+	tasks       map[string]*taskEvents
+	ended       []string            // IDs of the remembered ended tasks, oldest first
+	endedIDs    map[string]struct{} // the same IDs, as a set
+	subscribers map[string]map[*TaskEventSubscription]struct{}
+	fleet       map[*FleetSubscription]struct{}
+}
+
+// taskEvents is the history of one task.
+type taskEvents struct {
+	lastID int64
+	events []model.TaskEvent
+	ended  bool
+}
+
+// TaskEventSubscription receives the events of one task.
+type TaskEventSubscription struct {
+	// Backlog holds the events already published after the requested ID.
+	Backlog []model.TaskEvent
+	// Events delivers later events. It is closed once the task has ended, the
+	// subscriber fell behind, or the subscription was closed.
+	Events <-chan model.TaskEvent
+	// Ended reports whether the task had already ended when subscribing.
+	Ended bool
+
+	events chan model.TaskEvent
+	taskID string
+	stream *TaskEventStream
+}
+
+// NewTaskEventStream creates an empty stream.
+func NewTaskEventStream() *TaskEventStream {
+	return &TaskEventStream{
+		tasks:       make(map[string]*taskEvents),
+		endedIDs:    make(map[string]struct{}),
+		subscribers: make(map[string]map[*TaskEventSubscription]struct{}),
+		fleet:       make(map[*FleetSubscription]struct{}),
+	}
+}
+
+// Publish numbers the event, records it in the task's history and hands it to
+// the task's subscribers and the fleet subscribers.
+func (s *TaskEventStream) Publish(event model.TaskEvent) {
+	if s == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := s.tasks[event.TaskID]
+	if history == nil {
+		if _, ended := s.endedIDs[event.TaskID]; ended {
+			return
+		}
+		history = &taskEvents{}
+		s.tasks[event.TaskID] = history
+	}
+	if history.ended {
+		return
+	}
+
+	history.lastID++
+	event.ID = history.lastID
+	history.events = append(history.events, event)
+	if len(history.events) > taskEventHistory {
+		history.events = history.events[len(history.events)-taskEventHistory:]
+	}
+	history.ended = event.Type == model.TaskEventEnd
+	if history.ended {
+		s.forgetEndedTasks(event.TaskID)
+	}
+
+	for sub := range s.subscribers[event.TaskID] {
+		select {
+		case sub.events <- event:
+		default:
+			// too slow; it can come back with its last event ID
+			s.unsubscribe(sub)
+			continue
+		}
+		if history.ended {
+			s.unsubscribe(sub)
+		}
+	}
+
+	for sub := range s.fleet {
+		if !sub.offer(event) {
+			s.unsubscribeFleet(sub)
+		}
+	}
+}
+
+// forgetEndedTasks records that the task ended, drops the history of the
+// ended task that falls beyond endedTaskHistory and forgets the oldest ended
+// tasks beyond endedTaskIDs; s.mu must be held.
+func (s *TaskEventStream) forgetEndedTasks(taskID string) {
+	s.ended = append(s.ended, taskID)
+	s.endedIDs[taskID] = struct{}{}
+	if i := len(s.ended) - 1 - endedTaskHistory; i >= 0 {
+		delete(s.tasks, s.ended[i])
+	}
+	for len(s.ended) > endedTaskIDs {
+		delete(s.endedIDs, s.ended[0])
+		s.ended = s.ended[1:]
+	}
+}
+
+// Subscribe returns the events of a task published after the given ID and a
+// channel for the ones still to come.
+func (s *TaskEventStream) Subscribe(taskID string, afterID int64) *TaskEventSubscription {
+	sub := &TaskEventSubscription{
+		events: make(chan model.TaskEvent, subscriberBuffer),
+		taskID: taskID,
+		stream: s,
+	}
+	sub.Events = sub.events
+
+	if s == nil {
+		close(sub.events)
+		return sub
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if history := s.tasks[taskID]; history != nil {
+		for _, event := range history.events {
+			if event.ID > afterID {
+				sub.Backlog = append(sub.Backlog, event)
+			}
+		}
+		if history.ended {
+			sub.Ended = true
+			close(sub.events)
+			return sub
+		}
+	}
+
+	if s.subscribers[taskID] == nil {
+		s.subscribers[taskID] = make(map[*TaskEventSubscription]struct{})
+	}
+	s.subscribers[taskID][sub] = struct{}{}
+	return sub
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (sub *TaskEventSubscription) Close() {
+	if sub.stream == nil {
+		return
+	}
+	sub.stream.mu.Lock()
+	defer sub.stream.mu.Unlock()
+	sub.stream.unsubscribe(sub)
+}
+
+// unsubscribe removes the subscriber and closes its channel; s.mu must be held.
+func (s *TaskEventStream) unsubscribe(sub *TaskEventSubscription) {
+	subs, ok := s.subscribers[sub.taskID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(s.subscribers, sub.taskID)
+	}
+	close(sub.events)
+}
+
+// Follow subscribes the stream to the task events on the bus. The stream only
+// buffers, so the bus may wait for it rather than lose events that clients
+// resume from.
+func (s *TaskEventStream) Follow(bus *EventBus) *EventSubscription {
+	return bus.Subscribe("task event stream", s.apply, SubscribeOptions{
+		Events: []string{
+			model.EventTaskCreated,
+			model.EventTaskPositionUpdated,
+			model.EventTaskCompleted,
+			model.EventTaskFailed,
+			model.EventTaskCancelled,
+		},
+		Buffer:   256,
+		Shards:   4,
+		Overflow: BlockWhenFull,
+	})
+}
+
+// apply publishes the stream events a domain event stands for.
+func (s *TaskEventStream) apply(event model.DomainEvent) {
+	at := event.OccurredAt()
+	switch e := event.(type) {
+	case model.TaskCreated:
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventStatus, Status: model.TaskStatusPending, At: at})
+	case model.TaskPositionUpdated:
+		position := e.Position
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventPosition, Status: model.TaskStatusPending, Position: &position, At: at})
+	case model.TaskCompleted:
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventEnd, Status: model.TaskStatusCompleted, At: at})
+	case model.TaskFailed:
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventError, Status: model.TaskStatusPending, Error: e.Reason, At: at})
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventEnd, Status: model.TaskStatusFailed, Error: e.Reason, At: at})
+	case model.TaskCancelled:
+		s.Publish(model.TaskEvent{TaskID: e.TaskID, RobotID: e.RobotID, Type: model.TaskEventEnd, Status: model.TaskStatusCancelled, Error: e.Reason, At: at})
+	}
+}
@@ -0,0 +1,125 @@
+package manager
+
+import (
+	"fmt"
+	"testing"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestTaskEventStream_Resume(t *testing.T) {
+	stream := NewTaskEventStream()
+	stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventStatus})
+	stream.Publish(model.TaskEvent{TaskID: "b", Type: model.TaskEventStatus})
+	stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventPosition})
+
+	sub := stream.Subscribe("a", 1)
+	defer sub.Close()
+
+	if len(sub.Backlog) != 1 || sub.Backlog[0].ID != 2 || sub.Backlog[0].Type != model.TaskEventPosition {
+		t.Fatalf("expected only event 2 of task a in the backlog, got %+v", sub.Backlog)
+	}
+
+	stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventEnd, Status: model.TaskStatusCompleted})
+
+	event, ok := <-sub.Events
+	if !ok || event.ID != 3 || event.Type != model.TaskEventEnd {
+		t.Fatalf("expected end event 3, got %+v (open=%t)", event, ok)
+	}
+	if _, ok := <-sub.Events; ok {
+		t.Error("expected the subscription to close after the end event")
+	}
+}
+
+func TestTaskEventStream_Ended(t *testing.T) {
+	stream := NewTaskEventStream()
+	stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventEnd, Status: model.TaskStatusCancelled})
+	// a monitor that is still winding down must not extend the stream
+	stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventPosition})
+
+	sub := stream.Subscribe("a", 0)
+	if !sub.Ended || len(sub.Backlog) != 1 {
+		t.Fatalf("expected an ended stream with one event, got ended=%t backlog=%+v", sub.Ended, sub.Backlog)
+	}
+	if _, ok := <-sub.Events; ok {
+		t.Error("expected no live events for an ended task")
+	}
+	sub.Close()
+}
+
+func TestTaskEventStream_SlowSubscriberDropped(t *testing.T) {
+	stream := NewTaskEventStream()
+	sub := stream.Subscribe("a", 0)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		stream.Publish(model.TaskEvent{TaskID: "a", Type: model.TaskEventPosition})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("expected the %d buffered events before the drop, got %d", subscriberBuffer, received)
+	}
+
+	// it resumes where it left off
+	resumed := stream.Subscribe("a", int64(received))
+	defer resumed.Close()
+	if len(resumed.Backlog) != 1 || resumed.Backlog[0].ID != subscriberBuffer+1 {
+		t.Errorf("expected the missed event in the backlog, got %+v", resumed.Backlog)
+	}
+}
+
+func TestTaskEventStream_EndedTasksForgotten(t *testing.T) {
+	stream := NewTaskEventStream()
+	for i := 0; i <= endedTaskHistory; i++ {
+		taskID := fmt.Sprintf("task-%d", i)
+		stream.Publish(model.TaskEvent{TaskID: taskID, Type: model.TaskEventStatus})
+		stream.Publish(model.TaskEvent{TaskID: taskID, Type: model.TaskEventEnd, Status: model.TaskStatusCompleted})
+	}
+	// a running task is never forgotten
+	stream.Publish(model.TaskEvent{TaskID: "running", Type: model.TaskEventStatus})
+
+	if len(stream.tasks) != endedTaskHistory+1 {
+		t.Errorf("expected %d ended tasks and the running one kept, got %d", endedTaskHistory, len(stream.tasks))
+	}
+
+	oldest := stream.Subscribe("task-0", 0)
+	defer oldest.Close()
+	if oldest.Ended || len(oldest.Backlog) != 0 {
+		t.Errorf("expected the oldest ended task to be forgotten, got ended=%t backlog=%+v", oldest.Ended, oldest.Backlog)
+	}
+
+	newest := stream.Subscribe(fmt.Sprintf("task-%d", endedTaskHistory), 0)
+	if !newest.Ended || len(newest.Backlog) != 2 {
+		t.Errorf("expected the newest ended task to keep its history, got ended=%t backlog=%+v", newest.Ended, newest.Backlog)
+	}
+}
+
+func TestTaskEventStream_RedeliveryAfterForgetting(t *testing.T) {
+	stream := NewTaskEventStream()
+	for i := 0; i <= endedTaskHistory; i++ {
+		taskID := fmt.Sprintf("task-%d", i)
+		stream.Publish(model.TaskEvent{TaskID: taskID, Type: model.TaskEventStatus})
+		stream.Publish(model.TaskEvent{TaskID: taskID, Type: model.TaskEventEnd, Status: model.TaskStatusCompleted})
+	}
+	resumed := stream.Subscribe("task-0", 2)
+	defer resumed.Close()
+	fleet := stream.SubscribeFleet(FleetFilter{})
+	defer fleet.Close()
+
+	// the outbox delivers a position of task-0 again after its history is gone
+	stream.Publish(model.TaskEvent{TaskID: "task-0", Type: model.TaskEventPosition, Position: &model.Position{X: 1}})
+
+	if _, reopened := stream.tasks["task-0"]; reopened {
+		t.Error("expected the event of an ended task to be dropped")
+	}
+	select {
+	case event := <-resumed.Events:
+		t.Errorf("expected nothing after the end event, got %+v", event)
+	default:
+	}
+	if events, _ := fleet.Drain(); len(events) != 0 {
+		t.Errorf("expected nothing sent to the fleet, got %+v", events)
+	}
+}
@@ -2,74 +2,220 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
+	"warehouse-robots/backend/api/constant"
 	"warehouse-robots/backend/api/dao"
 	"warehouse-robots/backend/api/model"
 )
 
 // TaskMonitor manages the lifecycle of goroutines that watch robot task channels.
-// It ensures updates (status, position, errors) are persisted into the repository
-// and provides graceful shutdown.
+// It ensures updates (status, position, errors) are persisted into the repository,
+// keeps the crate inventory in step with grabs and drops, keeps path reservations
+// in step with the robot, and provides graceful shutdown. Task lifecycle events
+// reach the event bus through the repository's outbox; the monitor only
+// publishes the robot state changes it observes.
+//
+// The container owns a single TaskMonitor and every service that starts, stops
+// or cancels monitoring shares it, so a task is only ever watched once and the
+// running monitors can be listed, see Monitors.
 type TaskMonitor struct {
-	repository dao.ITaskRepository
-	monitors   map[string]context.CancelFunc
-	mu         sync.Mutex
-	wg         sync.WaitGroup
+	repository      dao.ITaskRepository
+	crateRepository dao.ICrateRepository
+	reservations    *ReservationTable // optional
+	bus             *EventBus         // optional
+	monitors        map[string]*monitorEntry
+	onFailure       func(taskID string)
+	mu              sync.Mutex
+	wg              sync.WaitGroup
 }
 
-func NewTaskMonitor(repo dao.ITaskRepository) *TaskMonitor {
+// monitorEntry is the registration of one monitor goroutine. A task can be
+// monitored again after it is re-enqueued, so cleanup compares entries rather
+// than task IDs to avoid removing the newer registration.
+type monitorEntry struct {
+	cancel    context.CancelFunc
+	startedAt time.Time
+	deadline  time.Time
+	// done is closed once the monitor goroutine has exited
+	done chan struct{}
+
+	// guarded by TaskMonitor.mu
+	lastEventAt time.Time
+	events      int
+}
+
+// MonitorInfo describes a running monitor.
+type MonitorInfo struct {
+	TaskID    string
+	StartedAt time.Time
+	// LastEventAt is when the SDK last reported on the task; zero until it does
+	LastEventAt time.Time
+	// Events counts the position updates and errors received
+	Events int
+	// Deadline is when the task times out
+	Deadline time.Time
+}
+
+// NewTaskMonitor creates a monitor. reservations may be nil when no path
+// reservations need to follow the tasks, and bus may be nil when nobody
+// listens to task events.
+func NewTaskMonitor(
+	repo dao.ITaskRepository,
+	crateRepo dao.ICrateRepository,
+	reservations *ReservationTable,
+	bus *EventBus,
+) *TaskMonitor {
 	return &TaskMonitor{
-		repository: repo,
-		monitors:   make(map[string]context.CancelFunc),
+		repository:      repo,
+		crateRepository: crateRepo,
+		reservations:    reservations,
+		bus:             bus,
+		monitors:        make(map[string]*monitorEntry),
 	}
 }
 
+// OnFailure registers a handler that runs after a monitored task is marked FAILED,
+// e.g. to re-validate the tasks queued behind it.
+func (tm *TaskMonitor) OnFailure(handler func(taskID string)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.onFailure = handler
+}
+
 // StartMonitoring creates a goroutine that listens for position and error events
 // from a robot task. It registers a cancel function to allow external shutdown.
-// Each monitor has a maximum lifetime of 30 minutes.
+// Each monitor has a maximum lifetime of constant.TaskMonitorTimeout.
 func (tm *TaskMonitor) StartMonitoring(
 	taskID string,
 	positionChan <-chan model.RobotState,
 	errorChan <-chan error,
 ) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	now := time.Now()
+	deadline := now.Add(constant.TaskMonitorTimeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	entry := &monitorEntry{cancel: cancel, startedAt: now, deadline: deadline, done: make(chan struct{})}
 
 	tm.mu.Lock()
-	tm.monitors[taskID] = cancel
+	if previous, exists := tm.monitors[taskID]; exists {
+		previous.cancel()
+	}
+	tm.monitors[taskID] = entry
 	tm.mu.Unlock()
 
 	// Increment WaitGroup counter before starting the goroutine.
 	// This ensures Shutdown() can wait for this monitor to exit
 	tm.wg.Add(1)
-	go tm.monitorTask(ctx, taskID, positionChan, errorChan)
+	go tm.monitorTask(ctx, entry, taskID, positionChan, errorChan)
+}
+
+// StopMonitoring detaches the monitor from a task without touching its status,
+// for when the task is about to be re-enqueued or cancelled by the caller.
+// Returns false if no monitor was running for the task.
+func (tm *TaskMonitor) StopMonitoring(taskID string) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	entry, exists := tm.monitors[taskID]
+	if !exists {
+		return false
+	}
+	entry.cancel()
+	delete(tm.monitors, taskID)
+	return true
+}
+
+// Monitors lists the running monitors, the longest running first.
+func (tm *TaskMonitor) Monitors() []MonitorInfo {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	monitors := make([]MonitorInfo, 0, len(tm.monitors))
+	for taskID, entry := range tm.monitors {
+		monitors = append(monitors, MonitorInfo{
+			TaskID:      taskID,
+			StartedAt:   entry.startedAt,
+			LastEventAt: entry.lastEventAt,
+			Events:      entry.events,
+			Deadline:    entry.deadline,
+		})
+	}
+
+	sort.Slice(monitors, func(i, j int) bool {
+		if !monitors[i].StartedAt.Equal(monitors[j].StartedAt) {
+			return monitors[i].StartedAt.Before(monitors[j].StartedAt)
+		}
+		return monitors[i].TaskID < monitors[j].TaskID
+	})
+	return monitors
+}
+
+// recordEvent notes that the SDK reported on the monitored task.
+func (tm *TaskMonitor) recordEvent(entry *monitorEntry) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	entry.lastEventAt = time.Now()
+	entry.events++
 }
 
 // monitorTask is the goroutine that listens to channels
 // It updates task state in the repository until the task completes, fails, or times out.
 func (tm *TaskMonitor) monitorTask(
 	ctx context.Context,
+	entry *monitorEntry,
 	taskID string,
 	positionChan <-chan model.RobotState,
 	errorChan <-chan error,
 ) {
 	defer tm.wg.Done()
-	defer tm.cleanup(taskID)
+	defer tm.cleanup(taskID, entry)
+
+	// last position seen, used to detect crate grabs and drops
+	var last *model.RobotState
+	// index of the last position seen; the first one is the start cell
+	step := -1
+	// set once the SDK reported the task cancelled; the positions it still
+	// sends are where the robot got to before stopping
+	cancelled := false
 
 	for {
 		select {
 		case position, ok := <-positionChan:
 			if !ok {
+				// The SDK may report an error and close both channels together,
+				// so drain the error channel before treating the close as success.
+				select {
+				case taskErr, errOk := <-errorChan:
+					if errOk && errors.Is(taskErr, model.ErrSDKTaskCancelled) {
+						cancelled = true
+					} else if errOk && taskErr != nil {
+						tm.fail(entry, taskID, taskErr.Error(), last)
+						return
+					}
+				default:
+				}
+
+				// Cancelled through the SDK: whoever cancelled it records the status
+				if cancelled {
+					tm.releaseReservation(entry, taskID, last)
+					return
+				}
+
 				// Channel closed - task completed successfully
 				err := tm.repository.UpdateStatus(taskID, model.TaskStatusCompleted, "")
 				if err != nil {
 					// Log error but don't return - channel is closed anyway
 					fmt.Printf("Error updating status to completed: %v\n", err)
 				}
+				tm.releaseReservation(entry, taskID, last)
 				return
 			}
 
+			tm.recordEvent(entry)
+
 			// Update position in repository
 			pos := &model.Position{
 				X:        position.X,
@@ -85,6 +231,17 @@ func (tm *TaskMonitor) monitorTask(
 				continue
 			}
 
+			if last != nil {
+				tm.syncCrateInventory(task.RobotID, *last, position)
+			}
+			moved := last == nil || *last != position
+			last = &position
+
+			step++
+			if tm.reservations != nil {
+				tm.reservations.Advance(taskID, step)
+			}
+
 			status := model.TaskStatusPending
 			if task != nil && task.Status == model.TaskStatusCompleted {
 				status = task.Status // Don't override completed status
@@ -94,66 +251,145 @@ func (tm *TaskMonitor) monitorTask(
 			if err != nil {
 				fmt.Printf("Error updating position for task %s: %v\n", taskID, err)
 			}
+			if moved {
+				tm.bus.Publish(model.RobotStateChanged{RobotID: task.RobotID, TaskID: taskID, State: position, At: time.Now()})
+			}
 
 		case err, ok := <-errorChan:
+			if ok && errors.Is(err, model.ErrSDKTaskCancelled) {
+				tm.recordEvent(entry)
+				cancelled = true
+				errorChan = nil // keep recording positions until the SDK closes them
+				continue
+			}
 			if ok && err != nil {
+				tm.recordEvent(entry)
 				// Error received - task failed
-				updateErr := tm.repository.UpdateStatus(taskID, model.TaskStatusFailed, err.Error())
-				if updateErr != nil {
-					fmt.Printf("Error updating status to failed: %v\n", updateErr)
-				}
+				tm.fail(entry, taskID, err.Error(), last)
 				return
 			}
 
 		case <-ctx.Done():
 			// Timeout or cancelled
 			if ctx.Err() == context.DeadlineExceeded {
-				err := tm.repository.UpdateStatus(taskID, model.TaskStatusFailed, "task timeout")
-				if err != nil {
-					fmt.Printf("Error updating status to timeout: %v\n", err)
-				}
+				tm.fail(entry, taskID, "task timeout", last)
+				return
 			}
-			// If cancelled, status should already be updated elsewhere
+			// If cancelled, status should already be updated elsewhere.
+			// Keep reading so the SDK never blocks on a channel nobody listens to.
+			go drain(positionChan, errorChan)
 			return
 		}
 	}
 }
 
+// fail marks the task FAILED and notifies the failure handler, if any.
+func (tm *TaskMonitor) fail(entry *monitorEntry, taskID, errorMsg string, last *model.RobotState) {
+	if err := tm.repository.UpdateStatus(taskID, model.TaskStatusFailed, errorMsg); err != nil {
+		fmt.Printf("Error updating status to failed: %v\n", err)
+	}
+	tm.releaseReservation(entry, taskID, last)
+
+	tm.mu.Lock()
+	handler := tm.onFailure
+	tm.mu.Unlock()
+
+	if handler != nil {
+		handler(taskID)
+	}
+}
+
+// releaseReservation frees the task's path, parking the robot where it was last seen.
+// A monitor that was stopped leaves the path alone: the task may have been
+// re-enqueued under the same ID, and the reservation now belongs to its new plan.
+func (tm *TaskMonitor) releaseReservation(entry *monitorEntry, taskID string, last *model.RobotState) {
+	if tm.reservations == nil {
+		return
+	}
+
+	// held across Release so that StopMonitoring cannot detach the entry in between
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.monitors[taskID] != entry {
+		return
+	}
+	var at *model.Cell
+	if last != nil {
+		at = &model.Cell{X: last.X, Y: last.Y}
+	}
+	tm.reservations.Release(taskID, at)
+}
+
+// drain discards whatever the SDK still sends for a task that is no longer monitored.
+func drain(positionChan <-chan model.RobotState, errorChan <-chan error) {
+	for positionChan != nil || errorChan != nil {
+		select {
+		case _, ok := <-positionChan:
+			if !ok {
+				positionChan = nil
+			}
+		case _, ok := <-errorChan:
+			if !ok {
+				errorChan = nil
+			}
+		}
+	}
+}
+
+// syncCrateInventory records a grab or drop in the crate inventory when the
+// robot's crate flag flips between two consecutive position updates.
+func (tm *TaskMonitor) syncCrateInventory(robotID string, previous, current model.RobotState) {
+	switch {
+	case !previous.HasCrate && current.HasCrate:
+		if _, err := tm.crateRepository.Pick(robotID, current.X, current.Y); err != nil {
+			fmt.Printf("Error recording crate grab by robot %s: %v\n", robotID, err)
+		}
+	case previous.HasCrate && !current.HasCrate:
+		if err := tm.crateRepository.Drop(robotID, current.X, current.Y); err != nil {
+			fmt.Printf("Error recording crate drop by robot %s: %v\n", robotID, err)
+		}
+	}
+}
+
 // cleanup removes the monitor for a task and cancels its context.
-func (tm *TaskMonitor) cleanup(taskID string) {
+func (tm *TaskMonitor) cleanup(taskID string, entry *monitorEntry) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	if cancel, exists := tm.monitors[taskID]; exists {
-		cancel()
+	entry.cancel()
+	close(entry.done)
+	if current, exists := tm.monitors[taskID]; exists && current == entry {
 		delete(tm.monitors, taskID)
 	}
 }
 
-// CancelTask stops monitoring and cancels the task explicitly.
-func (tm *TaskMonitor) CancelTask(taskID string) error {
+// CancelTask marks the task CANCELLED with the reason. Call it once the SDK
+// accepted the cancellation: a running monitor is first given up to
+// constant.TaskCancelReportTimeout to record where the robot stopped, and is
+// stopped after that.
+func (tm *TaskMonitor) CancelTask(taskID, reason string) error {
 	tm.mu.Lock()
+	entry, exists := tm.monitors[taskID]
+	tm.mu.Unlock()
 
-	cancel, exists := tm.monitors[taskID]
-	if !exists {
-		tm.mu.Unlock()
-		return fmt.Errorf("no monitor found for task %s", taskID)
+	if exists {
+		select {
+		case <-entry.done:
+		case <-time.After(constant.TaskCancelReportTimeout):
+		}
 	}
-
-	cancel()
-	delete(tm.monitors, taskID)
-	tm.mu.Unlock() // Unlock before calling repository to avoid potential deadlock
+	tm.StopMonitoring(taskID)
 
 	// Update status in repository
-	return tm.repository.UpdateStatus(taskID, model.TaskStatusCancelled, "cancelled by user")
+	return tm.repository.UpdateStatus(taskID, model.TaskStatusCancelled, reason)
 }
 
 // Shutdown gracefully stops all monitors
 func (tm *TaskMonitor) Shutdown(ctx context.Context) error {
 	tm.mu.Lock()
 	// Cancel all monitors
-	for _, cancel := range tm.monitors {
-		cancel()
+	for _, entry := range tm.monitors {
+		entry.cancel()
 	}
 	tm.mu.Unlock()
 
@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestTaskMonitor_Monitors(t *testing.T) {
+	repo := dao.NewInMemoryTaskRepository()
+	_ = repo.Create(&model.Task{TaskID: "task", RobotID: "robot", Status: model.TaskStatusPending})
+	monitor := NewTaskMonitor(repo, dao.NewInMemoryCrateRepository(), nil, nil)
+	defer monitor.Shutdown(context.Background())
+
+	positions := make(chan model.RobotState)
+	started := time.Now()
+	monitor.StartMonitoring("task", positions, make(chan error))
+
+	monitors := monitor.Monitors()
+	if len(monitors) != 1 || monitors[0].TaskID != "task" || !monitors[0].LastEventAt.IsZero() || monitors[0].Events != 0 {
+		t.Fatalf("expected one monitor without events yet, got %+v", monitors)
+	}
+	if deadline := monitors[0].Deadline; deadline.Before(started.Add(constant.TaskMonitorTimeout)) ||
+		deadline.After(time.Now().Add(constant.TaskMonitorTimeout)) {
+		t.Errorf("expected the task to time out in %s, got %s", constant.TaskMonitorTimeout, deadline.Sub(started))
+	}
+
+	positions <- model.RobotState{X: 0, Y: 1}
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		if monitors = monitor.Monitors(); monitors[0].Events == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the update to be recorded, got %+v", monitors)
+		}
+	}
+	if monitors[0].LastEventAt.Before(started) {
+		t.Errorf("expected the time of the update, got %s", monitors[0].LastEventAt)
+	}
+
+	// cancelling through the same monitor stops watching the task
+	if err := monitor.CancelTask("task", "cancelled by user"); err != nil {
+		t.Fatal(err)
+	}
+	if monitors := monitor.Monitors(); len(monitors) != 0 {
+		t.Errorf("expected no monitors after cancelling, got %+v", monitors)
+	}
+}
+
+func TestTaskMonitor_CancelTaskRecordsStopPosition(t *testing.T) {
+	repo := dao.NewInMemoryTaskRepository()
+	_ = repo.Create(&model.Task{TaskID: "task", RobotID: "robot", Status: model.TaskStatusPending})
+	monitor := NewTaskMonitor(repo, dao.NewInMemoryCrateRepository(), nil, nil)
+	defer monitor.Shutdown(context.Background())
+
+	// the SDK stopped the robot after one step and reported it, all before
+	// the monitor got to read anything
+	positions := make(chan model.RobotState, 10)
+	errs := make(chan error, 1)
+	positions <- model.RobotState{X: 0, Y: 0}
+	positions <- model.RobotState{X: 0, Y: 1}
+	errs <- model.ErrSDKTaskCancelled
+	close(positions)
+	close(errs)
+	monitor.StartMonitoring("task", positions, errs)
+
+	if err := monitor.CancelTask("task", "cancelled by user"); err != nil {
+		t.Fatal(err)
+	}
+	task, _ := repo.GetById("task")
+	if task.Status != model.TaskStatusCancelled || task.Error != "cancelled by user" {
+		t.Errorf("expected the task cancelled by user, got %s %q", task.Status, task.Error)
+	}
+	if task.CurrentPosition == nil || task.CurrentPosition.Y != 1 {
+		t.Errorf("expected the stop position (0,1) recorded, got %+v", task.CurrentPosition)
+	}
+}
+
+func TestTaskMonitor_StoppedMonitorKeepsNewReservation(t *testing.T) {
+	repo := dao.NewInMemoryTaskRepository()
+	_ = repo.Create(&model.Task{TaskID: "task", RobotID: "robot", Status: model.TaskStatusPending})
+	reservations := NewReservationTable(constant.RobotStepDuration)
+	monitor := NewTaskMonitor(repo, dao.NewInMemoryCrateRepository(), reservations, nil)
+	defer monitor.Shutdown(context.Background())
+
+	positions := make(chan model.RobotState)
+	errs := make(chan error, 1)
+	reservations.Reserve("robot", "task", []model.Cell{{X: 0, Y: 0}, {X: 0, Y: 1}})
+	monitor.StartMonitoring("task", positions, errs)
+	monitor.mu.Lock()
+	old := monitor.monitors["task"]
+	monitor.mu.Unlock()
+
+	// the task is re-enqueued under the same ID, as the queue revalidation does,
+	// and only then does the SDK report the old queued task cancelled
+	monitor.StopMonitoring("task")
+	reservations.Release("task", nil)
+	reservations.Reserve("robot", "task", []model.Cell{{X: 0, Y: 0}, {X: 1, Y: 0}})
+	monitor.StartMonitoring("task", make(chan model.RobotState), make(chan error))
+	errs <- model.ErrSDKTaskCancelled
+	close(positions)
+	close(errs)
+	<-old.done
+
+	plans := reservations.Reservations()
+	if len(plans) != 1 || plans[0].TaskID != "task" || plans[0].Cells[1] != (model.Cell{X: 1, Y: 0}) {
+		t.Errorf("expected the re-enqueued task's path still reserved, got %+v", plans)
+	}
+}
@@ -0,0 +1,232 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/model"
+)
+
+// Headers sent with every webhook delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookAttemptHeader   = "X-Webhook-Attempt"
+)
+
+// WebhookDispatcher notifies the registered webhooks when a task ends. It
+// subscribes to the task outcomes on the event bus, posts a signed JSON
+// payload to every webhook that subscribed to the event, and retries failed
+// deliveries with exponential backoff. Every attempt is recorded in the
+// webhook repository.
+type WebhookDispatcher struct {
+	webhooks       dao.IWebhookRepository
+	tasks          dao.ITaskRepository
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+
+	subscription *EventSubscription
+	deliveries   atomic.Int64
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a dispatcher that makes up to maxAttempts
+// attempts per payload, each limited by timeout, waiting initialBackoff after
+// the first failure and twice as long after each further one.
+func NewWebhookDispatcher(
+	webhooks dao.IWebhookRepository,
+	tasks dao.ITaskRepository,
+	maxAttempts int,
+	initialBackoff time.Duration,
+	timeout time.Duration,
+) *WebhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookDispatcher{
+		webhooks:       webhooks,
+		tasks:          tasks,
+		client:         &http.Client{Timeout: timeout},
+		maxAttempts:    maxAttempts,
+		initialBackoff: initialBackoff,
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+}
+
+// Start subscribes to the task outcomes on the bus until Shutdown.
+func (d *WebhookDispatcher) Start(bus *EventBus) {
+	d.subscription = bus.Subscribe("webhooks", d.Dispatch, SubscribeOptions{
+		Events:   []string{model.EventTaskCompleted, model.EventTaskFailed, model.EventTaskCancelled},
+		Overflow: BlockWhenFull,
+	})
+}
+
+// Dispatch starts delivering the outcome of a task to every webhook that
+// wants it. Events other than TaskCompleted, TaskFailed and TaskCancelled are
+// ignored.
+func (d *WebhookDispatcher) Dispatch(event model.DomainEvent) {
+	payload := model.WebhookPayload{TaskID: event.StreamID(), OccurredAt: event.OccurredAt()}
+	switch e := event.(type) {
+	case model.TaskCompleted:
+		payload.RobotID, payload.Status = e.RobotID, model.TaskStatusCompleted
+	case model.TaskFailed:
+		payload.RobotID, payload.Status, payload.Error = e.RobotID, model.TaskStatusFailed, e.Reason
+	case model.TaskCancelled:
+		payload.RobotID, payload.Status, payload.Error = e.RobotID, model.TaskStatusCancelled, e.Reason
+	default:
+		return
+	}
+	name := model.WebhookEventFor(payload.Status)
+	payload.Event = name
+
+	webhooks, err := d.webhooks.List()
+	if err != nil {
+		log.Printf("webhooks: list: %v", err)
+		return
+	}
+
+	if task, err := d.tasks.GetById(payload.TaskID); err == nil {
+		payload.RobotID = task.RobotID
+		payload.Commands = task.Commands
+		payload.FinalPosition = task.CurrentPosition
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Wants(name) {
+			continue
+		}
+		delivery := payload
+		delivery.DeliveryID = fmt.Sprintf("delivery_%d", d.deliveries.Add(1))
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.deliver(webhook, delivery)
+		}()
+	}
+}
+
+// deliver posts the payload until the webhook accepts it, refuses it for good,
+// the attempts run out or the dispatcher shuts down.
+func (d *WebhookDispatcher) deliver(webhook *model.Webhook, payload model.WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("webhooks: encode %s: %v", payload.DeliveryID, err)
+		return
+	}
+	signature := Sign(webhook.Secret, body)
+
+	backoff := d.initialBackoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		record := d.post(webhook, payload, body, signature, attempt)
+		if err := d.webhooks.RecordAttempt(record); err != nil {
+			log.Printf("webhooks: record attempt %d of %s: %v", attempt, payload.DeliveryID, err)
+		}
+		if record.Succeeded || !retryable(record.StatusCode) {
+			return
+		}
+		if attempt == d.maxAttempts {
+			log.Printf("webhooks: giving up on %s to %s after %d attempts", payload.DeliveryID, webhook.URL, attempt)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-d.ctx.Done():
+			return
+		}
+	}
+}
+
+// post makes one delivery attempt.
+func (d *WebhookDispatcher) post(webhook *model.Webhook, payload model.WebhookPayload,
+	body []byte, signature string, attempt int) *model.WebhookAttempt {
+	record := &model.WebhookAttempt{
+		DeliveryID: payload.DeliveryID,
+		WebhookID:  webhook.ID,
+		TaskID:     payload.TaskID,
+		Event:      payload.Event,
+		Attempt:    attempt,
+		At:         time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, signature)
+	req.Header.Set(WebhookEventHeader, payload.Event)
+	req.Header.Set(WebhookDeliveryHeader, payload.DeliveryID)
+	req.Header.Set(WebhookAttemptHeader, strconv.Itoa(attempt))
+
+	resp, err := d.client.Do(req)
+	record.Duration = time.Since(record.At)
+	if err != nil {
+		record.Error = err.Error()
+		return record
+	}
+	resp.Body.Close()
+
+	record.StatusCode = resp.StatusCode
+	record.Succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !record.Succeeded {
+		record.Error = resp.Status
+	}
+	return record
+}
+
+// retryable reports whether an attempt that ended with the status code may
+// succeed later: no response at all, a timeout, rate limiting or a server error.
+func retryable(statusCode int) bool {
+	return statusCode == 0 ||
+		statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= 500
+}
+
+// Sign returns the signature header value for a payload: the hex encoded
+// HMAC-SHA256 of the body under the webhook secret, prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Shutdown stops following task events, abandons deliveries in flight and
+// pending retries, and waits for them to wind down.
+func (d *WebhookDispatcher) Shutdown(ctx context.Context) error {
+	if d.subscription != nil {
+		d.subscription.Close()
+	}
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
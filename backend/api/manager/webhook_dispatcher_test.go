@@ -0,0 +1,79 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestWebhookDispatcher_Deliver(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []int
+		attempts  int
+		delivered bool
+	}{
+		{"accepted", []int{http.StatusNoContent}, 1, true},
+		{"retried_after_server_error", []int{http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK}, 3, true},
+		{"refused_for_good", []int{http.StatusGone}, 1, false},
+		{"attempts_run_out", []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if got := r.Header.Get(WebhookSignatureHeader); got != Sign("s3cret", body) {
+					t.Errorf("expected a valid signature, got %q", got)
+				}
+				var payload model.WebhookPayload
+				if err := json.Unmarshal(body, &payload); err != nil || payload.Event != model.WebhookEventTaskCompleted {
+					t.Errorf("expected a task.completed payload, got %s (%v)", body, err)
+				}
+				w.WriteHeader(tt.responses[calls.Add(1)-1])
+			}))
+			defer receiver.Close()
+
+			webhooks := dao.NewInMemoryWebhookRepository()
+			_ = webhooks.Create(&model.Webhook{ID: "hook", URL: receiver.URL, Secret: "s3cret"})
+			dispatcher := NewWebhookDispatcher(webhooks, dao.NewInMemoryTaskRepository(), 3, time.Millisecond, time.Second)
+
+			dispatcher.Dispatch(model.TaskCompleted{TaskID: "task"})
+			dispatcher.wg.Wait()
+
+			attempts, _ := webhooks.ListAttempts("hook")
+			if len(attempts) != tt.attempts {
+				t.Fatalf("expected %d attempts, got %d", tt.attempts, len(attempts))
+			}
+			last := attempts[len(attempts)-1]
+			if last.Succeeded != tt.delivered || last.Attempt != tt.attempts {
+				t.Errorf("expected attempt %d to end with delivered=%t, got %+v", tt.attempts, tt.delivered, last)
+			}
+			if attempts[0].DeliveryID != last.DeliveryID {
+				t.Errorf("expected retries to share the delivery ID, got %s and %s", attempts[0].DeliveryID, last.DeliveryID)
+			}
+		})
+	}
+}
+
+func TestWebhookDispatcher_EventFilter(t *testing.T) {
+	webhooks := dao.NewInMemoryWebhookRepository()
+	_ = webhooks.Create(&model.Webhook{ID: "failures", URL: "http://127.0.0.1:0", Events: []string{model.WebhookEventTaskFailed}})
+	dispatcher := NewWebhookDispatcher(webhooks, dao.NewInMemoryTaskRepository(), 1, time.Millisecond, time.Second)
+	defer dispatcher.Shutdown(context.Background())
+
+	dispatcher.Dispatch(model.TaskCompleted{TaskID: "task"})
+	dispatcher.wg.Wait()
+
+	if attempts, _ := webhooks.ListAttempts("failures"); len(attempts) != 0 {
+		t.Errorf("expected no delivery of task.completed to a task.failed webhook, got %+v", attempts)
+	}
+}
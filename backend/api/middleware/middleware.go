@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"bufio"
 	"log"
+	"net"
 	"net/http"
 	"time"
 
@@ -75,3 +77,15 @@ func (lrw *loggingResponseWriter) WriteHeader(code int) {
 	lrw.statusCode = code
 	lrw.ResponseWriter.WriteHeader(code)
 }
+
+// Unwrap exposes the underlying writer, so http.NewResponseController can
+// flush streamed responses through the wrapper.
+func (lrw *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return lrw.ResponseWriter
+}
+
+// Hijack hands the connection over to the handler, e.g. for WebSocket upgrades.
+func (lrw *loggingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	lrw.statusCode = http.StatusSwitchingProtocols
+	return http.NewResponseController(lrw.ResponseWriter).Hijack()
+}
@@ -0,0 +1,10 @@
+package model
+
+// Crate is a single crate on the warehouse floor.
+// ID and SKU are optional; anonymous crates are identified by their cell only.
+type Crate struct {
+	ID  string `json:"id,omitempty"`
+	SKU string `json:"sku,omitempty"`
+	X   uint   `json:"x"`
+	Y   uint   `json:"y"`
+}
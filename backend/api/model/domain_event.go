@@ -0,0 +1,150 @@
+package model
+
+import "time"
+
+// Domain event names, as returned by DomainEvent.EventName.
+const (
+	EventTaskCreated         = "TaskCreated"
+	EventTaskPositionUpdated = "TaskPositionUpdated"
+	EventTaskCompleted       = "TaskCompleted"
+	EventTaskFailed          = "TaskFailed"
+	EventTaskCancelled       = "TaskCancelled"
+	EventRobotStateChanged   = "RobotStateChanged"
+)
+
+// DomainEvent is something that happened to a task or robot, published on the
+// event bus after it has been persisted.
+type DomainEvent interface {
+	// EventName identifies the event type, e.g. EventTaskCreated.
+	EventName() string
+	// StreamID is the task or robot the event belongs to; events with the same
+	// stream ID are delivered in the order they were published.
+	StreamID() string
+	// OccurredAt is when the event happened.
+	OccurredAt() time.Time
+}
+
+// TaskCreated is published once a task has been enqueued and persisted.
+type TaskCreated struct {
+	TaskID   string
+	RobotID  string
+	Commands string
+	Target   *Cell
+	At       time.Time
+}
+
+// TaskPositionUpdated is published for every position the robot reports while
+// working on a task, starting with the cell the task started from.
+type TaskPositionUpdated struct {
+	TaskID   string
+	RobotID  string
+	Position Position
+	At       time.Time
+}
+
+// TaskCompleted is published when the robot finished all commands of a task.
+type TaskCompleted struct {
+	TaskID   string
+	RobotID  string
+	Position *Position // last position seen, nil if none was reported
+	At       time.Time
+}
+
+// TaskFailed is published when the SDK reported an error or the task timed out.
+type TaskFailed struct {
+	TaskID   string
+	RobotID  string
+	Reason   string
+	Position *Position // last position seen, nil if none was reported
+	At       time.Time
+}
+
+// TaskCancelled is published when a task was cancelled, by a user or because
+// it could no longer run.
+type TaskCancelled struct {
+	TaskID  string
+	RobotID string
+	Reason  string
+	At      time.Time
+}
+
+// RobotStateChanged is published when a robot is seen on another cell or
+// picks up or puts down a crate.
+type RobotStateChanged struct {
+	RobotID string
+	TaskID  string // task the robot was working on
+	State   RobotState
+	At      time.Time
+}
+
+func (e TaskCreated) EventName() string {
+	return EventTaskCreated
+}
+
+func (e TaskCreated) StreamID() string {
+	return e.TaskID
+}
+
+func (e TaskCreated) OccurredAt() time.Time {
+	return e.At
+}
+
+func (e TaskPositionUpdated) EventName() string {
+	return EventTaskPositionUpdated
+}
+
+func (e TaskPositionUpdated) StreamID() string {
+	return e.TaskID
+}
+
+func (e TaskPositionUpdated) OccurredAt() time.Time {
+	return e.At
+}
+
+func (e TaskCompleted) EventName() string {
+	return EventTaskCompleted
+}
+
+func (e TaskCompleted) StreamID() string {
+	return e.TaskID
+}
+
+func (e TaskCompleted) OccurredAt() time.Time {
+	return e.At
+}
+
+func (e TaskFailed) EventName() string {
+	return EventTaskFailed
+}
+
+func (e TaskFailed) StreamID() string {
+	return e.TaskID
+}
+
+func (e TaskFailed) OccurredAt() time.Time {
+	return e.At
+}
+
+func (e TaskCancelled) EventName() string {
+	return EventTaskCancelled
+}
+
+func (e TaskCancelled) StreamID() string {
+	return e.TaskID
+}
+
+func (e TaskCancelled) OccurredAt() time.Time {
+	return e.At
+}
+
+func (e RobotStateChanged) EventName() string {
+	return EventRobotStateChanged
+}
+
+func (e RobotStateChanged) StreamID() string {
+	return e.RobotID
+}
+
+func (e RobotStateChanged) OccurredAt() time.Time {
+	return e.At
+}
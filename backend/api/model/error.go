@@ -6,14 +6,21 @@ import (
 )
 
 var (
-	ErrValidation        = errors.New(constant.ErrorCodeValidation)
-	ErrBoundary          = errors.New(constant.ErrorCodeBoundary)
-	ErrRobotIDInvalid    = errors.New(constant.ErrorCodeRobotIdInvalid)
-	ErrRobotNotFound     = errors.New(constant.ErrorCodeRobotNotFound)
-	ErrTaskNotFound      = errors.New(constant.ErrorCodeTaskNotFound)
-	ErrRobotBusy         = errors.New(constant.ErrorCodeRobotBusy)
-	ErrTaskQueueFull     = errors.New(constant.ErrorCodeTaskQueueFull)
-	ErrInternal          = errors.New(constant.ErrorCodeInternal)
-	ErrTaskProcessed     = errors.New(constant.ErrorCodeTaskAlreadyDone)
-	ErrSDKFailedToCancel = errors.New(constant.ErrorSDKFailedToCancel)
+	ErrValidation             = errors.New(constant.ErrorCodeValidation)
+	ErrBoundary               = errors.New(constant.ErrorCodeBoundary)
+	ErrCrate                  = errors.New(constant.ErrorCodeCrate)
+	ErrObstacle               = errors.New(constant.ErrorCodeObstacle)
+	ErrNoPath                 = errors.New(constant.ErrorCodeNoPath)
+	ErrPathConflict           = errors.New(constant.ErrorCodePathConflict)
+	ErrRobotIDInvalid         = errors.New(constant.ErrorCodeRobotIdInvalid)
+	ErrRobotNotFound          = errors.New(constant.ErrorCodeRobotNotFound)
+	ErrRobotAlreadyRegistered = errors.New(constant.ErrorCodeRobotAlreadyRegistered)
+	ErrTaskNotFound           = errors.New(constant.ErrorCodeTaskNotFound)
+	ErrWebhookNotFound        = errors.New(constant.ErrorCodeWebhookNotFound)
+	ErrRobotBusy              = errors.New(constant.ErrorCodeRobotBusy)
+	ErrTaskQueueFull          = errors.New(constant.ErrorCodeTaskQueueFull)
+	ErrInternal               = errors.New(constant.ErrorCodeInternal)
+	ErrTaskProcessed          = errors.New(constant.ErrorCodeTaskAlreadyDone)
+	ErrSDKFailedToCancel      = errors.New(constant.ErrorSDKFailedToCancel)
+	ErrShuttingDown           = errors.New(constant.ErrorCodeShuttingDown)
 )
@@ -0,0 +1,39 @@
+package model
+
+import "warehouse-robots/backend/api/constant"
+
+// Grid is the rectangular warehouse floor robots may move on.
+// Cells run from (OriginX, OriginY) to (OriginX+Width-1, OriginY+Height-1) inclusive.
+type Grid struct {
+	OriginX uint
+	OriginY uint
+	Width   uint
+	Height  uint
+}
+
+// DefaultGrid returns the default 10x10 warehouse floor.
+func DefaultGrid() Grid {
+	return Grid{
+		OriginX: constant.MinCoordinateX,
+		OriginY: constant.MinCoordinateY,
+		Width:   constant.WarehouseSizeX,
+		Height:  constant.WarehouseSizeY,
+	}
+}
+
+// MaxX returns the largest valid x coordinate.
+func (g Grid) MaxX() uint {
+	return g.OriginX + g.Width - 1
+}
+
+// MaxY returns the largest valid y coordinate.
+func (g Grid) MaxY() uint {
+	return g.OriginY + g.Height - 1
+}
+
+// Contains reports whether (x, y) lies on the floor. Coordinates are signed so
+// callers can test a move before applying it to an unsigned position.
+func (g Grid) Contains(x, y int) bool {
+	return x >= int(g.OriginX) && x <= int(g.MaxX()) &&
+		y >= int(g.OriginY) && y <= int(g.MaxY())
+}
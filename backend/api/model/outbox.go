@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// OutboxEntry is a task lifecycle event recorded by the task repository in the
+// same operation as the change it describes, so the event survives a crash
+// between persisting the change and publishing it. The outbox relay publishes
+// pending entries and then marks them delivered, which removes them.
+type OutboxEntry struct {
+	ID        int64  // increasing in the order the entries were written
+	Event     string // domain event name, e.g. EventTaskCompleted
+	TaskID    string
+	RobotID   string
+	Status    TaskStatus
+	Error     string
+	Position  *Position // task position after the change, nil if none reported yet
+	CreatedAt time.Time
+}
+
+// OutboxEventFor returns the domain event recorded when a task changes to the
+// status, or "" if the change is not a lifecycle event.
+func OutboxEventFor(status TaskStatus) string {
+	switch status {
+	case TaskStatusCompleted:
+		return EventTaskCompleted
+	case TaskStatusFailed:
+		return EventTaskFailed
+	case TaskStatusCancelled:
+		return EventTaskCancelled
+	default:
+		return ""
+	}
+}
+
+// DomainEvent rebuilds the domain event the entry was recorded for, or nil if
+// the event name is unknown.
+func (e *OutboxEntry) DomainEvent() DomainEvent {
+	switch e.Event {
+	case EventTaskPositionUpdated:
+		if e.Position == nil {
+			return nil
+		}
+		return TaskPositionUpdated{TaskID: e.TaskID, RobotID: e.RobotID, Position: *e.Position, At: e.CreatedAt}
+	case EventTaskCompleted:
+		return TaskCompleted{TaskID: e.TaskID, RobotID: e.RobotID, Position: e.Position, At: e.CreatedAt}
+	case EventTaskFailed:
+		return TaskFailed{TaskID: e.TaskID, RobotID: e.RobotID, Reason: e.Error, Position: e.Position, At: e.CreatedAt}
+	case EventTaskCancelled:
+		return TaskCancelled{TaskID: e.TaskID, RobotID: e.RobotID, Reason: e.Error, At: e.CreatedAt}
+	default:
+		return nil
+	}
+}
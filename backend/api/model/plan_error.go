@@ -0,0 +1,27 @@
+package model
+
+import "fmt"
+
+// PlanError describes where a command plan fails validation.
+// It wraps one of the sentinel errors so errors.Is keeps working, while
+// carrying the offending step for the API error details.
+type PlanError struct {
+	Err    error
+	Step   int // zero-based index into the normalized command string
+	X      int
+	Y      int
+	Reason string
+}
+
+func (e *PlanError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PlanError) Unwrap() error {
+	return e.Err
+}
+
+// Details renders the failing step for ErrorResponse.details.
+func (e *PlanError) Details() string {
+	return fmt.Sprintf("step %d enters %s at (%d,%d)", e.Step, e.Reason, e.X, e.Y)
+}
@@ -0,0 +1,24 @@
+package model
+
+import "fmt"
+
+// SyntaxError describes where a command string fails to parse.
+// It wraps ErrValidation so errors.Is keeps working, while carrying the
+// offending position for the API error details.
+type SyntaxError struct {
+	Position int // one-based character position in the raw command string
+	Reason   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid commands at position %d: %s", e.Position, e.Reason)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return ErrValidation
+}
+
+// Details renders the failing position for ErrorResponse.details.
+func (e *SyntaxError) Details() string {
+	return fmt.Sprintf("position %d: %s", e.Position, e.Reason)
+}
@@ -14,9 +14,16 @@ const (
 )
 
 type Task struct {
-	TaskID          string     `json:"task_id"`
-	RobotID         string     `json:"robot_id"`
-	Commands        string     `json:"commands"`
+	TaskID   string `json:"task_id"`
+	RobotID  string `json:"robot_id"`
+	Commands string `json:"commands"`
+	Target   *Cell  `json:"target,omitempty"`
+	// SDKTaskID is the SDK handle for the task. It is empty until a queued task
+	// is re-planned and enqueued again, after which it differs from TaskID.
+	SDKTaskID string `json:"sdk_task_id,omitempty"`
+	// PlannedStart is the position the commands were validated from, i.e. the
+	// projected end of the task queued ahead of this one.
+	PlannedStart    *Position  `json:"planned_start,omitempty"`
 	Status          TaskStatus `json:"status"`
 	CurrentPosition *Position  `json:"current_position,omitempty"`
 	Error           string     `json:"error,omitempty"`
@@ -24,6 +31,32 @@ type Task struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
+// SDKID returns the ID the SDK knows this task by.
+func (t *Task) SDKID() string {
+	if t.SDKTaskID != "" {
+		return t.SDKTaskID
+	}
+	return t.TaskID
+}
+
+// Clone returns a deep copy of the task.
+func (t *Task) Clone() *Task {
+	clone := *t
+	if t.Target != nil {
+		target := *t.Target
+		clone.Target = &target
+	}
+	if t.PlannedStart != nil {
+		start := *t.PlannedStart
+		clone.PlannedStart = &start
+	}
+	if t.CurrentPosition != nil {
+		position := *t.CurrentPosition
+		clone.CurrentPosition = &position
+	}
+	return &clone
+}
+
 type Position struct {
 	X        uint `json:"x"`
 	Y        uint `json:"y"`
@@ -0,0 +1,12 @@
+package model
+
+// TaskDrainReport is what became of the running tasks when the service shut down.
+type TaskDrainReport struct {
+	// Finished counts the tasks that ended while the service waited for them
+	Finished int
+	// Cancelled lists the tasks cancelled through the SDK
+	Cancelled []string
+	// Running lists the tasks still PENDING when the service stopped watching;
+	// a persistent store recovers them on the next start
+	Running []*Task
+}
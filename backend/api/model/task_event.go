@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type TaskEventType string
+
+const (
+	// TaskEventStatus reports a status change that does not end the task, e.g.
+	// the task being (re-)enqueued.
+	TaskEventStatus TaskEventType = "status"
+	// TaskEventPosition reports the robot's position while it works on the task.
+	TaskEventPosition TaskEventType = "position"
+	// TaskEventError reports an error from the SDK; the task ends right after.
+	TaskEventError TaskEventType = "error"
+	// TaskEventEnd is the last event of a task, carrying its terminal status.
+	TaskEventEnd TaskEventType = "end"
+)
+
+// TaskEvent is one step in the life of a task as observed by the task monitor.
+// IDs count up from 1 per task, so a client can resume after the last one it saw.
+type TaskEvent struct {
+	ID       int64
+	TaskID   string
+	RobotID  string
+	Type     TaskEventType
+	Status   TaskStatus
+	Position *Position
+	Error    string
+	At       time.Time
+}
@@ -0,0 +1,91 @@
+package model
+
+import "time"
+
+// TaskHistoryType is the kind of change a task history event records.
+type TaskHistoryType string
+
+const (
+	// TaskHistoryCreated records a new task; Snapshot holds the whole task.
+	TaskHistoryCreated TaskHistoryType = "created"
+	// TaskHistoryReplaced records a task rewritten as a whole, e.g. when a
+	// queued task is re-planned; Snapshot holds the whole task.
+	TaskHistoryReplaced TaskHistoryType = "replaced"
+	// TaskHistoryPosition records a position sample with its step index.
+	TaskHistoryPosition TaskHistoryType = "position"
+	// TaskHistoryStatus records a status transition and its error, if any.
+	TaskHistoryStatus TaskHistoryType = "status"
+)
+
+// TaskHistoryEvent is one entry of a task's append-only history. The task as
+// the repository returns it is the projection of its events, see ReplayTask.
+type TaskHistoryEvent struct {
+	TaskID string
+	Seq    int64 // 1 for the first event of the task, increasing by one
+	Type   TaskHistoryType
+	At     time.Time
+
+	// Snapshot is the whole task, for created and replaced events
+	Snapshot *Task
+
+	// Step is the index of a position sample; 0 is the cell the task started from
+	Step     int
+	Position *Position
+
+	// Status is the status after a position or status event
+	Status TaskStatus
+	// Error is the error set by a status event; empty keeps the previous one
+	Error string
+}
+
+// Apply folds the event into the task and returns the result. A created event
+// starts the projection, so task may be nil for it.
+func (e *TaskHistoryEvent) Apply(task *Task) *Task {
+	switch e.Type {
+	case TaskHistoryCreated, TaskHistoryReplaced:
+		if e.Snapshot == nil {
+			return task
+		}
+		projected := e.Snapshot.Clone()
+		if e.Type == TaskHistoryReplaced {
+			projected.UpdatedAt = e.At
+		}
+		return projected
+
+	case TaskHistoryPosition:
+		if task == nil {
+			return nil
+		}
+		if e.Position != nil {
+			position := *e.Position
+			task.CurrentPosition = &position
+		}
+		task.Status = e.Status
+		task.UpdatedAt = e.At
+		return task
+
+	case TaskHistoryStatus:
+		if task == nil {
+			return nil
+		}
+		task.Status = e.Status
+		if e.Error != "" {
+			task.Error = e.Error
+		}
+		task.UpdatedAt = e.At
+		return task
+
+	default:
+		return task
+	}
+}
+
+// ReplayTask rebuilds a task from its history, oldest event first. It returns
+// nil if the history does not start with the task's creation.
+func ReplayTask(events []*TaskHistoryEvent) *Task {
+	var task *Task
+	for _, event := range events {
+		task = event.Apply(task)
+	}
+	return task
+}
@@ -0,0 +1,88 @@
+package model
+
+import "fmt"
+
+// Cell is a single coordinate on the warehouse grid.
+type Cell struct {
+	X uint `json:"x"`
+	Y uint `json:"y"`
+}
+
+// NoGoZone is a named rectangle robots must never enter.
+// Min and Max are inclusive corners.
+type NoGoZone struct {
+	Name string `json:"name"`
+	Min  Cell   `json:"min"`
+	Max  Cell   `json:"max"`
+}
+
+// Contains reports whether (x, y) lies inside the zone.
+func (z NoGoZone) Contains(x, y int) bool {
+	return x >= int(z.Min.X) && x <= int(z.Max.X) &&
+		y >= int(z.Min.Y) && y <= int(z.Max.Y)
+}
+
+// RobotHome is the cell a robot parks on when the warehouse starts.
+type RobotHome struct {
+	RobotID string `json:"id"`
+	Cell
+}
+
+// WarehouseMap is the static layout of the warehouse: the grid, the cells
+// robots cannot drive through (walls, shelves, other racking and pillars),
+// named no-go zones, and the special cells robots and crates start on.
+type WarehouseMap struct {
+	Grid          Grid
+	Walls         map[Cell]bool
+	Shelves       map[Cell]bool
+	Blocked       map[Cell]bool
+	Zones         []NoGoZone
+	CrateSlots    []Cell
+	ChargingDocks []Cell
+	RobotHomes    []RobotHome
+}
+
+// NewWarehouseMap returns an empty map covering the whole grid.
+func NewWarehouseMap(grid Grid) *WarehouseMap {
+	return &WarehouseMap{
+		Grid:    grid,
+		Walls:   make(map[Cell]bool),
+		Shelves: make(map[Cell]bool),
+		Blocked: make(map[Cell]bool),
+	}
+}
+
+// RobotHome returns the home cell of the given robot, if the map defines one.
+func (m *WarehouseMap) RobotHome(robotID string) (Cell, bool) {
+	for _, home := range m.RobotHomes {
+		if home.RobotID == robotID {
+			return home.Cell, true
+		}
+	}
+	return Cell{}, false
+}
+
+// Obstruction returns a description of what stops a robot from entering (x, y),
+// or an empty string if the cell is free. Cells outside the grid are not
+// obstructions; boundary checks are done separately against Grid.
+func (m *WarehouseMap) Obstruction(x, y int) string {
+	if !m.Grid.Contains(x, y) {
+		return ""
+	}
+	cell := Cell{X: uint(x), Y: uint(y)}
+	if m.Walls[cell] {
+		return "wall"
+	}
+	if m.Shelves[cell] {
+		return "shelf"
+	}
+	if m.Blocked[cell] {
+		return "blocked cell"
+	}
+	for _, zone := range m.Zones {
+		if zone.Contains(x, y) {
+			return fmt.Sprintf("no-go zone %q", zone.Name)
+		}
+	}
+	return ""
+}
@@ -1,5 +1,18 @@
 package model
 
+import "errors"
+
+// Errors of the SDK task contract. CancelTask returns ErrSDKTaskUnknown for a
+// task the robot never had and ErrSDKTaskFinished for one that already ended.
+// A task cancelled through CancelTask sends ErrSDKTaskCancelled on its error
+// channel once the robot has stopped, after the positions it reached; both
+// channels are closed after it.
+var (
+	ErrSDKTaskUnknown   = errors.New("sdk: unknown task")
+	ErrSDKTaskFinished  = errors.New("sdk: task already finished")
+	ErrSDKTaskCancelled = errors.New("sdk: task cancelled")
+)
+
 // Low level SDK functions.
 type Warehouse interface {
 	Robots() []Robot
@@ -11,6 +24,22 @@ type Robot interface {
 	CurrentState() RobotState
 }
 
+// IdentifiedRobot is implemented by SDK robots that report their own serial
+// number, which then becomes their stable ID in the API.
+type IdentifiedRobot interface {
+	Robot
+	GetID() string
+}
+
+// TaskWatcher is implemented by SDK robots that can hand out the updates of a
+// task they accepted earlier, e.g. before the service restarted. WatchTask
+// returns the task's position and error channels, as EnqueueTask did, while the
+// task is queued or running, and ok false once the robot no longer has it.
+type TaskWatcher interface {
+	Robot
+	WatchTask(taskID string) (position chan RobotState, err chan error, ok bool)
+}
+
 type RobotState struct {
 	X        uint
 	Y        uint
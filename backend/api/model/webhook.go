@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// Webhook event names, one per terminal task status.
+const (
+	WebhookEventTaskCompleted = "task.completed"
+	WebhookEventTaskFailed    = "task.failed"
+	WebhookEventTaskCancelled = "task.cancelled"
+)
+
+// WebhookEventFor returns the webhook event name for a terminal task status,
+// or "" for a status that does not end a task.
+func WebhookEventFor(status TaskStatus) string {
+	switch status {
+	case TaskStatusCompleted:
+		return WebhookEventTaskCompleted
+	case TaskStatusFailed:
+		return WebhookEventTaskFailed
+	case TaskStatusCancelled:
+		return WebhookEventTaskCancelled
+	}
+	return ""
+}
+
+// Webhook is a callback URL that is notified when tasks end. Payloads are
+// signed with the secret, so the receiver can tell they came from us.
+type Webhook struct {
+	ID     string
+	URL    string
+	Secret string
+	// Events lists the event names to deliver; empty means all of them.
+	Events    []string
+	CreatedAt time.Time
+}
+
+// Wants reports whether the webhook subscribed to the event.
+func (w *Webhook) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, wanted := range w.Events {
+		if wanted == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body posted to a webhook.
+type WebhookPayload struct {
+	DeliveryID    string     `json:"delivery_id"`
+	Event         string     `json:"event"`
+	TaskID        string     `json:"task_id"`
+	RobotID       string     `json:"robot_id"`
+	Status        TaskStatus `json:"status"`
+	Commands      string     `json:"commands,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	FinalPosition *Position  `json:"final_position,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+}
+
+// WebhookAttempt records one attempt to deliver a payload to a webhook.
+// Retries of a payload share its delivery ID.
+type WebhookAttempt struct {
+	DeliveryID string
+	WebhookID  string
+	TaskID     string
+	Event      string
+	Attempt    int
+	// StatusCode is the receiver's response status, 0 if there was no response.
+	StatusCode int
+	Error      string
+	Succeeded  bool
+	Duration   time.Duration
+	At         time.Time
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// Expand parses a compact command string into the canonical sequence of
+// single-letter commands the robot SDK understands.
+//
+// Grammar (case-insensitive):
+//
+//	sequence := { item | separator }
+//	item     := ( command | "(" sequence ")" ) [ count ]
+//	command  := "N" | "S" | "E" | "W" | "G" | "D"
+//	count    := digit { digit }
+//
+// Whitespace and commas separate items and are otherwise ignored. A count
+// must directly follow its command or group, so "N9" and "(NE)3" expand to
+// nine norths and NENENE, while "N 9" is rejected.
+//
+// Returns a *model.SyntaxError (wrapping ErrValidation) pointing at the first
+// offending character.
+func Expand(commands string) (string, error) {
+	p := &commandParser{input: []rune(commands)}
+
+	expanded, err := p.sequence(0)
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.input) {
+		// sequence only stops early on a closing parenthesis
+		return "", p.errorf(p.pos, "unmatched ')'")
+	}
+	if expanded == "" {
+		return "", p.errorf(len(p.input), "commands cannot be empty")
+	}
+	return expanded, nil
+}
+
+// commandParser is a recursive descent parser over the raw command string.
+type commandParser struct {
+	input []rune
+	pos   int
+}
+
+// sequence parses items until the end of input or a closing parenthesis.
+// depth is the number of open groups, used to reject stray ')' at the top level.
+func (p *commandParser) sequence(depth int) (string, error) {
+	var out strings.Builder
+
+	for p.pos < len(p.input) {
+		start := p.pos
+		r := p.input[p.pos]
+
+		var item string
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == ',':
+			p.pos++
+			continue
+		case r == ')':
+			if depth == 0 {
+				return "", p.errorf(p.pos, "unmatched ')'")
+			}
+			return out.String(), nil
+		case r == '(':
+			p.pos++
+			group, err := p.sequence(depth + 1)
+			if err != nil {
+				return "", err
+			}
+			if p.pos >= len(p.input) {
+				return "", p.errorf(start, "unclosed '('")
+			}
+			if group == "" {
+				return "", p.errorf(start, "empty group")
+			}
+			p.pos++ // consume ')'
+			item = group
+		case isCommand(r):
+			p.pos++
+			item = strings.ToUpper(string(r))
+		case r >= '0' && r <= '9':
+			return "", p.errorf(p.pos, "repeat count must follow a command or group")
+		default:
+			return "", p.errorf(p.pos, fmt.Sprintf("invalid command character '%c'. Only N, S, E, W, G, D are allowed", r))
+		}
+
+		count, err := p.count()
+		if err != nil {
+			return "", err
+		}
+		if out.Len()+len(item)*count > constant.MaxExpandedCommands {
+			return "", p.errorf(start, fmt.Sprintf("commands expand to more than %d steps", constant.MaxExpandedCommands))
+		}
+		out.WriteString(strings.Repeat(item, count))
+	}
+
+	return out.String(), nil
+}
+
+// count parses an optional repeat count, defaulting to 1.
+func (p *commandParser) count() (int, error) {
+	start := p.pos
+	count := 0
+	for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+		count = count*10 + int(p.input[p.pos]-'0')
+		if count > constant.MaxExpandedCommands {
+			return 0, p.errorf(start, fmt.Sprintf("repeat count exceeds %d", constant.MaxExpandedCommands))
+		}
+		p.pos++
+	}
+
+	if p.pos == start {
+		return 1, nil
+	}
+	if count == 0 {
+		return 0, p.errorf(start, "repeat count must be at least 1")
+	}
+	return count, nil
+}
+
+// errorf builds a SyntaxError for the zero-based rune index.
+func (p *commandParser) errorf(index int, reason string) error {
+	return &model.SyntaxError{Position: index + 1, Reason: reason}
+}
+
+func isCommand(r rune) bool {
+	switch r {
+	case 'N', 'S', 'E', 'W', 'G', 'D', 'n', 's', 'e', 'w', 'g', 'd':
+		return true
+	}
+	return false
+}
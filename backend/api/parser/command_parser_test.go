@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"warehouse-robots/backend/api/model"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		expected string
+	}{
+		{"plain", "NESW", "NESW"},
+		{"lowercase_with_spaces", "n e s w", "NESW"},
+		{"run_length", "N9", "NNNNNNNNN"},
+		{"multi_digit_count", "E12", "EEEEEEEEEEEE"},
+		{"group", "(NE)3", "NENENE"},
+		{"nested_group", "((NE)2 S)2", "NENESNENES"},
+		{"commas", "N2,E,G,S2,D", "NNEGSSD"},
+		{"group_without_count", "(NE)", "NE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := Expand(tt.commands)
+			if err != nil {
+				t.Fatalf("Expand(%q) unexpected error = %v", tt.commands, err)
+			}
+			if expanded != tt.expected {
+				t.Errorf("Expand(%q) = %q, expected %q", tt.commands, expanded, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands string
+		position int
+	}{
+		{"empty", "", 1},
+		{"only_separators", " , ", 4},
+		{"invalid_character", "NNX", 3},
+		{"zero_count", "N0", 2},
+		{"leading_count", "3N", 1},
+		{"count_after_space", "N 3", 3},
+		{"unclosed_group", "N(NE", 2},
+		{"unmatched_close", "NE)", 3},
+		{"empty_group", "N()", 2},
+		{"too_many_steps", "(N100)100", 1},
+		{"huge_count", "N99999999999", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Expand(tt.commands)
+
+			var syntaxErr *model.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expand(%q) expected *model.SyntaxError, got %v", tt.commands, err)
+			}
+			if syntaxErr.Position != tt.position {
+				t.Errorf("Expand(%q) error position = %d, expected %d (%v)", tt.commands, syntaxErr.Position, tt.position, err)
+			}
+			if !errors.Is(err, model.ErrValidation) {
+				t.Errorf("Expand(%q) error should wrap ErrValidation", tt.commands)
+			}
+		})
+	}
+}
+
+func TestExpand_MaxLength(t *testing.T) {
+	expanded, err := Expand("(NS)500")
+	if err != nil {
+		t.Fatalf("Expand() unexpected error = %v", err)
+	}
+	if len(expanded) != 1000 || !strings.HasPrefix(expanded, "NSNS") {
+		t.Errorf("Expand() produced %d commands", len(expanded))
+	}
+}
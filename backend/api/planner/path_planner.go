@@ -0,0 +1,83 @@
+package planner
+
+import (
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// move is a single step the planner can take, in the order neighbours are explored.
+type move struct {
+	command byte
+	dx, dy  int
+}
+
+// moves lists the compass moves in a fixed order so the same request always
+// produces the same path.
+var moves = []move{
+	{'N', 0, constant.RobotMoveUnit},
+	{'E', constant.RobotMoveUnit, 0},
+	{'S', 0, -constant.RobotMoveUnit},
+	{'W', -constant.RobotMoveUnit, 0},
+}
+
+// ShortestPath returns the shortest N/S/E/W command string that drives a robot
+// from one cell to another without leaving the grid or entering a wall, shelf,
+// blocked cell or no-go zone. Every move costs the same, so a breadth-first
+// search is enough to find an optimal path.
+//
+// Error Returns:
+//   - ErrBoundary: the target lies outside the grid.
+//   - ErrObstacle: the target itself cannot be entered.
+//   - ErrNoPath: obstacles wall the target off from the start.
+func ShortestPath(layout *model.WarehouseMap, from, to model.Cell) (string, error) {
+	if !layout.Grid.Contains(int(to.X), int(to.Y)) {
+		return "", model.ErrBoundary
+	}
+	if obstruction := layout.Obstruction(int(to.X), int(to.Y)); obstruction != "" {
+		return "", &model.PlanError{Err: model.ErrObstacle, Step: 0, X: int(to.X), Y: int(to.Y), Reason: obstruction}
+	}
+	if from == to {
+		return "", nil
+	}
+
+	// parent records how each visited cell was reached, for walking the path back
+	type step struct {
+		from    model.Cell
+		command byte
+	}
+	parent := map[model.Cell]step{from: {}}
+	queue := []model.Cell{from}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, m := range moves {
+			x, y := int(current.X)+m.dx, int(current.Y)+m.dy
+			if !layout.Grid.Contains(x, y) || layout.Obstruction(x, y) != "" {
+				continue
+			}
+
+			next := model.Cell{X: uint(x), Y: uint(y)}
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			parent[next] = step{from: current, command: m.command}
+
+			if next == to {
+				var path []byte
+				for cell := to; cell != from; cell = parent[cell].from {
+					path = append(path, parent[cell].command)
+				}
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return string(path), nil
+			}
+
+			queue = append(queue, next)
+		}
+	}
+
+	return "", model.ErrNoPath
+}
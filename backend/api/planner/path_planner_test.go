@@ -0,0 +1,60 @@
+package planner
+
+import (
+	"errors"
+	"testing"
+
+	"warehouse-robots/backend/api/model"
+)
+
+func TestShortestPath(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.Grid{Width: 4, Height: 4})
+	warehouseMap.Blocked[model.Cell{X: 1, Y: 0}] = true
+	warehouseMap.Blocked[model.Cell{X: 1, Y: 1}] = true
+	warehouseMap.Shelves[model.Cell{X: 3, Y: 2}] = true
+	warehouseMap.Zones = append(warehouseMap.Zones, model.NoGoZone{
+		Name: "charging bay",
+		Min:  model.Cell{X: 2, Y: 3},
+		Max:  model.Cell{X: 3, Y: 3},
+	})
+
+	tests := []struct {
+		name     string
+		from     model.Cell
+		to       model.Cell
+		expected string
+		err      error
+	}{
+		{"straight_line", model.Cell{X: 0, Y: 0}, model.Cell{X: 0, Y: 3}, "NNN", nil},
+		{"around_blocked_cells", model.Cell{X: 0, Y: 0}, model.Cell{X: 2, Y: 0}, "NNEESS", nil},
+		{"same_cell", model.Cell{X: 0, Y: 0}, model.Cell{X: 0, Y: 0}, "", nil},
+		{"target_outside_grid", model.Cell{X: 0, Y: 0}, model.Cell{X: 4, Y: 0}, "", model.ErrBoundary},
+		{"target_is_shelf", model.Cell{X: 0, Y: 0}, model.Cell{X: 3, Y: 2}, "", model.ErrObstacle},
+		{"target_in_zone", model.Cell{X: 0, Y: 0}, model.Cell{X: 2, Y: 3}, "", model.ErrObstacle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ShortestPath(warehouseMap, tt.from, tt.to)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ShortestPath() error = %v, expected %v", err, tt.err)
+			}
+			if path != tt.expected {
+				t.Errorf("ShortestPath() = %q, expected %q", path, tt.expected)
+			}
+		})
+	}
+}
+
+func TestShortestPath_NoPath(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.Grid{Width: 3, Height: 3})
+	warehouseMap.Walls[model.Cell{X: 1, Y: 0}] = true
+	warehouseMap.Walls[model.Cell{X: 1, Y: 1}] = true
+	warehouseMap.Walls[model.Cell{X: 1, Y: 2}] = true
+
+	_, err := ShortestPath(warehouseMap, model.Cell{X: 0, Y: 0}, model.Cell{X: 2, Y: 2})
+	if !errors.Is(err, model.ErrNoPath) {
+		t.Errorf("ShortestPath() error = %v, expected ErrNoPath", err)
+	}
+}
@@ -0,0 +1,89 @@
+package planner
+
+import (
+	"warehouse-robots/backend/api/model"
+)
+
+// Constraints tells the space-time planner where other robots are going to be.
+// Ticks are counted from the start of the plan being searched: the robot is on
+// its start cell at tick 0 and on the cell after its i-th command at tick i.
+type Constraints interface {
+	// Blocked reports whether moving from one cell to the next, arriving at the
+	// tick, runs into another robot or swaps cells with one head-on.
+	Blocked(tick int, from, to model.Cell) bool
+
+	// Settled reports whether the robot may stay on the cell from the tick on
+	// without any other robot passing through it later.
+	Settled(tick int, cell model.Cell) bool
+}
+
+// ConflictFreePath returns the shortest N/S/E/W command string that drives a
+// robot from one cell to another while keeping clear of the other robots
+// described by the constraints. The SDK has no wait command, so a robot that
+// has to let another one pass does so by stepping aside and back; the search
+// runs over (cell, tick) states to find such detours and gives up after
+// maxSteps commands.
+//
+// Error Returns:
+//   - ErrBoundary: the target lies outside the grid.
+//   - ErrObstacle: the target itself cannot be entered.
+//   - ErrNoPath: no route of at most maxSteps commands reaches the target in time.
+func ConflictFreePath(layout *model.WarehouseMap, from, to model.Cell, constraints Constraints, maxSteps int) (string, error) {
+	if !layout.Grid.Contains(int(to.X), int(to.Y)) {
+		return "", model.ErrBoundary
+	}
+	if obstruction := layout.Obstruction(int(to.X), int(to.Y)); obstruction != "" {
+		return "", &model.PlanError{Err: model.ErrObstacle, Step: 0, X: int(to.X), Y: int(to.Y), Reason: obstruction}
+	}
+	if from == to && constraints.Settled(0, to) {
+		return "", nil
+	}
+
+	type state struct {
+		cell model.Cell
+		tick int
+	}
+	type step struct {
+		from    state
+		command byte
+	}
+	start := state{cell: from}
+	parent := map[state]step{start: {}}
+	queue := []state{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.tick >= maxSteps {
+			continue
+		}
+
+		for _, m := range moves {
+			x, y := int(current.cell.X)+m.dx, int(current.cell.Y)+m.dy
+			if !layout.Grid.Contains(x, y) || layout.Obstruction(x, y) != "" {
+				continue
+			}
+
+			next := state{cell: model.Cell{X: uint(x), Y: uint(y)}, tick: current.tick + 1}
+			if _, seen := parent[next]; seen {
+				continue
+			}
+			if constraints.Blocked(next.tick, current.cell, next.cell) {
+				continue
+			}
+			parent[next] = step{from: current, command: m.command}
+
+			if next.cell == to && constraints.Settled(next.tick, to) {
+				path := make([]byte, next.tick)
+				for s := next; s != start; s = parent[s].from {
+					path[s.tick-1] = parent[s].command
+				}
+				return string(path), nil
+			}
+
+			queue = append(queue, next)
+		}
+	}
+
+	return "", model.ErrNoPath
+}
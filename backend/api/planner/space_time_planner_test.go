@@ -0,0 +1,75 @@
+package planner
+
+import (
+	"errors"
+	"testing"
+
+	"warehouse-robots/backend/api/model"
+)
+
+// timeline is another robot that is on cells[tick] and rests on the last cell.
+type timeline []model.Cell
+
+func (o timeline) at(tick int) model.Cell {
+	return o[min(max(tick, 0), len(o)-1)]
+}
+
+func (o timeline) Blocked(tick int, from, to model.Cell) bool {
+	return o.at(tick) == to || (o.at(tick-1) == to && o.at(tick) == from)
+}
+
+func (o timeline) Settled(tick int, cell model.Cell) bool {
+	for t := tick; t < len(o); t++ {
+		if o[t] == cell {
+			return false
+		}
+	}
+	return o.at(tick) != cell
+}
+
+func TestConflictFreePath(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.Grid{Width: 3, Height: 3})
+
+	tests := []struct {
+		name     string
+		from     model.Cell
+		to       model.Cell
+		other    timeline
+		maxSteps int
+		expected string
+		err      error
+	}{
+		{"free_straight_line", model.Cell{X: 0, Y: 1}, model.Cell{X: 2, Y: 1},
+			timeline{{X: 0, Y: 0}}, 10, "EE", nil},
+		{"around_parked_robot", model.Cell{X: 0, Y: 1}, model.Cell{X: 2, Y: 1},
+			timeline{{X: 1, Y: 1}}, 10, "NEES", nil},
+		// the other robot crosses (1,1) at tick 1, so the robot steps aside first
+		{"let_crossing_robot_pass", model.Cell{X: 0, Y: 1}, model.Cell{X: 2, Y: 1},
+			timeline{{X: 1, Y: 2}, {X: 1, Y: 1}, {X: 1, Y: 0}, {X: 2, Y: 0}}, 10, "NEES", nil},
+		// heading straight at each other down the middle row
+		{"no_head_on_swap", model.Cell{X: 0, Y: 1}, model.Cell{X: 1, Y: 1},
+			timeline{{X: 1, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 0}}, 10, "NES", nil},
+		// the other robot passes the target at tick 3, so the robot may only settle there at tick 5
+		{"wait_until_target_is_clear", model.Cell{X: 0, Y: 0}, model.Cell{X: 1, Y: 0},
+			timeline{{X: 2, Y: 2}, {X: 2, Y: 1}, {X: 2, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 2}}, 10, "NNSSE", nil},
+		{"target_taken_for_good", model.Cell{X: 0, Y: 0}, model.Cell{X: 1, Y: 0},
+			timeline{{X: 1, Y: 0}}, 10, "", model.ErrNoPath},
+		{"too_few_steps", model.Cell{X: 0, Y: 1}, model.Cell{X: 2, Y: 1},
+			timeline{{X: 1, Y: 1}}, 3, "", model.ErrNoPath},
+		{"target_outside_grid", model.Cell{X: 0, Y: 0}, model.Cell{X: 3, Y: 0},
+			timeline{{X: 2, Y: 2}}, 10, "", model.ErrBoundary},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := ConflictFreePath(warehouseMap, tt.from, tt.to, tt.other, tt.maxSteps)
+
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ConflictFreePath() error = %v, expected %v", err, tt.err)
+			}
+			if path != tt.expected {
+				t.Errorf("ConflictFreePath() = %q, expected %q", path, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IBatchMoveService sends several robots to their destinations in one go.
+// Implementations are expected to:
+//   - Plan routes in priority order, each one keeping clear of the paths already
+//     reserved by the fleet and of the routes planned earlier in the batch.
+//   - Retry with a different priority order when a robot cannot be routed.
+//   - Run every route through the same validation as ICreateTaskService.
+//   - Enqueue nothing unless a route was found for every robot.
+type IBatchMoveService interface {
+	// MoveRobots plans conflict-free routes for all moves and enqueues one goto
+	// task per robot.
+	//
+	// Parameters:
+	//   - req: one destination per robot; earlier moves get priority.
+	//
+	// Returns:
+	//   - BatchMovePlan with each robot's task ID, commands and path length, and the makespan.
+	//
+	// Error Returns
+	//	 - ErrNoPath: no joint plan exists; nothing was enqueued.
+	//	 - ErrValidation: a robot is already at its destination.
+	//	 - Otherwise the same as ICreateTaskService.CreateTask, for the first robot that fails.
+	MoveRobots(req dtos.BatchMoveRequest) (*dtos.BatchMovePlan, error)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+	"warehouse-robots/backend/api/planner"
+)
+
+// BatchMoveServiceImpl is the default implementation of IBatchMoveService.
+// It uses prioritized planning: robots are routed one at a time through a
+// space-time search that treats the reservations of the fleet and of the
+// robots routed before them as moving obstacles. When a robot cannot be
+// routed it is moved to the front of the order and the batch is planned again.
+type BatchMoveServiceImpl struct {
+	createTaskService *CreateTaskServiceImpl
+}
+
+// NewBatchMoveService constructs a BatchMoveServiceImpl on top of the create task service.
+func NewBatchMoveService(createTaskService *CreateTaskServiceImpl) IBatchMoveService {
+	return &BatchMoveServiceImpl{
+		createTaskService: createTaskService,
+	}
+}
+
+// batchPlan is the route found for one move of the batch.
+type batchPlan struct {
+	plan  *taskPlan
+	delay int64 // steps the robot's queued tasks still take before this route starts
+}
+
+// MoveRobots plans all moves and, once every robot has a route, enqueues them.
+// If one cannot be enqueued, the moves already enqueued are cancelled again.
+func (s *BatchMoveServiceImpl) MoveRobots(req dtos.BatchMoveRequest) (*dtos.BatchMovePlan, error) {
+	s.createTaskService.queueMu.Lock()
+	defer s.createTaskService.queueMu.Unlock()
+
+	if err := s.createTaskService.acceptingTasks(); err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(req.Moves))
+	for i := range order {
+		order[i] = i
+	}
+
+	var plans []*batchPlan
+	for attempt := 0; ; attempt++ {
+		var failed int
+		var err error
+		plans, failed, err = s.planInOrder(req.Moves, order)
+		if err == nil {
+			break
+		}
+
+		// only routing failures can be helped by another order, and a robot
+		// that cannot be routed even with top priority never will be
+		if !errors.Is(err, model.ErrNoPath) || order[0] == failed || attempt >= len(order) {
+			log.Printf("batch move rejected after %d attempt(s): %v", attempt+1, err)
+			return nil, err
+		}
+		order = promote(order, failed)
+	}
+
+	result := &dtos.BatchMovePlan{Tasks: make([]dtos.BatchMoveTask, 0, len(plans))}
+	enqueued := make([]*model.Task, 0, len(plans))
+	for i, move := range req.Moves {
+		task, err := s.createTaskService.enqueuePlan(move.RobotID, plans[i].plan)
+		if err != nil {
+			log.Printf("batch move: enqueue move of robot %s: %v", move.RobotID, err)
+			s.rollBack(enqueued, err)
+			return nil, err
+		}
+		enqueued = append(enqueued, task)
+
+		result.Tasks = append(result.Tasks, dtos.BatchMoveTask{
+			RobotID:    move.RobotID,
+			TaskID:     task.TaskID,
+			Commands:   task.Commands,
+			Goto:       move.Goto,
+			PathLength: len(task.Commands),
+		})
+		result.Makespan = max(result.Makespan, int(plans[i].delay)+len(task.Commands))
+	}
+	result.EstimatedDurationMs = (time.Duration(result.Makespan) * constant.RobotStepDuration).Milliseconds()
+
+	return result, nil
+}
+
+// rollBack cancels the tasks of a batch that could not be enqueued as a whole,
+// the last enqueued first, and frees their paths. Callers must hold queueMu.
+func (s *BatchMoveServiceImpl) rollBack(tasks []*model.Task, cause error) {
+	c := s.createTaskService
+	reason := fmt.Sprintf("cancelled: the batch move could not be enqueued: %s", describeError(cause))
+
+	for _, task := range slices.Backward(tasks) {
+		robot, err := c.robots.Get(task.RobotID)
+		if err != nil {
+			log.Printf("batch move: resolve robot %q of task %s: %v", task.RobotID, task.TaskID, err)
+			continue
+		}
+		if err := robot.CancelTask(task.SDKID()); err != nil {
+			log.Printf("batch move: sdk cancel of task %s: %v", task.TaskID, err)
+			continue
+		}
+		if err := c.taskMonitor.CancelTask(task.TaskID, reason); err != nil {
+			log.Printf("batch move: update task %s: %v", task.TaskID, err)
+		}
+		state := robot.CurrentState()
+		c.reservations.Release(task.TaskID, &model.Cell{X: state.X, Y: state.Y})
+	}
+}
+
+// planInOrder routes the moves in the given priority order against a copy of
+// the reservation table, so nothing is reserved for real. It returns the plans
+// in request order, or the index of the move that failed and why.
+func (s *BatchMoveServiceImpl) planInOrder(moves []dtos.BatchMove, order []int) ([]*batchPlan, int, error) {
+	reservations := s.createTaskService.reservations.Clone()
+	plans := make([]*batchPlan, len(moves))
+
+	// robots of the batch leave their cells, so robots routed before them may
+	// end there; robots routed after them plan around those routes in turn
+	for _, move := range moves {
+		reservations.Unpark(move.RobotID)
+	}
+
+	for _, i := range order {
+		move := moves[i]
+		plan, err := s.planMove(reservations, move)
+		if err != nil {
+			return nil, i, err
+		}
+
+		plans[i] = &batchPlan{plan: plan, delay: reservations.Delay(move.RobotID)}
+		reservations.Reserve(move.RobotID, "", trajectoryCells(plan.start, plan.commands))
+	}
+
+	return plans, -1, nil
+}
+
+// planMove finds a conflict-free route for one robot and validates it like any
+// other goto task.
+func (s *BatchMoveServiceImpl) planMove(reservations *manager.ReservationTable, move dtos.BatchMove) (*taskPlan, error) {
+	c := s.createTaskService
+
+	robot, tasks, start, err := c.resolveStart(move.RobotID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := model.Cell{X: start.X, Y: start.Y}
+	target := model.Cell{X: move.Goto.X, Y: move.Goto.Y}
+	if from == target {
+		return nil, fmt.Errorf("%w: robot %s is already at (%d,%d)", model.ErrValidation, move.RobotID, target.X, target.Y)
+	}
+
+	commands, err := planner.ConflictFreePath(c.warehouseMap, from, target,
+		reservations.PathConstraints(move.RobotID), constant.MaxExpandedCommands)
+	if errors.Is(err, model.ErrNoPath) {
+		log.Printf("batch move: no conflict-free route for robot %s from (%d,%d) to (%d,%d)",
+			move.RobotID, from.X, from.Y, target.X, target.Y)
+		return nil, fmt.Errorf("%w: no conflict-free route for robot %s to (%d,%d)", err, move.RobotID, target.X, target.Y)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.validatePlan(start, commands, queuedCrateMoves(tasks)); err != nil {
+		return nil, err
+	}
+
+	// the planner and the table apply the same rules; checking again keeps the
+	// batch honest should they ever drift apart
+	if err := reservations.Check(move.RobotID, trajectoryCells(start, commands)); err != nil {
+		return nil, err
+	}
+
+	return &taskPlan{robot: robot, start: start, commands: commands, target: &target}, nil
+}
+
+// promote moves the given index to the front of the order, keeping the rest in place.
+func promote(order []int, index int) []int {
+	promoted := []int{index}
+	for _, i := range order {
+		if i != index {
+			promoted = append(promoted, i)
+		}
+	}
+	return promoted
+}
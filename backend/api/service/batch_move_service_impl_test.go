@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// newBatchMoveFixture puts two robots at both ends of a 3x2 warehouse.
+func newBatchMoveFixture(repository dao.ITaskRepository) (IBatchMoveService, *stubRobot, *stubRobot, *manager.ReservationTable) {
+	left := &stubRobot{state: model.RobotState{X: 0, Y: 0}}
+	right := &stubRobot{prefix: "right_", state: model.RobotState{X: 2, Y: 0}}
+
+	reservations := manager.NewReservationTable(constant.RobotStepDuration)
+	reservations.Park("0", model.Cell{X: 0, Y: 0})
+	reservations.Park("1", model.Cell{X: 2, Y: 0})
+
+	createTaskService := newTestCreateTaskService(stubRegistry(left, right),
+		model.NewWarehouseMap(model.Grid{Width: 3, Height: 2}), repository, reservations)
+	return NewBatchMoveService(createTaskService), left, right, reservations
+}
+
+func TestBatchMoveServiceImpl_MoveRobots_SwapEnds(t *testing.T) {
+	service, left, right, _ := newBatchMoveFixture(dao.NewInMemoryTaskRepository())
+
+	plan, err := service.MoveRobots(dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "0", Goto: dtos.CellRef{X: 2, Y: 0}},
+		{RobotID: "1", Goto: dtos.CellRef{X: 0, Y: 0}},
+	}})
+	if err != nil {
+		t.Fatalf("MoveRobots() error = %v", err)
+	}
+
+	// robot 0 has priority and drives straight; robot 1 ducks into the top row to let it pass
+	if len(left.enqueued) != 1 || left.enqueued[0] != "EE" {
+		t.Errorf("expected robot 0 to get EE, got %v", left.enqueued)
+	}
+	if len(right.enqueued) != 1 || right.enqueued[0] != "NWSW" {
+		t.Errorf("expected robot 1 to get NWSW, got %v", right.enqueued)
+	}
+	if plan.Makespan != 4 || plan.Tasks[0].PathLength != 2 || plan.Tasks[1].PathLength != 4 {
+		t.Errorf("expected path lengths 2 and 4 and makespan 4, got %+v", plan)
+	}
+}
+
+func TestBatchMoveServiceImpl_MoveRobots_NoJointPlan(t *testing.T) {
+	service, left, right, _ := newBatchMoveFixture(dao.NewInMemoryTaskRepository())
+
+	// both robots want to end on the same cell, in either order
+	_, err := service.MoveRobots(dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "0", Goto: dtos.CellRef{X: 1, Y: 1}},
+		{RobotID: "1", Goto: dtos.CellRef{X: 1, Y: 1}},
+	}})
+	if !errors.Is(err, model.ErrNoPath) {
+		t.Fatalf("MoveRobots() error = %v, expected ErrNoPath", err)
+	}
+	if len(left.enqueued) != 0 || len(right.enqueued) != 0 {
+		t.Errorf("expected nothing to be enqueued, got %v and %v", left.enqueued, right.enqueued)
+	}
+}
+
+// failingCreateRepository fails to store any task after the first failAfter.
+type failingCreateRepository struct {
+	dao.ITaskRepository
+	failAfter int
+	created   int
+}
+
+func (r *failingCreateRepository) Create(task *model.Task) error {
+	if r.created == r.failAfter {
+		return errors.New("disk full")
+	}
+	r.created++
+	return r.ITaskRepository.Create(task)
+}
+
+func TestBatchMoveServiceImpl_MoveRobots_RollsBackOnEnqueueFailure(t *testing.T) {
+	repository := &failingCreateRepository{ITaskRepository: dao.NewInMemoryTaskRepository(), failAfter: 1}
+	service, left, right, reservations := newBatchMoveFixture(repository)
+
+	plan, err := service.MoveRobots(dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "0", Goto: dtos.CellRef{X: 2, Y: 0}},
+		{RobotID: "1", Goto: dtos.CellRef{X: 0, Y: 0}},
+	}})
+	if err == nil || plan != nil {
+		t.Fatalf("expected the batch to fail, got %+v (%v)", plan, err)
+	}
+
+	// both robots got their task, and both were withdrawn again
+	if len(left.cancelled) != 1 || len(right.cancelled) != 1 {
+		t.Errorf("expected both SDK tasks cancelled, got %v and %v", left.cancelled, right.cancelled)
+	}
+	tasks, _ := repository.GetByRobotId("0")
+	if len(tasks) != 1 || tasks[0].Status != model.TaskStatusCancelled || tasks[0].Error == "" {
+		t.Fatalf("expected robot 0's task CANCELLED with a reason, got %+v", tasks)
+	}
+	if pending := reservations.Reservations(); len(pending) != 0 {
+		t.Errorf("expected no paths left reserved, got %+v", pending)
+	}
+	if err := reservations.Check("1", []model.Cell{{X: 0, Y: 0}}); err == nil {
+		t.Error("expected robot 0 to be parked on its cell again")
+	}
+}
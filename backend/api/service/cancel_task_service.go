@@ -4,7 +4,8 @@ package service
 //   - If the task is already terminal (COMPLETED, FAILED, or CANCELLED): reject.
 //   - If the task is PENDING: invoke the SDK's CancelTask with up to
 //     three retries using backoff.
-//   - On success: stop monitoring and persist status = CANCELLED.
+//   - On success: stop monitoring, persist status = CANCELLED and re-validate the
+//     tasks queued behind it (see ITaskQueueService).
 //   - On repeated failure: we dont do anything.
 type ICancelTaskService interface {
 	// CancelTaskById cancels the task in both sdk and updates status in the db
@@ -1,8 +1,8 @@
 package service
 
 import (
+	"errors"
 	"log"
-	"strconv"
 	"time"
 
 	"warehouse-robots/backend/api/dao"
@@ -11,27 +11,34 @@ import (
 )
 
 type CancelTaskServiceImpl struct {
-	warehouse   model.Warehouse
-	repository  dao.ITaskRepository
-	taskMonitor *manager.TaskMonitor
+	robots           *manager.RobotRegistry
+	repository       dao.ITaskRepository
+	taskMonitor      *manager.TaskMonitor
+	taskQueueService ITaskQueueService
 }
 
-// NewCancelTaskService constructor
+// NewCancelTaskService constructor. taskMonitor is the monitor the tasks were
+// started with, so cancelling a task also stops watching it.
 func NewCancelTaskService(
-	warehouse model.Warehouse,
-	repository dao.ITaskRepository) ICancelTaskService {
+	robots *manager.RobotRegistry,
+	repository dao.ITaskRepository,
+	taskMonitor *manager.TaskMonitor,
+	taskQueueService ITaskQueueService) ICancelTaskService {
 	return &CancelTaskServiceImpl{
-		warehouse:   warehouse,
-		repository:  repository,
-		taskMonitor: manager.NewTaskMonitor(repository),
+		robots:           robots,
+		repository:       repository,
+		taskMonitor:      taskMonitor,
+		taskQueueService: taskQueueService,
 	}
 }
 
 // CancelTaskById cancels a task.
 // Rules:
 //   - If task is TERMINAL (COMPLETED/FAILED/CANCELLED): reject.
-//   - If task is PENDING: attempt SDK CancelTask with retries; on success, stop monitor and mark CANCELLED.
-//     On repeated failure, mark FAILED with an explanatory error.
+//   - If task is PENDING: attempt SDK CancelTask with retries; on success, mark it CANCELLED once the
+//     monitor recorded where the robot stopped, then re-validate the tasks queued behind it from there.
+//     A task the SDK does not know cannot run any more and is cancelled too; one the SDK already
+//     finished is rejected like a terminal task. On repeated failure, leave the task as it is.
 func (s *CancelTaskServiceImpl) CancelTaskById(taskId string) error {
 	task, err := s.repository.GetById(taskId)
 	if err != nil {
@@ -46,10 +53,10 @@ func (s *CancelTaskServiceImpl) CancelTaskById(taskId string) error {
 		return model.ErrTaskProcessed
 
 	case model.TaskStatusPending:
-		robot, err := s.getRobotByRobotID(task.RobotID)
+		robot, err := s.robots.Get(task.RobotID)
 		if err != nil {
 			log.Printf("resolve robot %q: %v", task.RobotID, err)
-			return model.ErrRobotNotFound
+			return err
 		}
 
 		// Retry SDK cancel a few times
@@ -57,17 +64,28 @@ func (s *CancelTaskServiceImpl) CancelTaskById(taskId string) error {
 		baseDelay := 100 * time.Millisecond
 		var lastErr error
 		for i := 0; i < maxRetries; i++ {
-			if err := robot.CancelTask(taskId); err == nil {
+			err := robot.CancelTask(task.SDKID())
+			switch {
+			case err == nil, errors.Is(err, model.ErrSDKTaskUnknown):
+				if err != nil {
+					log.Printf("sdk does not know task %s, cancelling it: %v", taskId, err)
+				}
 				// SDK accepted so we need to update the task status to CANCELLED
-				if monErr := s.taskMonitor.CancelTask(taskId); monErr != nil {
-					// If no monitor found, still update status explicitly
-					_ = s.repository.UpdateStatus(taskId, model.TaskStatusCancelled, "cancelled by user")
+				if monErr := s.taskMonitor.CancelTask(taskId, "cancelled by user"); monErr != nil {
+					log.Printf("update task %s: %v", taskId, monErr)
 				}
+				// the tasks queued behind were validated from where this one would have ended
+				s.taskQueueService.RevalidateQueueAfter(taskId)
 				return nil
-			} else {
-				lastErr = err
-				time.Sleep(time.Duration(1<<i) * baseDelay)
+
+			case errors.Is(err, model.ErrSDKTaskFinished):
+				// the monitor records how it ended
+				log.Printf("task %s finished before it could be cancelled: %v", taskId, err)
+				return model.ErrTaskProcessed
 			}
+
+			lastErr = err
+			time.Sleep(time.Duration(1<<i) * baseDelay)
 		}
 
 		// Could not cancel in SDK even after retry, then dont do anything
@@ -77,20 +95,3 @@ func (s *CancelTaskServiceImpl) CancelTaskById(taskId string) error {
 
 	return nil
 }
-
-// getRobotByID resolves a robot from the warehouse by numeric string ID.
-// The robotID is expected to be a base-10 string representing a zero-based index
-// into the slice returned by warehouse.Robots() (e.g., "0", "1", ...).
-func (s *CancelTaskServiceImpl) getRobotByRobotID(robotID string) (model.Robot, error) {
-	robotIndex, err := strconv.Atoi(robotID)
-	if err != nil {
-		return nil, model.ErrRobotIDInvalid
-	}
-
-	robots := s.warehouse.Robots()
-	if robotIndex < 0 || robotIndex >= len(robots) {
-		log.Printf("robot index %v out of range (0-%d)", robotIndex, len(robots)-1)
-		return nil, model.ErrRobotIDInvalid
-	}
-	return robots[robotIndex], nil
-}
@@ -1,14 +1,17 @@
 package service
 
 import (
+	"errors"
 	"testing"
 	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/manager"
 	"warehouse-robots/backend/api/model"
 )
 
 func TestCreateTaskServiceImpl_validateBoundary(t *testing.T) {
 	// Create a service instance for testing
-	service := &CreateTaskServiceImpl{}
+	service := &CreateTaskServiceImpl{warehouseMap: model.NewWarehouseMap(model.DefaultGrid())}
 
 	tests := []struct {
 		name        string
@@ -213,7 +216,7 @@ func TestCreateTaskServiceImpl_validateBoundary(t *testing.T) {
 }
 
 func TestCreateTaskServiceImpl_validateBoundary_InvalidCommands(t *testing.T) {
-	service := &CreateTaskServiceImpl{}
+	service := &CreateTaskServiceImpl{warehouseMap: model.NewWarehouseMap(model.DefaultGrid())}
 	start := &model.Position{X: 5, Y: 5, HasCrate: false}
 
 	invalidCommands := []string{
@@ -238,7 +241,7 @@ func TestCreateTaskServiceImpl_validateBoundary_InvalidCommands(t *testing.T) {
 }
 
 func TestCreateTaskServiceImpl_validateBoundary_NilPosition(t *testing.T) {
-	service := &CreateTaskServiceImpl{}
+	service := &CreateTaskServiceImpl{warehouseMap: model.NewWarehouseMap(model.DefaultGrid())}
 
 	// This test checks behavior with nil position - may cause panic in current implementation
 	defer func() {
@@ -256,7 +259,7 @@ func TestCreateTaskServiceImpl_validateBoundary_NilPosition(t *testing.T) {
 
 // Benchmark test for performance
 func BenchmarkCreateTaskServiceImpl_validateBoundary(b *testing.B) {
-	service := &CreateTaskServiceImpl{}
+	service := &CreateTaskServiceImpl{warehouseMap: model.NewWarehouseMap(model.DefaultGrid())}
 	start := &model.Position{X: 5, Y: 5, HasCrate: false}
 	commands := "NNESSSWWNNEESSSWW"
 
@@ -268,3 +271,57 @@ func BenchmarkCreateTaskServiceImpl_validateBoundary(b *testing.B) {
 		}
 	}
 }
+
+// flakyRobot answers CancelTask with the given errors in turn, then succeeds.
+type flakyRobot struct {
+	stubRobot
+	errs []error
+}
+
+func (r *flakyRobot) CancelTask(taskID string) error {
+	r.cancelled = append(r.cancelled, taskID)
+	if len(r.cancelled) <= len(r.errs) {
+		return r.errs[len(r.cancelled)-1]
+	}
+	return nil
+}
+
+func TestCancelTaskServiceImpl_CancelTaskById_SDKErrors(t *testing.T) {
+	busy := errors.New("robot busy")
+	tests := []struct {
+		name       string
+		errs       []error
+		wantErr    error
+		wantCalls  int
+		wantStatus model.TaskStatus
+	}{
+		{"retried_until_accepted", []error{busy, busy}, nil, 3, model.TaskStatusCancelled},
+		{"gives_up_after_retries", []error{busy, busy, busy}, model.ErrSDKFailedToCancel, 3, model.TaskStatusPending},
+		{"unknown_task_is_cancelled", []error{model.ErrSDKTaskUnknown}, nil, 1, model.TaskStatusCancelled},
+		{"finished_task_is_not_retried", []error{model.ErrSDKTaskFinished}, model.ErrTaskProcessed, 1, model.TaskStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robot := &flakyRobot{errs: tt.errs}
+			repository := dao.NewInMemoryTaskRepository()
+			createTaskService := newTestCreateTaskService(stubRegistry(robot),
+				model.NewWarehouseMap(model.DefaultGrid()), repository, manager.NewReservationTable(constant.RobotStepDuration))
+			service := NewCancelTaskService(createTaskService.robots, repository, createTaskService.taskMonitor,
+				NewTaskQueueService(createTaskService))
+
+			_ = repository.Create(&model.Task{TaskID: "a", RobotID: "0", Commands: "N", Status: model.TaskStatusPending,
+				PlannedStart: &model.Position{X: 0, Y: 0}})
+
+			if err := service.CancelTaskById("a"); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CancelTaskById() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(robot.cancelled) != tt.wantCalls {
+				t.Errorf("expected %d SDK cancels, got %d", tt.wantCalls, len(robot.cancelled))
+			}
+			if task, _ := repository.GetById("a"); task.Status != tt.wantStatus {
+				t.Errorf("expected the task to be %s, got %s", tt.wantStatus, task.Status)
+			}
+		})
+	}
+}
@@ -6,10 +6,14 @@ import (
 
 // ICreateTaskService coordinates validation, enqueueing, persistence, and monitoring
 // of robot tasks. Implementations are expected to:
-//   - Resolve the target robot from the warehouse/SDK.
-//   - Derive the starting position from the most recent terminal task and reject
-//     creation if there is an active (pending/running) task.
-//   - Validate the command sequence against warehouse bounds.
+//   - Resolve the target robot from the robot registry.
+//   - Derive the starting position from the projected end of the last queued task,
+//     or the most recent terminal task when the robot is idle, and reject creation
+//     once the robot's queue is full.
+//   - Expand compact commands such as "(NE)3" into single-letter commands, or plan a
+//     shortest route when a goto target is given instead.
+//   - Validate the command sequence against warehouse bounds, obstacles and crate rules.
+//   - Check the route against other robots' space-time reservations and reserve it.
 //   - Enqueue the commands to the SDK and persist a PENDING task record.
 //   - Start background monitoring to keep task status/position up to date.
 type ICreateTaskService interface {
@@ -17,15 +21,22 @@ type ICreateTaskService interface {
 	//
 	// Parameters:
 	//   - robotID: identifier of the target robot.
-	//   - req:     command payload to execute.
+	//   - req:     command payload to execute, or a goto target to plan a route to.
 	//
 	// Returns:
 	//   - TaskInfo snapshot of the newly created task on success.
 	//
 	// Error Returns
 	//	 - ErrRobotNotFound: robot not found
-	//   - ErrTaskNotFound: task not found by the robot id
-	//   - ErrTaskQueueFull: task is pending, but we want to queue another one.
+	//   - the repository error, wrapped, if the robot's tasks cannot be read
+	//   - ErrTaskQueueFull: the robot already has constant.MaxQueuedTasks pending tasks.
 	//	 - ErrBoundary: the robot will move out of the boundary if execute the given command.
+	//	 - ErrObstacle: the robot would enter a blocked cell or no-go zone (wrapped in *model.PlanError).
+	//	 - ErrCrate: a grab or drop in the given command cannot be performed.
+	//	 - ErrNoPath: no route reaches the goto target.
+	//	 - ErrPathConflict: the route collides with another robot's reservation (wrapped in *model.PlanError).
+	//	 - ErrValidation: the commands do not parse (wrapped in *model.SyntaxError) or the
+	//	   robot is already at the goto target.
+	//	 - ErrShuttingDown: the service is shutting down and takes no new tasks.
 	CreateTask(robotID string, req dtos.CreateTaskRequest) (*dtos.TaskInfo, error)
 }
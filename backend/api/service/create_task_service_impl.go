@@ -1,11 +1,13 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"sort"
-	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"warehouse-robots/backend/api/constant"
@@ -13,117 +15,310 @@ import (
 	"warehouse-robots/backend/api/dtos"
 	"warehouse-robots/backend/api/manager"
 	"warehouse-robots/backend/api/model"
+	"warehouse-robots/backend/api/parser"
+	"warehouse-robots/backend/api/planner"
 )
 
 // CreateTaskServiceImpl coordinates validation, enqueue, and monitoring of robot tasks.
-// It retrieves the target robot from the warehouse SDK, validates the command
-// plan against warehouse bounds, persists a task record, and starts background
+// It resolves the target robot from the robot registry, validates the command
+// plan against the warehouse map, the crate inventory and the paths reserved by
+// other robots, persists a task record, reserves its path, and starts background
 // monitoring to keep the task status and position up to date.
 type CreateTaskServiceImpl struct {
-	warehouse   model.Warehouse
-	repository  dao.ITaskRepository
-	taskMonitor *manager.TaskMonitor
+	robots          *manager.RobotRegistry
+	warehouseMap    *model.WarehouseMap
+	repository      dao.ITaskRepository
+	crateRepository dao.ICrateRepository
+	reservations    *manager.ReservationTable
+	taskMonitor     *manager.TaskMonitor
+	bus             *manager.EventBus
+
+	// queueMu serialises changes to robot task queues, so a new task is never
+	// validated against a queue that is being re-validated at the same time
+	queueMu sync.Mutex
+
+	// draining is set on shutdown; new tasks are refused from then on
+	draining atomic.Bool
 }
 
 // NewCreateTaskService constructs a CreateTaskServiceImpl with the provided
-// warehouse SDK handle and task repository.
+// robot registry, warehouse map, task repository, crate inventory, path
+// reservations, the shared task monitor and the event bus.
 func NewCreateTaskService(
-	warehouse model.Warehouse,
+	robots *manager.RobotRegistry,
+	warehouseMap *model.WarehouseMap,
 	repository dao.ITaskRepository,
+	crateRepository dao.ICrateRepository,
+	reservations *manager.ReservationTable,
+	taskMonitor *manager.TaskMonitor,
+	bus *manager.EventBus,
 ) *CreateTaskServiceImpl {
 	return &CreateTaskServiceImpl{
-		warehouse:   warehouse,
-		repository:  repository,
-		taskMonitor: manager.NewTaskMonitor(repository),
+		robots:          robots,
+		warehouseMap:    warehouseMap,
+		repository:      repository,
+		crateRepository: crateRepository,
+		reservations:    reservations,
+		taskMonitor:     taskMonitor,
+		bus:             bus,
 	}
 }
 
+// taskPlan is a request that passed validation and is ready to be enqueued or previewed.
+type taskPlan struct {
+	robot    model.Robot
+	start    *model.Position
+	commands string      // canonical single-letter commands
+	target   *model.Cell // goto target, nil for plain commands
+}
+
 // CreateTask validates and enqueues a new task for the given robot.
 // Returns a TaskInfo snapshot for the newly created task or an error.
 func (s *CreateTaskServiceImpl) CreateTask(robotID string, req dtos.CreateTaskRequest) (*dtos.TaskInfo, error) {
-	robot, err := s.getRobotByID(robotID)
-	if err != nil {
-		log.Printf("robot not found: %v", err)
-		return nil, model.ErrRobotNotFound
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+
+	if err := s.acceptingTasks(); err != nil {
+		return nil, err
 	}
 
-	tasks, err := s.repository.GetByRobotId(robotID)
+	plan, err := s.planTask(robotID, req)
 	if err != nil {
-		return nil, model.ErrTaskNotFound
+		return nil, err
 	}
 
-	startPos, err := s.calculateStartPosition(robotID, tasks)
+	task, err := s.enqueuePlan(robotID, plan)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.validateBoundary(startPos, req.Commands); err != nil {
-		return nil, err
+	return &dtos.TaskInfo{
+		TaskID:    task.TaskID,
+		RobotID:   robotID,
+		Status:    dtos.TaskStatusPending,
+		Commands:  plan.commands,
+		Goto:      req.Goto,
+		CreatedAt: task.CreatedAt,
+	}, nil
+}
+
+// enqueuePlan sends a validated plan to the robot, reserves its path, persists
+// the PENDING task and starts monitoring it. Callers must hold queueMu.
+func (s *CreateTaskServiceImpl) enqueuePlan(robotID string, plan *taskPlan) (*model.Task, error) {
+	sdkTaskID, posCh, errCh := plan.robot.EnqueueTask(normalizeCommands(plan.commands))
+	if sdkTaskID == "" {
+		// the SDK turned the task down (its queue is full) and says why on errCh
+		select {
+		case err := <-errCh:
+			log.Printf("sdk refused task for robot %s: %v", robotID, err)
+		default:
+			log.Printf("sdk refused task for robot %s", robotID)
+		}
+		return nil, model.ErrTaskQueueFull
 	}
 
-	taskID, posCh, errCh := robot.EnqueueTask(req.Commands)
+	taskID, err := s.freeTaskID(sdkTaskID)
+	if err != nil {
+		log.Printf("task id for sdk task %s: %v", sdkTaskID, err)
+		if cancelErr := plan.robot.CancelTask(sdkTaskID); cancelErr != nil {
+			log.Printf("sdk cancel of unrecorded task %s: %v", sdkTaskID, cancelErr)
+		}
+		return nil, err
+	}
 
 	task := &model.Task{
 		TaskID:          taskID,
 		RobotID:         robotID,
-		Commands:        req.Commands,
+		Commands:        plan.commands,
+		Target:          plan.target,
+		PlannedStart:    plan.start,
 		Status:          model.TaskStatusPending,
 		CurrentPosition: nil, // updated by monitor
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+	if taskID != sdkTaskID {
+		task.SDKTaskID = sdkTaskID
+	}
+
+	// the path was checked against the reservations under the same queue lock
+	s.reservations.Reserve(robotID, taskID, trajectoryCells(plan.start, plan.commands))
 
 	if err := s.repository.Create(task); err != nil {
-		// The SDK has already accepted the task; still start monitoring, but return the persistence error.
-		s.taskMonitor.StartMonitoring(taskID, posCh, errCh)
 		log.Printf("repo.Create task=%s robot=%s failed: %v", taskID, robotID, err)
+		// The SDK has already accepted the task; nothing records it, so withdraw it.
+		// The monitor still reads whatever the SDK reports for it.
+		if cancelErr := plan.robot.CancelTask(sdkTaskID); cancelErr != nil {
+			log.Printf("sdk cancel of unrecorded task %s: %v", taskID, cancelErr)
+		} else {
+			state := plan.robot.CurrentState()
+			s.reservations.Release(taskID, &model.Cell{X: state.X, Y: state.Y})
+		}
+		s.taskMonitor.StartMonitoring(taskID, posCh, errCh)
 		return nil, err
 	}
 
+	s.bus.Publish(model.TaskCreated{
+		TaskID:   taskID,
+		RobotID:  robotID,
+		Commands: plan.commands,
+		Target:   plan.target,
+		At:       task.CreatedAt,
+	})
+
 	// Everytime we create a new task,
 	// we will create a goroutine to listen to the channel and update the new position on our database
 	s.taskMonitor.StartMonitoring(taskID, posCh, errCh)
 
-	return &dtos.TaskInfo{
-		TaskID:    taskID,
-		RobotID:   robotID,
-		Status:    dtos.TaskStatusPending,
-		Commands:  req.Commands,
-		CreatedAt: task.CreatedAt,
-	}, nil
+	return task, nil
+}
+
+// acceptingTasks returns ErrShuttingDown once the service has started draining.
+func (s *CreateTaskServiceImpl) acceptingTasks() error {
+	if s.draining.Load() {
+		log.Printf("task refused: shutting down")
+		return model.ErrShuttingDown
+	}
+	return nil
+}
+
+// freeTaskID returns the SDK's task ID, or a variant of it ("task_0_1.2") if a
+// stored task already has that ID: the SDK numbers its tasks afresh after a
+// restart, while a persistent repository keeps the earlier ones. An ID is free
+// only if the repository reports it not found; any other error is returned.
+func (s *CreateTaskServiceImpl) freeTaskID(sdkTaskID string) (string, error) {
+	taskID := sdkTaskID
+	for n := 2; ; n++ {
+		_, err := s.repository.GetById(taskID)
+		if errors.Is(err, model.ErrTaskNotFound) {
+			return taskID, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		taskID = fmt.Sprintf("%s.%d", sdkTaskID, n)
+	}
+}
+
+// planTask runs the validation pipeline shared by task creation and preview:
+// resolve the robot, derive its start position, turn the request into canonical
+// commands and check them against the warehouse map and crate inventory.
+// Nothing is sent to the robot.
+func (s *CreateTaskServiceImpl) planTask(robotID string, req dtos.CreateTaskRequest) (*taskPlan, error) {
+	robot, tasks, startPos, err := s.resolveStart(robotID)
+	if err != nil {
+		return nil, err
+	}
+
+	// both compact commands and goto targets are turned into the canonical
+	// command string up front, so they go through exactly the same validation
+	var commands string
+	var target *model.Cell
+	if req.Goto != nil {
+		target = &model.Cell{X: req.Goto.X, Y: req.Goto.Y}
+		if commands, err = s.planRoute(startPos, *target); err != nil {
+			return nil, err
+		}
+	} else if commands, err = parser.Expand(req.Commands); err != nil {
+		return nil, err
+	}
+
+	if err := s.validatePlan(startPos, commands, queuedCrateMoves(tasks)); err != nil {
+		return nil, err
+	}
+
+	if err := s.reservations.Check(robotID, trajectoryCells(startPos, commands)); err != nil {
+		log.Printf("path conflict for robot %s: %v", robotID, describeError(err))
+		return nil, err
+	}
+
+	return &taskPlan{robot: robot, start: startPos, commands: commands, target: target}, nil
 }
 
-// getRobotByID resolves a robot from the warehouse by numeric string ID.
-// The robotID is expected to be a base-10 string representing a zero-based index
-// into the slice returned by warehouse.Robots() (e.g., "0", "1", ...).
-func (s *CreateTaskServiceImpl) getRobotByID(robotID string) (model.Robot, error) {
-	robotIndex, err := strconv.Atoi(robotID)
+// resolveStart looks up the robot and its tasks and derives where a new task
+// for it would start.
+func (s *CreateTaskServiceImpl) resolveStart(robotID string) (model.Robot, []*model.Task, *model.Position, error) {
+	robot, err := s.robots.Get(robotID)
+	if err != nil {
+		log.Printf("resolve robot %q: %v", robotID, err)
+		return nil, nil, nil, err
+	}
+
+	tasks, err := s.repository.GetByRobotId(robotID)
+	if err != nil {
+		log.Printf("get tasks for robot %s: %v", robotID, err)
+		return nil, nil, nil, fmt.Errorf("get tasks for robot %s: %w", robotID, err)
+	}
+
+	start, err := s.calculateStartPosition(robot, tasks)
 	if err != nil {
-		return nil, fmt.Errorf("invalid robot ID %q: %v", robotID, err)
+		return nil, nil, nil, err
+	}
+	return robot, tasks, start, nil
+}
+
+// validatePlan checks commands from the start position against the warehouse
+// bounds, obstacles and crate rules. moved holds crate changes made by earlier
+// queued tasks and is updated with this plan's grabs and drops.
+func (s *CreateTaskServiceImpl) validatePlan(start *model.Position, commands string, moved map[[2]int]bool) error {
+	if err := s.validateBoundary(start, commands); err != nil {
+		return err
 	}
 
-	robots := s.warehouse.Robots()
-	if robotIndex < 0 || robotIndex >= len(robots) {
-		return nil, fmt.Errorf("robot index %d out of range (0-%d)", robotIndex, len(robots)-1)
+	if err := s.validateObstacles(start, commands); err != nil {
+		return err
 	}
-	return robots[robotIndex], nil
+
+	return s.validateCrateOperations(start, commands, moved)
+}
+
+// planRoute finds the shortest route from the start position to the goto target.
+// A target equal to the start position is rejected as there is nothing to do.
+func (s *CreateTaskServiceImpl) planRoute(start *model.Position, target model.Cell) (string, error) {
+	from := model.Cell{X: start.X, Y: start.Y}
+	if from == target {
+		log.Printf("goto rejected: robot is already at (%d,%d)", target.X, target.Y)
+		return "", fmt.Errorf("%w: robot is already at (%d,%d)", model.ErrValidation, target.X, target.Y)
+	}
+
+	commands, err := planner.ShortestPath(s.warehouseMap, from, target)
+	if err != nil {
+		log.Printf("goto (%d,%d) from (%d,%d) could not be planned: %v", target.X, target.Y, from.X, from.Y, err)
+		return "", err
+	}
+	return commands, nil
 }
 
 // calculateStartPosition determines the robot’s starting point when queuing a new task.
 //
 // Policy:
-//   - Only one active task per robot is allowed; if a PENDING task exists, reject.
-//   - Use the most recent TERMINAL task to derive the next start:
+//   - Up to constant.MaxQueuedTasks PENDING (running or queued) tasks are allowed; beyond that, reject.
+//   - If tasks are PENDING, start from the projected end of the last one queued:
+//     its commands replayed from the position it was validated from.
+//   - Otherwise use the most recent TERMINAL task to derive the next start:
 //   - COMPLETED or FAILED or CANCELLED → use its last known CurrentPosition.
-//   - If no prior task exists, default to (0,0,false).
+//   - If no prior task exists, fall back to the robot's current SDK state so the
+//     crate it may already be carrying is taken into account.
+//
+// If a queued task no longer ends where it was projected to (an earlier task
+// failed or was cancelled), the queue is re-validated from the robot's actual
+// position, see ITaskQueueService.
 //
 // Returns the computed starting position or an error if the request should be rejected.
-func (s *CreateTaskServiceImpl) calculateStartPosition(robotID string, tasks []*model.Task) (*model.Position, error) {
-	for _, task := range tasks {
-		if task.Status == model.TaskStatusPending {
-			log.Printf("task %s is pending", task.TaskID)
+func (s *CreateTaskServiceImpl) calculateStartPosition(robot model.Robot, tasks []*model.Task) (*model.Position, error) {
+	if pending := pendingTasks(tasks); len(pending) > 0 {
+		if len(pending) >= constant.MaxQueuedTasks {
+			log.Printf("robot already has %d pending tasks", len(pending))
+			return nil, model.ErrTaskQueueFull
+		}
+
+		last := pending[len(pending)-1]
+		if last.PlannedStart == nil {
+			// without a planned start there is nothing to project from
+			log.Printf("task %s is pending without a planned start", last.TaskID)
 			return nil, model.ErrTaskQueueFull
 		}
+		return projectedEnd(last), nil
 	}
 
 	sort.Slice(tasks, func(i, j int) bool {
@@ -143,11 +338,12 @@ func (s *CreateTaskServiceImpl) calculateStartPosition(robotID string, tasks []*
 		}
 	}
 
-	return &model.Position{X: 0, Y: 0, HasCrate: false}, nil
+	state := robot.CurrentState()
+	return &model.Position{X: state.X, Y: state.Y, HasCrate: state.HasCrate}, nil
 }
 
 // validateBoundary simulates the command sequence from a starting position and
-// ensures every step remains within the warehouse bounds.
+// ensures every step remains within the configured warehouse grid.
 //
 // Commands are case-insensitive single letters; whitespace is ignored.
 // Valid moves: N (y+1), S (y-1), E (x+1), W (x-1).
@@ -155,10 +351,10 @@ func (s *CreateTaskServiceImpl) calculateStartPosition(robotID string, tasks []*
 func (s *CreateTaskServiceImpl) validateBoundary(start *model.Position, commands string) error {
 	x, y := int(start.X), int(start.Y)
 
-	maxX := constant.WarehouseSizeX - 1
-	maxY := constant.WarehouseSizeY - 1
-	minX := constant.MinCoordinateX
-	minY := constant.MinCoordinateY
+	maxX := int(s.warehouseMap.Grid.MaxX())
+	maxY := int(s.warehouseMap.Grid.MaxY())
+	minX := int(s.warehouseMap.Grid.OriginX)
+	minY := int(s.warehouseMap.Grid.OriginY)
 
 	commands = strings.ToUpper(strings.ReplaceAll(commands, " ", ""))
 
@@ -196,3 +392,219 @@ func (s *CreateTaskServiceImpl) validateBoundary(start *model.Position, commands
 
 	return nil
 }
+
+// validateObstacles simulates the command sequence from a starting position and
+// ensures no step enters a blocked cell or no-go zone of the warehouse map.
+// Returns a *model.PlanError wrapping ErrObstacle with the offending step.
+func (s *CreateTaskServiceImpl) validateObstacles(start *model.Position, commands string) error {
+	x, y := int(start.X), int(start.Y)
+
+	for i, cmd := range normalizeCommands(commands) {
+		switch cmd {
+		case 'N':
+			y += constant.RobotMoveUnit
+		case 'S':
+			y -= constant.RobotMoveUnit
+		case 'E':
+			x += constant.RobotMoveUnit
+		case 'W':
+			x -= constant.RobotMoveUnit
+		default:
+			continue
+		}
+
+		if obstruction := s.warehouseMap.Obstruction(x, y); obstruction != "" {
+			log.Printf("obstacle violation: command '%c' at index %d enters %s at (%d,%d)", cmd, i, obstruction, x, y)
+			return &model.PlanError{Err: model.ErrObstacle, Step: i, X: x, Y: y, Reason: obstruction}
+		}
+	}
+
+	return nil
+}
+
+// validateCrateOperations simulates the command sequence from a starting position
+// and ensures every grab (G) and drop (D) is physically possible.
+//
+// Rules:
+//   - G: the robot must not already hold a crate and its cell must contain one.
+//   - D: the robot must hold a crate and its cell must be empty.
+//
+// Cell contents are read from the crate inventory; crates moved by earlier queued
+// tasks or earlier in the same sequence are tracked in moved, which is updated
+// in place (nil starts an empty overlay).
+// Returns ErrCrate on the first impossible operation.
+func (s *CreateTaskServiceImpl) validateCrateOperations(start *model.Position, commands string, moved map[[2]int]bool) error {
+	x, y := int(start.X), int(start.Y)
+	hasCrate := start.HasCrate
+
+	// cells whose crate state changed during the simulation
+	if moved == nil {
+		moved = make(map[[2]int]bool)
+	}
+	cellHasCrate := func(x, y int) (bool, error) {
+		if crate, ok := moved[[2]int{x, y}]; ok {
+			return crate, nil
+		}
+		crate, err := s.crateRepository.GetByCell(uint(x), uint(y))
+		if err != nil {
+			log.Printf("crate inventory lookup (%d,%d) failed: %v", x, y, err)
+			return false, model.ErrInternal
+		}
+		return crate != nil, nil
+	}
+
+	for i, cmd := range normalizeCommands(commands) {
+		switch cmd {
+		case 'N':
+			y += constant.RobotMoveUnit
+		case 'S':
+			y -= constant.RobotMoveUnit
+		case 'E':
+			x += constant.RobotMoveUnit
+		case 'W':
+			x -= constant.RobotMoveUnit
+		case 'G':
+			if hasCrate {
+				log.Printf("crate violation: grab at index %d while already holding a crate at (%d,%d)", i, x, y)
+				return model.ErrCrate
+			}
+			occupied, err := cellHasCrate(x, y)
+			if err != nil {
+				return err
+			}
+			if !occupied {
+				log.Printf("crate violation: grab at index %d from empty cell (%d,%d)", i, x, y)
+				return model.ErrCrate
+			}
+			hasCrate = true
+			moved[[2]int{x, y}] = false
+		case 'D':
+			if !hasCrate {
+				log.Printf("crate violation: drop at index %d without holding a crate at (%d,%d)", i, x, y)
+				return model.ErrCrate
+			}
+			occupied, err := cellHasCrate(x, y)
+			if err != nil {
+				return err
+			}
+			if occupied {
+				log.Printf("crate violation: drop at index %d onto occupied cell (%d,%d)", i, x, y)
+				return model.ErrCrate
+			}
+			hasCrate = false
+			moved[[2]int{x, y}] = true
+		}
+	}
+
+	return nil
+}
+
+// normalizeCommands upper-cases the command string and strips whitespace so the
+// SDK only ever receives single-letter directives.
+func normalizeCommands(commands string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(commands), ""))
+}
+
+// pendingTasks returns the robot's PENDING tasks in the order they were queued.
+func pendingTasks(tasks []*model.Task) []*model.Task {
+	var pending []*model.Task
+	for _, task := range tasks {
+		if task.Status == model.TaskStatusPending {
+			pending = append(pending, task)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].CreatedAt.Before(pending[j].CreatedAt)
+	})
+	return pending
+}
+
+// projectedEnd is where a task leaves the robot if every command succeeds.
+func projectedEnd(task *model.Task) *model.Position {
+	trajectory, _ := simulateTrajectory(task.PlannedStart, task.Commands)
+	end := trajectory[len(trajectory)-1]
+	return &model.Position{X: end.X, Y: end.Y, HasCrate: end.HasCrate}
+}
+
+// simulateTrajectory replays validated commands from the start position.
+// It returns the start state followed by the state after each command, and
+// the number of moves (grabs and drops do not change cell).
+func simulateTrajectory(start *model.Position, commands string) ([]dtos.RobotState, int) {
+	state := dtos.RobotState{X: start.X, Y: start.Y, HasCrate: start.HasCrate}
+	trajectory := make([]dtos.RobotState, 0, len(commands)+1)
+	trajectory = append(trajectory, state)
+	moves := 0
+
+	for _, cmd := range commands {
+		switch cmd {
+		case 'N':
+			state.Y += constant.RobotMoveUnit
+			moves++
+		case 'S':
+			state.Y -= constant.RobotMoveUnit
+			moves++
+		case 'E':
+			state.X += constant.RobotMoveUnit
+			moves++
+		case 'W':
+			state.X -= constant.RobotMoveUnit
+			moves++
+		case 'G':
+			state.HasCrate = true
+		case 'D':
+			state.HasCrate = false
+		}
+		trajectory = append(trajectory, state)
+	}
+
+	return trajectory, moves
+}
+
+// trajectoryCells is the cell the robot occupies before and after each command,
+// as recorded in the reservation table.
+func trajectoryCells(start *model.Position, commands string) []model.Cell {
+	trajectory, _ := simulateTrajectory(start, commands)
+	cells := make([]model.Cell, len(trajectory))
+	for i, state := range trajectory {
+		cells[i] = model.Cell{X: state.X, Y: state.Y}
+	}
+	return cells
+}
+
+// queuedCrateMoves returns the crates the robot's queued tasks will have moved
+// by the time a new task starts, in the form validateCrateOperations expects.
+func queuedCrateMoves(tasks []*model.Task) map[[2]int]bool {
+	moved := make(map[[2]int]bool)
+	for _, task := range pendingTasks(tasks) {
+		replayCrateOperations(task.PlannedStart, task.Commands, moved)
+	}
+	return moved
+}
+
+// replayCrateOperations records the grabs and drops of an accepted plan in moved
+// without checking them; a task that is already running may have applied some
+// of them to the inventory, and replaying leaves those cells in the same state.
+func replayCrateOperations(start *model.Position, commands string, moved map[[2]int]bool) {
+	if start == nil {
+		return
+	}
+
+	x, y := int(start.X), int(start.Y)
+	for _, cmd := range commands {
+		switch cmd {
+		case 'N':
+			y += constant.RobotMoveUnit
+		case 'S':
+			y -= constant.RobotMoveUnit
+		case 'E':
+			x += constant.RobotMoveUnit
+		case 'W':
+			x -= constant.RobotMoveUnit
+		case 'G':
+			moved[[2]int{x, y}] = false
+		case 'D':
+			moved[[2]int{x, y}] = true
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestCreateTaskServiceImpl_validateCrateOperations(t *testing.T) {
+	crateRepository := dao.NewInMemoryCrateRepository()
+	_ = crateRepository.Place(&model.Crate{ID: "crate-1", X: 1, Y: 0})
+	_ = crateRepository.Place(&model.Crate{ID: "crate-2", X: 2, Y: 0})
+
+	service := &CreateTaskServiceImpl{crateRepository: crateRepository}
+
+	tests := []struct {
+		name        string
+		start       *model.Position
+		commands    string
+		expectError bool
+	}{
+		{"grab_from_crate_cell", &model.Position{X: 0, Y: 0}, "EG", false},
+		{"grab_then_drop_elsewhere", &model.Position{X: 0, Y: 0}, "EGND", false},
+		{"drop_then_grab_back", &model.Position{X: 0, Y: 0, HasCrate: true}, "DG", false},
+		{"grab_moved_crate", &model.Position{X: 0, Y: 0}, "EGNDG", false},
+		{"lowercase_with_spaces", &model.Position{X: 0, Y: 0}, "e g", false},
+		{"grab_while_holding", &model.Position{X: 0, Y: 0, HasCrate: true}, "EG", true},
+		{"grab_from_empty_cell", &model.Position{X: 0, Y: 0}, "G", true},
+		{"grab_after_crate_taken", &model.Position{X: 0, Y: 0}, "EGDGG", true},
+		{"drop_onto_crate", &model.Position{X: 0, Y: 0, HasCrate: true}, "ED", true},
+		{"drop_without_crate", &model.Position{X: 0, Y: 0}, "ND", true},
+		{"drop_onto_dropped_crate", &model.Position{X: 0, Y: 0}, "EGNDSEGWND", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateCrateOperations(tt.start, tt.commands, nil)
+
+			if tt.expectError && err != model.ErrCrate {
+				t.Errorf("validateCrateOperations() expected ErrCrate but got %v", err)
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("validateCrateOperations() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateTaskServiceImpl_validateObstacles(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.DefaultGrid())
+	warehouseMap.Blocked[model.Cell{X: 2, Y: 0}] = true
+	warehouseMap.Zones = append(warehouseMap.Zones, model.NoGoZone{
+		Name: "charging bay",
+		Min:  model.Cell{X: 0, Y: 3},
+		Max:  model.Cell{X: 1, Y: 4},
+	})
+
+	service := &CreateTaskServiceImpl{warehouseMap: warehouseMap}
+	start := &model.Position{X: 0, Y: 0}
+
+	tests := []struct {
+		name      string
+		commands  string
+		expectErr *model.PlanError
+	}{
+		{"free_path", "NNEE", nil},
+		{"around_blocked_cell", "ENEES", nil},
+		{"enters_blocked_cell", "EE", &model.PlanError{Step: 1, X: 2, Y: 0, Reason: "blocked cell"}},
+		{"enters_zone_after_crate_ops", "N G N N", &model.PlanError{Step: 3, X: 0, Y: 3, Reason: `no-go zone "charging bay"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.validateObstacles(start, tt.commands)
+
+			if tt.expectErr == nil {
+				if err != nil {
+					t.Errorf("validateObstacles() unexpected error = %v", err)
+				}
+				return
+			}
+
+			planErr, ok := err.(*model.PlanError)
+			if !ok || planErr.Err != model.ErrObstacle {
+				t.Fatalf("validateObstacles() expected *PlanError wrapping ErrObstacle but got %v", err)
+			}
+			if planErr.Step != tt.expectErr.Step || planErr.X != tt.expectErr.X ||
+				planErr.Y != tt.expectErr.Y || planErr.Reason != tt.expectErr.Reason {
+				t.Errorf("validateObstacles() got %+v, want %+v", planErr, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestSimulateTrajectory(t *testing.T) {
+	start := &model.Position{X: 1, Y: 1}
+
+	trajectory, pathLength := simulateTrajectory(start, "NEGSD")
+
+	expected := []dtos.RobotState{
+		{X: 1, Y: 1},
+		{X: 1, Y: 2},
+		{X: 2, Y: 2},
+		{X: 2, Y: 2, HasCrate: true},
+		{X: 2, Y: 1, HasCrate: true},
+		{X: 2, Y: 1},
+	}
+
+	if len(trajectory) != len(expected) {
+		t.Fatalf("simulateTrajectory() returned %d states, expected %d", len(trajectory), len(expected))
+	}
+	for i := range expected {
+		if trajectory[i] != expected[i] {
+			t.Errorf("simulateTrajectory() state %d = %+v, expected %+v", i, trajectory[i], expected[i])
+		}
+	}
+	if pathLength != 3 {
+		t.Errorf("simulateTrajectory() path length = %d, expected 3", pathLength)
+	}
+}
+
+func TestCreateTaskServiceImpl_calculateStartPosition(t *testing.T) {
+	service := &CreateTaskServiceImpl{}
+	robot := &stubRobot{state: model.RobotState{X: 4, Y: 4, HasCrate: true}}
+	now := time.Now()
+
+	queued := func(n int) []*model.Task {
+		var tasks []*model.Task
+		for i := 0; i < n; i++ {
+			tasks = append(tasks, &model.Task{
+				TaskID:       "queued",
+				Commands:     "N",
+				Status:       model.TaskStatusPending,
+				PlannedStart: &model.Position{X: 1, Y: uint(i)},
+				CreatedAt:    now.Add(time.Duration(i) * time.Second),
+			})
+		}
+		return tasks
+	}
+
+	tests := []struct {
+		name     string
+		tasks    []*model.Task
+		expected *model.Position
+		err      error
+	}{
+		{"no_tasks_uses_robot_state", nil, &model.Position{X: 4, Y: 4, HasCrate: true}, nil},
+		{"terminal_task_position", []*model.Task{
+			{Status: model.TaskStatusCompleted, CurrentPosition: &model.Position{X: 2, Y: 3}, UpdatedAt: now},
+		}, &model.Position{X: 2, Y: 3}, nil},
+		{"projected_end_of_last_queued", []*model.Task{
+			{Commands: "NNEG", Status: model.TaskStatusPending, PlannedStart: &model.Position{X: 1, Y: 1}, CreatedAt: now.Add(time.Second)},
+			{Commands: "SS", Status: model.TaskStatusPending, PlannedStart: &model.Position{X: 1, Y: 3}, CreatedAt: now},
+		}, &model.Position{X: 2, Y: 3, HasCrate: true}, nil},
+		{"queue_below_limit", queued(4), &model.Position{X: 1, Y: 4}, nil},
+		{"queue_full", queued(5), nil, model.ErrTaskQueueFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, err := service.calculateStartPosition(robot, tt.tasks)
+
+			if err != tt.err {
+				t.Fatalf("calculateStartPosition() error = %v, expected %v", err, tt.err)
+			}
+			if tt.expected != nil && *start != *tt.expected {
+				t.Errorf("calculateStartPosition() = %+v, expected %+v", *start, *tt.expected)
+			}
+		})
+	}
+}
+
+// fullQueueRobot turns every task down the way the SDK does when its queue is
+// full: an empty task ID, with the reason on the error channel.
+type fullQueueRobot struct {
+	stubRobot
+}
+
+func (r *fullQueueRobot) EnqueueTask(commands string) (string, chan model.RobotState, chan error) {
+	r.enqueued = append(r.enqueued, commands)
+	errCh := make(chan error, 1)
+	errCh <- errors.New("task queue is full")
+	return "", make(chan model.RobotState), errCh
+}
+
+func TestCreateTaskServiceImpl_CreateTask_SDKQueueFull(t *testing.T) {
+	robot := &fullQueueRobot{}
+	repository := dao.NewInMemoryTaskRepository()
+	reservations := manager.NewReservationTable(constant.RobotStepDuration)
+	service := newTestCreateTaskService(stubRegistry(robot), model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}),
+		repository, reservations)
+
+	task, err := service.CreateTask("0", dtos.CreateTaskRequest{Commands: "N E"})
+	if !errors.Is(err, model.ErrTaskQueueFull) || task != nil {
+		t.Fatalf("CreateTask() = %+v, %v, expected ErrTaskQueueFull", task, err)
+	}
+	if len(robot.enqueued) != 1 {
+		t.Errorf("expected the SDK to be asked once, got %v", robot.enqueued)
+	}
+	if tasks, _ := repository.GetByRobotId("0"); len(tasks) != 0 {
+		t.Errorf("expected nothing stored, got %+v", tasks)
+	}
+	if pending := reservations.Reservations(); len(pending) != 0 {
+		t.Errorf("expected nothing reserved, got %+v", pending)
+	}
+}
+
+// brokenLookupRepository fails every lookup of a task by ID.
+type brokenLookupRepository struct {
+	dao.ITaskRepository
+}
+
+func (r *brokenLookupRepository) GetById(taskID string) (*model.Task, error) {
+	return nil, errors.New("database is locked")
+}
+
+var errDiskIO = errors.New("disk I/O error")
+
+// brokenRobotTasksRepository fails every lookup of a robot's tasks.
+type brokenRobotTasksRepository struct {
+	dao.ITaskRepository
+}
+
+func (r *brokenRobotTasksRepository) GetByRobotId(robotID string) ([]*model.Task, error) {
+	return nil, errDiskIO
+}
+
+func TestCreateTaskServiceImpl_CreateTask_RobotTasksLookupFails(t *testing.T) {
+	robot := &stubRobot{}
+	repository := &brokenRobotTasksRepository{ITaskRepository: dao.NewInMemoryTaskRepository()}
+	service := newTestCreateTaskService(stubRegistry(robot), model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}),
+		repository, manager.NewReservationTable(constant.RobotStepDuration))
+
+	task, err := service.CreateTask("0", dtos.CreateTaskRequest{Commands: "N E"})
+	if !errors.Is(err, errDiskIO) || errors.Is(err, model.ErrTaskNotFound) || task != nil {
+		t.Fatalf("CreateTask() = %+v, %v, expected the repository error", task, err)
+	}
+	if len(robot.enqueued) != 0 {
+		t.Errorf("expected nothing sent to the robot, got %v", robot.enqueued)
+	}
+}
+
+func TestCreateTaskServiceImpl_CreateTask_RepositoryLookupFails(t *testing.T) {
+	robot := &stubRobot{}
+	repository := &brokenLookupRepository{ITaskRepository: dao.NewInMemoryTaskRepository()}
+	reservations := manager.NewReservationTable(constant.RobotStepDuration)
+	service := newTestCreateTaskService(stubRegistry(robot), model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}),
+		repository, reservations)
+
+	task, err := service.CreateTask("0", dtos.CreateTaskRequest{Commands: "N E"})
+	if err == nil || err.Error() != "database is locked" || task != nil {
+		t.Fatalf("CreateTask() = %+v, %v, expected the repository error", task, err)
+	}
+	if len(robot.cancelled) != 1 || robot.cancelled[0] != "stub_1" {
+		t.Errorf("expected the SDK task to be withdrawn, got %v", robot.cancelled)
+	}
+	if tasks, _ := repository.GetByRobotId("0"); len(tasks) != 0 {
+		t.Errorf("expected nothing stored, got %+v", tasks)
+	}
+	if pending := reservations.Reservations(); len(pending) != 0 {
+		t.Errorf("expected nothing reserved, got %+v", pending)
+	}
+}
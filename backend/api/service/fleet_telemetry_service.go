@@ -0,0 +1,40 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// FleetFeed is an open subscription to the events of the whole fleet.
+type FleetFeed struct {
+	// Ready is signalled when events are waiting, and closed once the feed
+	// has been dropped for falling behind or closed.
+	Ready <-chan struct{}
+	// Drain takes the waiting events, oldest first; open is false once
+	// nothing more will arrive.
+	Drain func() (events []dtos.TaskEvent, open bool)
+	// SetFilter replaces the filter for events published from now on.
+	SetFilter func(filter dtos.FleetFilter) error
+	// Close releases the subscription.
+	Close func()
+}
+
+// IFleetTelemetryService multicasts robot positions and task lifecycle events
+// for all robots.
+// Implementations are expected to:
+//   - Never hold up the task monitors for a slow consumer: position updates of
+//     a robot are coalesced to the latest one, and a consumer that lets other
+//     events pile up is dropped.
+//   - Keep the events of each task in the order they happened.
+type IFleetTelemetryService interface {
+	// SubscribeFleet opens a feed of the events that pass the filter.
+	//
+	// Parameters:
+	//   - filter: robot IDs and event types to receive; empty lists mean all.
+	//
+	// Returns:
+	//   - FleetFeed delivering the events published from now on.
+	//
+	// Error Returns:
+	//   - ErrValidation: the filter names an unknown event type.
+	SubscribeFleet(filter dtos.FleetFilter) (*FleetFeed, error)
+}
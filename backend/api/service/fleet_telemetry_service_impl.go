@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// FleetTelemetryServiceImpl is the default implementation of IFleetTelemetryService.
+// It subscribes to the task event stream the task monitors publish to.
+type FleetTelemetryServiceImpl struct {
+	events *manager.TaskEventStream
+}
+
+// NewFleetTelemetryService constructor
+func NewFleetTelemetryService(events *manager.TaskEventStream) IFleetTelemetryService {
+	return &FleetTelemetryServiceImpl{
+		events: events,
+	}
+}
+
+// SubscribeFleet validates the filter, then subscribes to the events of all tasks.
+func (s *FleetTelemetryServiceImpl) SubscribeFleet(filter dtos.FleetFilter) (*FleetFeed, error) {
+	fleetFilter, err := toFleetFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := s.events.SubscribeFleet(fleetFilter)
+
+	return &FleetFeed{
+		Ready: sub.Ready(),
+		Drain: func() ([]dtos.TaskEvent, bool) {
+			events, open := sub.Drain()
+			dtoEvents := make([]dtos.TaskEvent, 0, len(events))
+			for _, event := range events {
+				dtoEvents = append(dtoEvents, toTaskEventDto(event))
+			}
+			return dtoEvents, open
+		},
+		SetFilter: func(filter dtos.FleetFilter) error {
+			fleetFilter, err := toFleetFilter(filter)
+			if err != nil {
+				return err
+			}
+			sub.SetFilter(fleetFilter)
+			return nil
+		},
+		Close: sub.Close,
+	}, nil
+}
+
+// toFleetFilter converts a filter DTO, rejecting unknown event types.
+func toFleetFilter(filter dtos.FleetFilter) (manager.FleetFilter, error) {
+	fleetFilter := manager.FleetFilter{
+		RobotIDs: make(map[string]bool, len(filter.RobotIDs)),
+		Types:    make(map[model.TaskEventType]bool, len(filter.Types)),
+	}
+	for _, robotID := range filter.RobotIDs {
+		fleetFilter.RobotIDs[robotID] = true
+	}
+	for _, eventType := range filter.Types {
+		switch t := model.TaskEventType(eventType); t {
+		case model.TaskEventStatus, model.TaskEventPosition, model.TaskEventError, model.TaskEventEnd:
+			fleetFilter.Types[t] = true
+		default:
+			return manager.FleetFilter{}, fmt.Errorf("%w: unknown event type %q", model.ErrValidation, eventType)
+		}
+	}
+	return fleetFilter, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IPreviewTaskService dry-runs a task request. Implementations are expected to:
+//   - Run the same validation pipeline as ICreateTaskService.CreateTask.
+//   - Never enqueue anything on the robot or persist a task.
+//   - Simulate the accepted commands to report the route the robot would take.
+type IPreviewTaskService interface {
+	// PreviewTask validates the request for the given robot and simulates it.
+	//
+	// Parameters:
+	//   - robotID: identifier of the target robot.
+	//   - req:     command payload or goto target, as for CreateTask.
+	//
+	// Returns:
+	//   - TaskPreview with the trajectory, final position, path length and estimated duration.
+	//
+	// Error Returns
+	//	 - Same as ICreateTaskService.CreateTask.
+	PreviewTask(robotID string, req dtos.CreateTaskRequest) (*dtos.TaskPreview, error)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"time"
+
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dtos"
+)
+
+// PreviewTaskServiceImpl is the default implementation of IPreviewTaskService.
+// It borrows the validation pipeline of CreateTaskServiceImpl so a preview is
+// accepted or rejected exactly like the real request would be.
+type PreviewTaskServiceImpl struct {
+	createTaskService *CreateTaskServiceImpl
+}
+
+// NewPreviewTaskService constructs a PreviewTaskServiceImpl on top of the create task service.
+func NewPreviewTaskService(createTaskService *CreateTaskServiceImpl) IPreviewTaskService {
+	return &PreviewTaskServiceImpl{
+		createTaskService: createTaskService,
+	}
+}
+
+// PreviewTask validates the request and simulates the resulting commands from
+// the robot's start position without enqueueing them.
+func (s *PreviewTaskServiceImpl) PreviewTask(robotID string, req dtos.CreateTaskRequest) (*dtos.TaskPreview, error) {
+	plan, err := s.createTaskService.planTask(robotID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	trajectory, pathLength := simulateTrajectory(plan.start, plan.commands)
+
+	return &dtos.TaskPreview{
+		RobotID:             robotID,
+		Commands:            plan.commands,
+		Goto:                req.Goto,
+		Trajectory:          trajectory,
+		FinalPosition:       trajectory[len(trajectory)-1],
+		PathLength:          pathLength,
+		EstimatedDurationMs: (time.Duration(len(plan.commands)) * constant.RobotStepDuration).Milliseconds(),
+	}, nil
+}
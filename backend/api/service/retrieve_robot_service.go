@@ -0,0 +1,19 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IRetrieveRobotService exposes read-only access to the robots in the warehouse.
+// Implementations read the live robot state from the SDK and enrich it with the
+// robot's active task from the repository.
+type IRetrieveRobotService interface {
+	// RetrieveRobots returns a RobotInfo snapshot for every robot in the warehouse.
+	RetrieveRobots() ([]*dtos.RobotInfo, error)
+
+	// RetrieveRobotById returns a RobotInfo snapshot for the given robot ID.
+	//
+	// Error Returns:
+	//	 - ErrRobotNotFound: robot id does not resolve to a robot in the warehouse.
+	RetrieveRobotById(robotID string) (*dtos.RobotInfo, error)
+}
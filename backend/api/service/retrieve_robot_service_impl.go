@@ -0,0 +1,87 @@
+package service
+
+import (
+	"log"
+
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// RetrieveRobotServiceImpl is the default implementation of IRetrieveRobotService.
+// Robots are resolved through the robot registry and their positions come
+// straight from the SDK, so callers no longer need to infer them from the last
+// task record.
+type RetrieveRobotServiceImpl struct {
+	robots     *manager.RobotRegistry
+	repository dao.ITaskRepository
+}
+
+// NewRetrieveRobotService constructor
+func NewRetrieveRobotService(
+	robots *manager.RobotRegistry,
+	repository dao.ITaskRepository) IRetrieveRobotService {
+	return &RetrieveRobotServiceImpl{
+		robots:     robots,
+		repository: repository,
+	}
+}
+
+// RetrieveRobots lists every robot in service, in registration order.
+func (s *RetrieveRobotServiceImpl) RetrieveRobots() ([]*dtos.RobotInfo, error) {
+	robots := s.robots.List()
+
+	robotInfos := make([]*dtos.RobotInfo, 0, len(robots))
+	for _, robot := range robots {
+		robotInfo, err := s.toRobotInfo(robot.ID, robot.Robot)
+		if err != nil {
+			return nil, err
+		}
+		robotInfos = append(robotInfos, robotInfo)
+	}
+
+	return robotInfos, nil
+}
+
+// RetrieveRobotById resolves a single robot and returns its snapshot.
+func (s *RetrieveRobotServiceImpl) RetrieveRobotById(robotID string) (*dtos.RobotInfo, error) {
+	robot, err := s.robots.Get(robotID)
+	if err != nil {
+		log.Printf("resolve robot %q: %v", robotID, err)
+		return nil, err
+	}
+
+	return s.toRobotInfo(robotID, robot)
+}
+
+// toRobotInfo maps the SDK state of a robot and its active task into a RobotInfo.
+func (s *RetrieveRobotServiceImpl) toRobotInfo(robotID string, robot model.Robot) (*dtos.RobotInfo, error) {
+	state := robot.CurrentState()
+
+	tasks, err := s.repository.GetByRobotId(robotID)
+	if err != nil {
+		log.Printf("get tasks for robot %s: %v", robotID, err)
+		return nil, model.ErrInternal
+	}
+
+	robotInfo := &dtos.RobotInfo{
+		ID: robotID,
+		Position: dtos.RobotState{
+			X:        state.X,
+			Y:        state.Y,
+			HasCrate: state.HasCrate,
+		},
+	}
+
+	// the oldest PENDING task is the one being worked on, the rest wait behind it
+	for i, task := range pendingTasks(tasks) {
+		if i == 0 {
+			robotInfo.ActiveTaskID = task.TaskID
+			continue
+		}
+		robotInfo.QueuedTaskIDs = append(robotInfo.QueuedTaskIDs, task.TaskID)
+	}
+
+	return robotInfo, nil
+}
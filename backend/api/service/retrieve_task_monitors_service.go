@@ -0,0 +1,13 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IRetrieveTaskMonitorsService exposes the task monitors that are running, for
+// spotting tasks the robots have stopped reporting on.
+type IRetrieveTaskMonitorsService interface {
+	// RetrieveTaskMonitors returns a TaskMonitorInfo for every running
+	// monitor, the longest running first.
+	RetrieveTaskMonitors() ([]*dtos.TaskMonitorInfo, error)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"time"
+
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+)
+
+// RetrieveTaskMonitorsServiceImpl is the default implementation of
+// IRetrieveTaskMonitorsService, reading the shared task monitor and naming the
+// robot of each task from the repository.
+type RetrieveTaskMonitorsServiceImpl struct {
+	taskMonitor *manager.TaskMonitor
+	repository  dao.ITaskRepository
+}
+
+// NewRetrieveTaskMonitorsService constructor
+func NewRetrieveTaskMonitorsService(taskMonitor *manager.TaskMonitor, repository dao.ITaskRepository) IRetrieveTaskMonitorsService {
+	return &RetrieveTaskMonitorsServiceImpl{
+		taskMonitor: taskMonitor,
+		repository:  repository,
+	}
+}
+
+// RetrieveTaskMonitors lists the running monitors. A monitor whose task is not
+// in the repository, e.g. because storing it failed, is listed without a robot.
+func (s *RetrieveTaskMonitorsServiceImpl) RetrieveTaskMonitors() ([]*dtos.TaskMonitorInfo, error) {
+	now := time.Now()
+	monitors := s.taskMonitor.Monitors()
+
+	infos := make([]*dtos.TaskMonitorInfo, 0, len(monitors))
+	for _, monitor := range monitors {
+		info := &dtos.TaskMonitorInfo{
+			TaskID:             monitor.TaskID,
+			StartedAt:          monitor.StartedAt,
+			AgeMs:              now.Sub(monitor.StartedAt).Milliseconds(),
+			Events:             monitor.Events,
+			RemainingTimeoutMs: max(monitor.Deadline.Sub(now), 0).Milliseconds(),
+		}
+		if !monitor.LastEventAt.IsZero() {
+			lastEventAt := monitor.LastEventAt
+			info.LastEventAt = &lastEventAt
+		}
+		if task, err := s.repository.GetById(monitor.TaskID); err == nil {
+			info.RobotID = task.RobotID
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
@@ -34,6 +34,7 @@ func (s *RetrieveTaskServiceImpl) RetrieveTaskById(taskId string) (*dtos.TaskInf
 		Status:    mapToDtoStatus(task.Status),
 		Commands:  task.Commands,
 		Error:     task.Error,
+		Goto:      toCellRef(task.Target),
 		CreatedAt: task.CreatedAt,
 		UpdatedAt: task.UpdatedAt,
 	}
@@ -49,6 +50,14 @@ func (s *RetrieveTaskServiceImpl) RetrieveTaskById(taskId string) (*dtos.TaskInf
 	return taskInfo, nil
 }
 
+// toCellRef converts an optional goto target into its DTO form.
+func toCellRef(cell *model.Cell) *dtos.CellRef {
+	if cell == nil {
+		return nil
+	}
+	return &dtos.CellRef{X: cell.X, Y: cell.Y}
+}
+
 // mapToDtoStatus converts a domain TaskStatus into its DTO equivalent.
 func mapToDtoStatus(s model.TaskStatus) dtos.TaskStatus {
 	return dtos.TaskStatus(s)
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IRetrieveTaskTrajectoryService exposes the recorded history of a task.
+// Implementations replay the task's append-only events into the points the
+// robot passed and the statuses the task went through.
+type IRetrieveTaskTrajectoryService interface {
+	// RetrieveTaskTrajectory returns the trajectory of the given task.
+	// Returns
+	// - TaskTrajectory
+	//
+	// Error Returns:
+	// - ErrTaskNotFound: task id not found in db.
+	//
+	RetrieveTaskTrajectory(taskID string) (*dtos.TaskTrajectory, error)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/model"
+)
+
+// RetrieveTaskTrajectoryServiceImpl is the default implementation of
+// IRetrieveTaskTrajectoryService, reading the task history from the repository.
+type RetrieveTaskTrajectoryServiceImpl struct {
+	repository dao.ITaskRepository
+}
+
+// NewRetrieveTaskTrajectoryService constructor
+func NewRetrieveTaskTrajectoryService(repository dao.ITaskRepository) IRetrieveTaskTrajectoryService {
+	return &RetrieveTaskTrajectoryServiceImpl{
+		repository: repository,
+	}
+}
+
+// RetrieveTaskTrajectory replays the task's history, collecting the position
+// samples and the events that changed the status. The status and robot are
+// those of the replayed task, so they match what GET /api/tasks/{taskId} shows.
+func (s *RetrieveTaskTrajectoryServiceImpl) RetrieveTaskTrajectory(taskID string) (*dtos.TaskTrajectory, error) {
+	events, err := s.repository.History(taskID)
+	if err != nil {
+		return nil, model.ErrTaskNotFound
+	}
+
+	points := []dtos.TrajectoryPoint{}
+	transitions := []dtos.StatusTransition{}
+
+	var task *model.Task
+	for _, event := range events {
+		var previous model.TaskStatus
+		if task != nil {
+			previous = task.Status
+		}
+		task = event.Apply(task)
+
+		if event.Type == model.TaskHistoryPosition && event.Position != nil {
+			points = append(points, dtos.TrajectoryPoint{
+				Step:     event.Step,
+				X:        event.Position.X,
+				Y:        event.Position.Y,
+				HasCrate: event.Position.HasCrate,
+				At:       event.At,
+			})
+		}
+		if task != nil && task.Status != previous {
+			transitions = append(transitions, dtos.StatusTransition{
+				Status: mapToDtoStatus(task.Status),
+				Error:  task.Error,
+				At:     event.At,
+			})
+		}
+	}
+	if task == nil {
+		return nil, model.ErrTaskNotFound
+	}
+
+	return &dtos.TaskTrajectory{
+		TaskID:      task.TaskID,
+		RobotID:     task.RobotID,
+		Status:      mapToDtoStatus(task.Status),
+		Points:      points,
+		Transitions: transitions,
+	}, nil
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IRetrieveWarehouseService exposes read-only access to the warehouse floor:
+// its dimensions, the crate inventory and the contents of individual cells.
+type IRetrieveWarehouseService interface {
+	// RetrieveWarehouse returns the dimensions and coordinate range of the warehouse.
+	RetrieveWarehouse() (*dtos.WarehouseInfo, error)
+
+	// RetrieveCrates returns every crate currently on the floor.
+	RetrieveCrates() ([]*dtos.CrateInfo, error)
+
+	// RetrieveCell returns the crate and robots on the given cell.
+	//
+	// Error Returns:
+	//	 - ErrBoundary: the cell lies outside the warehouse.
+	RetrieveCell(x, y uint) (*dtos.CellInfo, error)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"log"
+	"sort"
+
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// RetrieveWarehouseServiceImpl is the default implementation of IRetrieveWarehouseService.
+// Layout comes from the warehouse map, crates from the crate inventory, robots from the robot registry.
+type RetrieveWarehouseServiceImpl struct {
+	robots          *manager.RobotRegistry
+	warehouseMap    *model.WarehouseMap
+	crateRepository dao.ICrateRepository
+}
+
+// NewRetrieveWarehouseService constructor
+func NewRetrieveWarehouseService(
+	robots *manager.RobotRegistry,
+	warehouseMap *model.WarehouseMap,
+	crateRepository dao.ICrateRepository) IRetrieveWarehouseService {
+	return &RetrieveWarehouseServiceImpl{
+		robots:          robots,
+		warehouseMap:    warehouseMap,
+		crateRepository: crateRepository,
+	}
+}
+
+// RetrieveWarehouse describes the configured warehouse grid and its obstacles.
+func (s *RetrieveWarehouseServiceImpl) RetrieveWarehouse() (*dtos.WarehouseInfo, error) {
+	grid := s.warehouseMap.Grid
+
+	robotHomes := make([]dtos.RobotHomeInfo, 0, len(s.warehouseMap.RobotHomes))
+	for _, home := range s.warehouseMap.RobotHomes {
+		robotHomes = append(robotHomes, dtos.RobotHomeInfo{RobotID: home.RobotID, X: home.X, Y: home.Y})
+	}
+
+	noGoZones := make([]dtos.NoGoZoneInfo, 0, len(s.warehouseMap.Zones))
+	for _, zone := range s.warehouseMap.Zones {
+		noGoZones = append(noGoZones, dtos.NoGoZoneInfo{
+			Name: zone.Name,
+			Min:  dtos.CellRef{X: zone.Min.X, Y: zone.Min.Y},
+			Max:  dtos.CellRef{X: zone.Max.X, Y: zone.Max.Y},
+		})
+	}
+
+	return &dtos.WarehouseInfo{
+		Width:         grid.Width,
+		Height:        grid.Height,
+		OriginX:       grid.OriginX,
+		OriginY:       grid.OriginY,
+		MaxX:          grid.MaxX(),
+		MaxY:          grid.MaxY(),
+		Walls:         toCellRefs(cellSet(s.warehouseMap.Walls)),
+		Shelves:       toCellRefs(cellSet(s.warehouseMap.Shelves)),
+		BlockedCells:  toCellRefs(cellSet(s.warehouseMap.Blocked)),
+		NoGoZones:     noGoZones,
+		CrateSlots:    toCellRefs(s.warehouseMap.CrateSlots),
+		ChargingDocks: toCellRefs(s.warehouseMap.ChargingDocks),
+		RobotHomes:    robotHomes,
+	}, nil
+}
+
+// cellSet flattens a set of cells into a slice.
+func cellSet(cells map[model.Cell]bool) []model.Cell {
+	flat := make([]model.Cell, 0, len(cells))
+	for cell := range cells {
+		flat = append(flat, cell)
+	}
+	return flat
+}
+
+// toCellRefs converts cells into DTOs ordered by (x, y) so responses are stable.
+func toCellRefs(cells []model.Cell) []dtos.CellRef {
+	refs := make([]dtos.CellRef, 0, len(cells))
+	for _, cell := range cells {
+		refs = append(refs, dtos.CellRef{X: cell.X, Y: cell.Y})
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].X != refs[j].X {
+			return refs[i].X < refs[j].X
+		}
+		return refs[i].Y < refs[j].Y
+	})
+	return refs
+}
+
+// RetrieveCrates lists the crate inventory.
+func (s *RetrieveWarehouseServiceImpl) RetrieveCrates() ([]*dtos.CrateInfo, error) {
+	crates, err := s.crateRepository.List()
+	if err != nil {
+		log.Printf("list crates: %v", err)
+		return nil, model.ErrInternal
+	}
+
+	crateInfos := make([]*dtos.CrateInfo, 0, len(crates))
+	for _, crate := range crates {
+		crateInfos = append(crateInfos, toCrateInfo(crate))
+	}
+
+	return crateInfos, nil
+}
+
+// RetrieveCell looks up the crate and robots on a single cell.
+func (s *RetrieveWarehouseServiceImpl) RetrieveCell(x, y uint) (*dtos.CellInfo, error) {
+	if !s.warehouseMap.Grid.Contains(int(x), int(y)) {
+		log.Printf("cell (%d,%d) is outside the warehouse", x, y)
+		return nil, model.ErrBoundary
+	}
+
+	crate, err := s.crateRepository.GetByCell(x, y)
+	if err != nil {
+		log.Printf("get crate at (%d,%d): %v", x, y, err)
+		return nil, model.ErrInternal
+	}
+
+	cellInfo := &dtos.CellInfo{
+		X:           x,
+		Y:           y,
+		Obstruction: s.warehouseMap.Obstruction(int(x), int(y)),
+		RobotIDs:    []string{},
+	}
+	if crate != nil {
+		cellInfo.Crate = toCrateInfo(crate)
+	}
+
+	for _, robot := range s.robots.List() {
+		state := robot.Robot.CurrentState()
+		if state.X == x && state.Y == y {
+			cellInfo.RobotIDs = append(cellInfo.RobotIDs, robot.ID)
+		}
+	}
+
+	return cellInfo, nil
+}
+
+// toCrateInfo converts a domain Crate into its DTO equivalent.
+func toCrateInfo(crate *model.Crate) *dtos.CrateInfo {
+	return &dtos.CrateInfo{
+		ID:  crate.ID,
+		SKU: crate.SKU,
+		X:   crate.X,
+		Y:   crate.Y,
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IRobotRegistryService takes robots in and out of service. Implementations are
+// expected to:
+//   - Keep robot IDs stable: a robot registers under the ID the SDK knows it by,
+//     and a decommissioned robot comes back under the same ID.
+//   - Refuse to decommission a robot that still has unfinished tasks.
+//   - Serialise with task creation, so no task is queued on a robot being decommissioned.
+type IRobotRegistryService interface {
+	// RegisterRobot puts a robot in service: one the warehouse SDK reports
+	// under the ID as its serial number (model.IdentifiedRobot), or a
+	// decommissioned one.
+	//
+	// Returns:
+	//   - RobotInfo snapshot of the robot.
+	//
+	// Error Returns:
+	//	 - ErrRobotIDInvalid: the ID is empty.
+	//	 - ErrRobotNotFound: neither the SDK nor the registry knows the ID.
+	//	 - ErrRobotAlreadyRegistered: the robot is already in service.
+	RegisterRobot(robotID string) (*dtos.RobotInfo, error)
+
+	// DecommissionRobot takes a robot out of service; it no longer appears in
+	// robot listings and cannot be given tasks.
+	//
+	// Error Returns:
+	//	 - ErrRobotNotFound: no robot is in service under the ID.
+	//	 - ErrRobotBusy: the robot still has PENDING tasks.
+	DecommissionRobot(robotID string) error
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"log"
+
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// RobotRegistryServiceImpl is the default implementation of IRobotRegistryService.
+// It works on the robot registry shared with CreateTaskServiceImpl and holds
+// its queue lock while changing it.
+type RobotRegistryServiceImpl struct {
+	createTaskService    *CreateTaskServiceImpl
+	retrieveRobotService IRetrieveRobotService
+	warehouse            model.Warehouse
+}
+
+// NewRobotRegistryService constructs a RobotRegistryServiceImpl on top of the
+// create task service; robot snapshots come from the retrieve robot service,
+// and robots registered for the first time are looked up in the warehouse SDK.
+func NewRobotRegistryService(createTaskService *CreateTaskServiceImpl,
+	retrieveRobotService IRetrieveRobotService, warehouse model.Warehouse) IRobotRegistryService {
+	return &RobotRegistryServiceImpl{
+		createTaskService:    createTaskService,
+		retrieveRobotService: retrieveRobotService,
+		warehouse:            warehouse,
+	}
+}
+
+// RegisterRobot puts the robot in service with the handle the SDK reports for
+// its serial, or its previous handle if the SDK does not list it, and parks it
+// where it stands in the reservation table.
+func (s *RobotRegistryServiceImpl) RegisterRobot(robotID string) (*dtos.RobotInfo, error) {
+	handle, _ := manager.FindSDKRobot(s.warehouse, robotID)
+
+	c := s.createTaskService
+	c.queueMu.Lock()
+	if err := c.robots.Register(robotID, handle); err != nil {
+		c.queueMu.Unlock()
+		log.Printf("register robot %q: %v", robotID, err)
+		return nil, err
+	}
+
+	robot, err := c.robots.Get(robotID)
+	if err == nil {
+		state := robot.CurrentState()
+		c.reservations.Park(robotID, model.Cell{X: state.X, Y: state.Y})
+	}
+	c.queueMu.Unlock()
+
+	return s.retrieveRobotService.RetrieveRobotById(robotID)
+}
+
+// DecommissionRobot takes an idle robot out of service. It stays parked in the
+// reservation table, since it still occupies its cell.
+func (s *RobotRegistryServiceImpl) DecommissionRobot(robotID string) error {
+	c := s.createTaskService
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+
+	if _, err := c.robots.Get(robotID); err != nil {
+		return err
+	}
+
+	tasks, err := c.repository.GetByRobotId(robotID)
+	if err != nil {
+		log.Printf("get tasks for robot %s: %v", robotID, err)
+		return model.ErrInternal
+	}
+	if pending := pendingTasks(tasks); len(pending) > 0 {
+		log.Printf("robot %s cannot be decommissioned with %d pending tasks", robotID, len(pending))
+		return model.ErrRobotBusy
+	}
+
+	return c.robots.Decommission(robotID)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// serialRobot is a stub robot that reports its own serial number.
+type serialRobot struct {
+	stubRobot
+	serial string
+}
+
+func (r *serialRobot) GetID() string {
+	return r.serial
+}
+
+type stubWarehouse []model.Robot
+
+func (w stubWarehouse) Robots() []model.Robot {
+	return w
+}
+
+func TestRobotRegistryServiceImpl_RegisterRobot_NewRobot(t *testing.T) {
+	first := &serialRobot{serial: "AX-1"}
+	warehouse := stubWarehouse{first}
+
+	registry := manager.NewRobotRegistry()
+	registry.Discover(warehouse)
+
+	repository := dao.NewInMemoryTaskRepository()
+	reservations := manager.NewReservationTable(constant.RobotStepDuration)
+	createTaskService := newTestCreateTaskService(registry, model.NewWarehouseMap(model.DefaultGrid()), repository, reservations)
+
+	// a robot comes online after discovery
+	second := &serialRobot{stubRobot: stubRobot{state: model.RobotState{X: 4, Y: 2}}, serial: "AX-2"}
+	service := NewRobotRegistryService(createTaskService, NewRetrieveRobotService(registry, repository),
+		append(warehouse, second))
+
+	info, err := service.RegisterRobot("AX-2")
+	if err != nil {
+		t.Fatalf("RegisterRobot() error = %v", err)
+	}
+	if info.ID != "AX-2" || info.Position.X != 4 || info.Position.Y != 2 {
+		t.Errorf("expected AX-2 at (4,2), got %+v", info)
+	}
+	if robot, err := registry.Get("AX-2"); err != nil || robot != second {
+		t.Errorf("expected AX-2 to resolve to the SDK's handle, got %v (%v)", robot, err)
+	}
+	if err := reservations.Check("AX-1", []model.Cell{{X: 4, Y: 2}}); err == nil {
+		t.Error("expected AX-2 to be parked at (4,2)")
+	}
+
+	if _, err := service.RegisterRobot("AX-2"); !errors.Is(err, model.ErrRobotAlreadyRegistered) {
+		t.Errorf("expected a second registration to be refused, got %v", err)
+	}
+	if _, err := service.RegisterRobot("AX-3"); !errors.Is(err, model.ErrRobotNotFound) {
+		t.Errorf("expected a robot the SDK does not report to be unknown, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"warehouse-robots/backend/api/model"
+)
+
+// ITaskDrainService winds the tasks down when the service shuts down.
+// Implementations are expected to:
+//   - Refuse new tasks with ErrShuttingDown once StopAcceptingTasks is called.
+//   - Either wait for the running tasks to end or cancel them through the SDK.
+//   - Stop monitoring whatever is still running, leaving it PENDING so a
+//     persistent store can recover it on the next start.
+type ITaskDrainService interface {
+	// StopAcceptingTasks makes task creation and batch moves fail with
+	// ErrShuttingDown from now on.
+	StopAcceptingTasks()
+
+	// DrainTasks applies the policy (constant.ShutdownTasksWait or
+	// constant.ShutdownTasksCancel) to the PENDING tasks, waiting for them at
+	// most until ctx is done, and reports what became of them.
+	//
+	// Error Returns:
+	//   - the repository error if the PENDING tasks cannot be listed.
+	DrainTasks(ctx context.Context, policy string) (*model.TaskDrainReport, error)
+}
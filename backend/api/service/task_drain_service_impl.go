@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+)
+
+// cancelledOnShutdownReason is recorded on tasks cancelled by the drain.
+const cancelledOnShutdownReason = "cancelled on shutdown"
+
+// drainPollInterval is how often the repository is checked for tasks still running.
+const drainPollInterval = 100 * time.Millisecond
+
+// monitorStopTimeout bounds the wait for the monitors to exit once the drain
+// deadline may already have passed; they exit as soon as they are stopped.
+const monitorStopTimeout = time.Second
+
+// TaskDrainServiceImpl is the default implementation of ITaskDrainService.
+// It shares the robot registry, repository, task monitor and queue lock of
+// CreateTaskServiceImpl.
+type TaskDrainServiceImpl struct {
+	createTaskService *CreateTaskServiceImpl
+}
+
+// NewTaskDrainService constructs a TaskDrainServiceImpl on top of the create task service.
+func NewTaskDrainService(createTaskService *CreateTaskServiceImpl) ITaskDrainService {
+	return &TaskDrainServiceImpl{
+		createTaskService: createTaskService,
+	}
+}
+
+// StopAcceptingTasks refuses new tasks. Taking the queue lock waits for a task
+// that is being created to be enqueued, so none slips in after the drain starts.
+func (s *TaskDrainServiceImpl) StopAcceptingTasks() {
+	c := s.createTaskService
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	c.draining.Store(true)
+}
+
+// DrainTasks waits for or cancels the PENDING tasks and then stops the monitors
+// of any still running.
+func (s *TaskDrainServiceImpl) DrainTasks(ctx context.Context, policy string) (*model.TaskDrainReport, error) {
+	c := s.createTaskService
+	s.StopAcceptingTasks()
+
+	pending, err := c.repository.GetByStatus(model.TaskStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	report := &model.TaskDrainReport{}
+
+	if policy == constant.ShutdownTasksCancel {
+		report.Cancelled = s.cancelAll(pending)
+	}
+
+	running, err := s.waitForTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Running = running
+	report.Finished = len(pending) - len(report.Cancelled) - len(running)
+
+	// whatever is left keeps running on the robots; nobody watches it from here on
+	stopCtx, cancel := context.WithTimeout(context.Background(), monitorStopTimeout)
+	defer cancel()
+	if err := c.taskMonitor.Shutdown(stopCtx); err != nil {
+		log.Printf("task drain: stop monitors: %v", err)
+	}
+	return report, nil
+}
+
+// cancelAll cancels the tasks through the SDK, the last queued first so a robot
+// does not start a task that is about to be cancelled. It returns the IDs of
+// the tasks cancelled; the others are left running.
+func (s *TaskDrainServiceImpl) cancelAll(pending []*model.Task) []string {
+	c := s.createTaskService
+
+	var cancelled []string
+	for _, task := range slices.Backward(pending) {
+		robot, err := c.robots.Get(task.RobotID)
+		if err != nil {
+			log.Printf("task drain: resolve robot %q of task %s: %v", task.RobotID, task.TaskID, err)
+			continue
+		}
+		if err := robot.CancelTask(task.SDKID()); err != nil {
+			log.Printf("task drain: sdk cancel of task %s: %v", task.TaskID, err)
+			continue
+		}
+		if err := c.taskMonitor.CancelTask(task.TaskID, cancelledOnShutdownReason); err != nil {
+			log.Printf("task drain: update task %s: %v", task.TaskID, err)
+			continue
+		}
+		cancelled = append(cancelled, task.TaskID)
+	}
+	return cancelled
+}
+
+// waitForTasks polls the repository until no task is PENDING or ctx is done,
+// and returns the tasks still PENDING.
+func (s *TaskDrainServiceImpl) waitForTasks(ctx context.Context) ([]*model.Task, error) {
+	c := s.createTaskService
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		running, err := c.repository.GetByStatus(model.TaskStatusPending)
+		if err != nil || len(running) == 0 {
+			return running, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return running, nil
+		case <-ticker.C:
+		}
+	}
+}
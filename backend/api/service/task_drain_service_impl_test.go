@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+func newTaskDrainFixture(t *testing.T) (*CreateTaskServiceImpl, ITaskDrainService, *stubRobot, dao.ITaskRepository) {
+	robot := &stubRobot{}
+	repository := dao.NewInMemoryTaskRepository()
+	createTaskService := newTestCreateTaskService(stubRegistry(robot),
+		model.NewWarehouseMap(model.DefaultGrid()), repository, manager.NewReservationTable(constant.RobotStepDuration))
+
+	now := time.Now()
+	for i, id := range []string{"a", "b"} {
+		task := &model.Task{TaskID: id, RobotID: "0", Commands: "N", Status: model.TaskStatusPending,
+			CreatedAt: now.Add(time.Duration(i) * time.Second)}
+		if err := repository.Create(task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	return createTaskService, NewTaskDrainService(createTaskService), robot, repository
+}
+
+func TestTaskDrainServiceImpl_StopAcceptingTasks(t *testing.T) {
+	createTaskService, drainService, robot, _ := newTaskDrainFixture(t)
+	drainService.StopAcceptingTasks()
+
+	if _, err := createTaskService.CreateTask("0", dtos.CreateTaskRequest{Commands: "E"}); !errors.Is(err, model.ErrShuttingDown) {
+		t.Errorf("CreateTask() error = %v, want ErrShuttingDown", err)
+	}
+	if len(robot.enqueued) != 0 {
+		t.Errorf("expected nothing enqueued, got %v", robot.enqueued)
+	}
+}
+
+func TestTaskDrainServiceImpl_DrainTasks_Wait(t *testing.T) {
+	_, drainService, robot, repository := newTaskDrainFixture(t)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = repository.UpdateStatus("a", model.TaskStatusCompleted, "")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	report, err := drainService.DrainTasks(ctx, constant.ShutdownTasksWait)
+	if err != nil {
+		t.Fatalf("DrainTasks() error = %v", err)
+	}
+
+	if report.Finished != 1 || len(report.Cancelled) != 0 || len(report.Running) != 1 || report.Running[0].TaskID != "b" {
+		t.Errorf("expected a finished and b left running, got %+v", report)
+	}
+	if len(robot.cancelled) != 0 {
+		t.Errorf("expected nothing cancelled, got %v", robot.cancelled)
+	}
+	if b, _ := repository.GetById("b"); b.Status != model.TaskStatusPending {
+		t.Errorf("expected b to stay PENDING for recovery, got %s", b.Status)
+	}
+}
+
+func TestTaskDrainServiceImpl_DrainTasks_Cancel(t *testing.T) {
+	_, drainService, robot, repository := newTaskDrainFixture(t)
+
+	report, err := drainService.DrainTasks(context.Background(), constant.ShutdownTasksCancel)
+	if err != nil {
+		t.Fatalf("DrainTasks() error = %v", err)
+	}
+
+	// the task queued last is cancelled first, so the robot never starts it
+	if !slices.Equal(robot.cancelled, []string{"b", "a"}) || !slices.Equal(report.Cancelled, []string{"b", "a"}) {
+		t.Errorf("expected b then a cancelled, got %v (report %+v)", robot.cancelled, report)
+	}
+	if report.Finished != 0 || len(report.Running) != 0 {
+		t.Errorf("expected nothing finished or left running, got %+v", report)
+	}
+	for _, id := range []string{"a", "b"} {
+		if task, _ := repository.GetById(id); task.Status != model.TaskStatusCancelled || task.Error != cancelledOnShutdownReason {
+			t.Errorf("expected %s to be cancelled on shutdown, got %+v", id, task)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// TaskEventFeed is an open subscription to the events of one task.
+type TaskEventFeed struct {
+	// Backlog holds the events published before subscribing, after the
+	// requested event ID.
+	Backlog []dtos.TaskEvent
+	// Events delivers the events still to come. It is closed after the end
+	// event, or early if the subscriber falls too far behind, in which case the
+	// client should resume from the last event ID it received.
+	Events <-chan dtos.TaskEvent
+	// Close releases the subscription; it must be called once the feed is no
+	// longer read.
+	Close func()
+}
+
+// ITaskEventService streams what the task monitor observes about a task.
+// Implementations are expected to:
+//   - Number the events of a task from 1, so clients can resume after the last one seen.
+//   - Finish every stream with an end event carrying the terminal status.
+//   - Still end the stream of a task that finished before its events were recorded.
+type ITaskEventService interface {
+	// SubscribeTaskEvents opens a feed of the task's events.
+	//
+	// Parameters:
+	//   - taskID: the task to follow.
+	//   - lastEventID: the last event the client has seen; 0 for all events.
+	//
+	// Returns:
+	//   - TaskEventFeed with the missed events and a channel for later ones.
+	//
+	// Error Returns:
+	//   - ErrTaskNotFound: task id not found in db.
+	SubscribeTaskEvents(taskID string, lastEventID int64) (*TaskEventFeed, error)
+}
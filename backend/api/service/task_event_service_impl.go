@@ -0,0 +1,109 @@
+package service
+
+import (
+	"sync"
+
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// TaskEventServiceImpl is the default implementation of ITaskEventService.
+// It reads from the task event stream the task monitors publish to, and falls
+// back to the task record for tasks that ended without any recorded events.
+type TaskEventServiceImpl struct {
+	repository dao.ITaskRepository
+	events     *manager.TaskEventStream
+}
+
+// NewTaskEventService constructor
+func NewTaskEventService(repository dao.ITaskRepository, events *manager.TaskEventStream) ITaskEventService {
+	return &TaskEventServiceImpl{
+		repository: repository,
+		events:     events,
+	}
+}
+
+// SubscribeTaskEvents checks the task exists, then subscribes to its events.
+func (s *TaskEventServiceImpl) SubscribeTaskEvents(taskID string, lastEventID int64) (*TaskEventFeed, error) {
+	task, err := s.repository.GetById(taskID)
+	if err != nil {
+		return nil, model.ErrTaskNotFound
+	}
+
+	sub := s.events.Subscribe(taskID, lastEventID)
+
+	feed := &TaskEventFeed{Backlog: make([]dtos.TaskEvent, 0, len(sub.Backlog))}
+	for _, event := range sub.Backlog {
+		feed.Backlog = append(feed.Backlog, toTaskEventDto(event))
+	}
+
+	// the task ended without its end event being recorded, e.g. before anyone
+	// was publishing; its record still tells how it ended
+	if !sub.Ended && isTerminal(task.Status) {
+		sub.Close()
+		feed.Backlog = append(feed.Backlog, dtos.TaskEvent{
+			TaskID:  task.TaskID,
+			RobotID: task.RobotID,
+			Type:    string(model.TaskEventEnd),
+			Status:  mapToDtoStatus(task.Status),
+			Error:   task.Error,
+			At:      task.UpdatedAt,
+		})
+	}
+
+	events := make(chan dtos.TaskEvent)
+	done := make(chan struct{})
+	var once sync.Once
+	feed.Events = events
+	feed.Close = func() {
+		once.Do(func() {
+			close(done)
+			sub.Close()
+		})
+	}
+
+	go func() {
+		defer close(events)
+		for event := range sub.Events {
+			select {
+			case events <- toTaskEventDto(event):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return feed, nil
+}
+
+// isTerminal reports whether a task with the status will not change any more.
+func isTerminal(status model.TaskStatus) bool {
+	switch status {
+	case model.TaskStatusCompleted, model.TaskStatusFailed, model.TaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// toTaskEventDto converts a task event into its DTO form.
+func toTaskEventDto(event model.TaskEvent) dtos.TaskEvent {
+	dto := dtos.TaskEvent{
+		ID:      event.ID,
+		TaskID:  event.TaskID,
+		RobotID: event.RobotID,
+		Type:    string(event.Type),
+		Status:  mapToDtoStatus(event.Status),
+		Error:   event.Error,
+		At:      event.At,
+	}
+	if event.Position != nil {
+		dto.Position = &dtos.RobotState{
+			X:        event.Position.X,
+			Y:        event.Position.Y,
+			HasCrate: event.Position.HasCrate,
+		}
+	}
+	return dto
+}
@@ -0,0 +1,17 @@
+package service
+
+// ITaskQueueService keeps a robot's queued tasks consistent once an earlier task
+// stops short. Every queued task was validated from the projected end of the
+// task ahead of it; when a task FAILS or is CANCELLED that projection no longer
+// holds, so implementations are expected to:
+//   - Re-validate each task queued behind it, in order, from the robot's actual position,
+//     and reserve its path again.
+//   - Keep tasks whose commands are still valid.
+//   - Re-plan goto tasks whose route is no longer valid and enqueue them again.
+//   - Cancel the rest, recording why in the task error.
+type ITaskQueueService interface {
+	// RevalidateQueueAfter re-validates the tasks queued behind the given
+	// FAILED or CANCELLED task. Problems are logged, not returned, as this runs
+	// in the background after the task has already stopped.
+	RevalidateQueueAfter(taskID string)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+
+	"warehouse-robots/backend/api/model"
+)
+
+// TaskQueueServiceImpl is the default implementation of ITaskQueueService.
+// It shares the validation pipeline, task monitor and queue lock of
+// CreateTaskServiceImpl, and registers itself for the monitor's failures.
+type TaskQueueServiceImpl struct {
+	createTaskService *CreateTaskServiceImpl
+}
+
+// NewTaskQueueService constructs a TaskQueueServiceImpl on top of the create task
+// service and re-validates the queue whenever one of its tasks fails.
+func NewTaskQueueService(createTaskService *CreateTaskServiceImpl) ITaskQueueService {
+	s := &TaskQueueServiceImpl{
+		createTaskService: createTaskService,
+	}
+	createTaskService.taskMonitor.OnFailure(s.RevalidateQueueAfter)
+	return s
+}
+
+// RevalidateQueueAfter walks the tasks queued behind the stopped task and keeps,
+// re-plans or cancels each one. Tasks queued ahead of it are left alone.
+func (s *TaskQueueServiceImpl) RevalidateQueueAfter(taskID string) {
+	c := s.createTaskService
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+
+	stopped, err := c.repository.GetById(taskID)
+	if err != nil {
+		log.Printf("revalidate queue: get task %s: %v", taskID, err)
+		return
+	}
+
+	robot, err := c.robots.Get(stopped.RobotID)
+	if err != nil {
+		log.Printf("revalidate queue: resolve robot %q: %v", stopped.RobotID, err)
+		return
+	}
+
+	tasks, err := c.repository.GetByRobotId(stopped.RobotID)
+	if err != nil {
+		log.Printf("revalidate queue: get tasks for robot %s: %v", stopped.RobotID, err)
+		return
+	}
+
+	var ahead, behind []*model.Task
+	for _, task := range pendingTasks(tasks) {
+		if task.CreatedAt.Before(stopped.CreatedAt) {
+			ahead = append(ahead, task)
+		} else {
+			behind = append(behind, task)
+		}
+	}
+	if len(behind) == 0 {
+		return
+	}
+
+	start := stopPosition(stopped, robot)
+
+	// the paths behind were reserved from the projected start, so reserve them
+	// again as they are re-validated
+	for _, task := range behind {
+		c.reservations.Release(task.TaskID, nil)
+	}
+	c.reservations.Release(stopped.TaskID, &model.Cell{X: start.X, Y: start.Y})
+
+	moved := make(map[[2]int]bool)
+	for _, task := range ahead {
+		replayCrateOperations(task.PlannedStart, task.Commands, moved)
+	}
+	if len(ahead) > 0 {
+		start = projectedEnd(ahead[len(ahead)-1])
+	}
+
+	// once a task is enqueued again it runs after everything still queued in
+	// the SDK, so every task behind it has to be enqueued again to keep the order
+	requeue := false
+
+	for _, task := range behind {
+		commands, replanned, err := s.revalidate(task, start, moved)
+		if err == nil {
+			err = c.reservations.Check(stopped.RobotID, trajectoryCells(start, commands))
+		}
+		if err != nil {
+			reason := fmt.Sprintf("cancelled: task %s %s and this task is no longer valid from (%d,%d): %s",
+				stopped.TaskID, describeStop(stopped.Status), start.X, start.Y, describeError(err))
+			s.cancel(robot, task, reason)
+			continue
+		}
+
+		switch {
+		case replanned || requeue:
+			if err := s.enqueueAgain(robot, task, commands, start); err != nil {
+				log.Printf("revalidate queue: enqueue task %s again: %v", task.TaskID, err)
+			}
+			requeue = true
+		case task.PlannedStart == nil || *task.PlannedStart != *start:
+			task.PlannedStart = start
+			if err := c.repository.Update(task); err != nil {
+				log.Printf("revalidate queue: update task %s: %v", task.TaskID, err)
+			}
+		}
+
+		c.reservations.Reserve(stopped.RobotID, task.TaskID, trajectoryCells(start, commands))
+
+		log.Printf("revalidate queue: task %s kept after %s %s (replanned=%t)", task.TaskID, stopped.TaskID, describeStop(stopped.Status), replanned)
+		start = projectedEnd(task)
+	}
+}
+
+// revalidate checks a queued task from its new start. A goto task whose route
+// is no longer valid, or no longer ends on its target, is re-planned.
+// moved is only updated if the task is kept.
+func (s *TaskQueueServiceImpl) revalidate(task *model.Task, start *model.Position, moved map[[2]int]bool) (string, bool, error) {
+	c := s.createTaskService
+
+	trial := maps.Clone(moved)
+	err := c.validatePlan(start, task.Commands, trial)
+	if err == nil && (task.Target == nil || reaches(start, task.Commands, *task.Target)) {
+		maps.Copy(moved, trial)
+		return task.Commands, false, nil
+	}
+	if task.Target == nil {
+		return "", false, err
+	}
+
+	commands, routeErr := c.planRoute(start, *task.Target)
+	if routeErr != nil {
+		return "", false, routeErr
+	}
+
+	trial = maps.Clone(moved)
+	if err := c.validatePlan(start, commands, trial); err != nil {
+		return "", false, err
+	}
+	maps.Copy(moved, trial)
+	return commands, true, nil
+}
+
+// enqueueAgain withdraws the task from the SDK and enqueues its (possibly
+// re-planned) commands at the back of the SDK queue, keeping the task ID.
+func (s *TaskQueueServiceImpl) enqueueAgain(robot model.Robot, task *model.Task, commands string, start *model.Position) error {
+	c := s.createTaskService
+
+	if err := robot.CancelTask(task.SDKID()); errors.Is(err, model.ErrSDKTaskFinished) {
+		// it ran meanwhile; its monitor records how it ended
+		return err
+	} else if err != nil {
+		log.Printf("revalidate queue: sdk cancel of task %s: %v", task.TaskID, err)
+	}
+	c.taskMonitor.StopMonitoring(task.TaskID)
+
+	sdkTaskID, posCh, errCh := robot.EnqueueTask(commands)
+
+	task.SDKTaskID = sdkTaskID
+	task.Commands = commands
+	task.PlannedStart = start
+	task.CurrentPosition = nil
+	err := c.repository.Update(task)
+
+	c.taskMonitor.StartMonitoring(task.TaskID, posCh, errCh)
+	return err
+}
+
+// cancel withdraws a task that can no longer run and records the reason.
+func (s *TaskQueueServiceImpl) cancel(robot model.Robot, task *model.Task, reason string) {
+	c := s.createTaskService
+
+	if err := robot.CancelTask(task.SDKID()); errors.Is(err, model.ErrSDKTaskFinished) {
+		log.Printf("revalidate queue: task %s finished before it could be cancelled", task.TaskID)
+		return
+	} else if err != nil {
+		log.Printf("revalidate queue: sdk cancel of task %s: %v", task.TaskID, err)
+	}
+
+	log.Printf("revalidate queue: task %s %s", task.TaskID, reason)
+	if err := c.taskMonitor.CancelTask(task.TaskID, reason); err != nil {
+		log.Printf("revalidate queue: update task %s: %v", task.TaskID, err)
+	}
+}
+
+// reaches reports whether the commands take the robot from start to the target.
+func reaches(start *model.Position, commands string, target model.Cell) bool {
+	trajectory, _ := simulateTrajectory(start, commands)
+	end := trajectory[len(trajectory)-1]
+	return end.X == target.X && end.Y == target.Y
+}
+
+// stopPosition is where the robot really is after the stopped task: its last
+// reported position, or where it would have started if it never ran.
+func stopPosition(stopped *model.Task, robot model.Robot) *model.Position {
+	switch {
+	case stopped.CurrentPosition != nil:
+		position := *stopped.CurrentPosition
+		return &position
+	case stopped.PlannedStart != nil:
+		position := *stopped.PlannedStart
+		return &position
+	default:
+		state := robot.CurrentState()
+		return &model.Position{X: state.X, Y: state.Y, HasCrate: state.HasCrate}
+	}
+}
+
+func describeStop(status model.TaskStatus) string {
+	if status == model.TaskStatusCancelled {
+		return "was cancelled"
+	}
+	return "failed"
+}
+
+// describeError renders a validation error with its details, if any.
+func describeError(err error) string {
+	var detailed interface{ Details() string }
+	if errors.As(err, &detailed) {
+		return fmt.Sprintf("%v (%s)", err, detailed.Details())
+	}
+	return err.Error()
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// stubRobot records what the services ask of the SDK without running anything.
+type stubRobot struct {
+	prefix    string // keeps task IDs of several stub robots apart
+	state     model.RobotState
+	enqueued  []string
+	cancelled []string
+}
+
+func (r *stubRobot) EnqueueTask(commands string) (string, chan model.RobotState, chan error) {
+	r.enqueued = append(r.enqueued, commands)
+	return fmt.Sprintf("%sstub_%d", r.prefix, len(r.enqueued)), make(chan model.RobotState, 10), make(chan error, 1)
+}
+
+func (r *stubRobot) CancelTask(taskID string) error {
+	r.cancelled = append(r.cancelled, taskID)
+	return nil
+}
+
+func (r *stubRobot) CurrentState() model.RobotState {
+	return r.state
+}
+
+// stubRegistry registers the robots under IDs "0", "1", ...
+func stubRegistry(robots ...model.Robot) *manager.RobotRegistry {
+	registry := manager.NewRobotRegistry()
+	for i, robot := range robots {
+		_ = registry.Register(strconv.Itoa(i), robot)
+	}
+	return registry
+}
+
+// newTestCreateTaskService wires a create task service as the container does,
+// with its own task monitor and no event bus.
+func newTestCreateTaskService(robots *manager.RobotRegistry, warehouseMap *model.WarehouseMap,
+	repository dao.ITaskRepository, reservations *manager.ReservationTable) *CreateTaskServiceImpl {
+	crateRepository := dao.NewInMemoryCrateRepository()
+	taskMonitor := manager.NewTaskMonitor(repository, crateRepository, reservations, nil)
+	return NewCreateTaskService(robots, warehouseMap, repository, crateRepository, reservations, taskMonitor, nil)
+}
+
+func TestTaskQueueServiceImpl_RevalidateQueueAfter(t *testing.T) {
+	robot := &stubRobot{}
+	repository := dao.NewInMemoryTaskRepository()
+	createTaskService := newTestCreateTaskService(stubRegistry(robot),
+		model.NewWarehouseMap(model.DefaultGrid()), repository, manager.NewReservationTable(constant.RobotStepDuration))
+	queueService := NewTaskQueueService(createTaskService)
+
+	now := time.Now()
+	tasks := []*model.Task{
+		// failed before moving, so the robot is still at (0,0) instead of (0,2)
+		{TaskID: "a", Commands: "NN", Status: model.TaskStatusFailed,
+			PlannedStart: &model.Position{X: 0, Y: 0}, CurrentPosition: &model.Position{X: 0, Y: 0}},
+		// S from (0,0) leaves the warehouse
+		{TaskID: "b", Commands: "SE", Status: model.TaskStatusPending,
+			PlannedStart: &model.Position{X: 0, Y: 2}},
+		// still in bounds from (0,0) but would miss its target
+		{TaskID: "c", Commands: "EN", Target: &model.Cell{X: 2, Y: 2}, Status: model.TaskStatusPending,
+			PlannedStart: &model.Position{X: 1, Y: 1}},
+		// still valid, but has to be enqueued again behind c
+		{TaskID: "d", Commands: "W", Status: model.TaskStatusPending,
+			PlannedStart: &model.Position{X: 2, Y: 2}},
+	}
+	for i, task := range tasks {
+		task.RobotID = "0"
+		task.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		if err := repository.Create(task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	queueService.RevalidateQueueAfter("a")
+
+	b, _ := repository.GetById("b")
+	if b.Status != model.TaskStatusCancelled || !strings.Contains(b.Error, "BOUNDARY_ERROR") {
+		t.Errorf("expected b to be cancelled with a boundary reason, got %s %q", b.Status, b.Error)
+	}
+
+	c, _ := repository.GetById("c")
+	if c.Status != model.TaskStatusPending || c.Commands != "NNEE" || c.SDKTaskID != "stub_1" {
+		t.Errorf("expected c to be re-planned as NNEE, got %s %q sdk=%q", c.Status, c.Commands, c.SDKTaskID)
+	}
+
+	d, _ := repository.GetById("d")
+	if d.Status != model.TaskStatusPending || d.Commands != "W" || d.SDKTaskID != "stub_2" {
+		t.Errorf("expected d to be enqueued again unchanged, got %s %q sdk=%q", d.Status, d.Commands, d.SDKTaskID)
+	}
+
+	if strings.Join(robot.cancelled, ",") != "b,c,d" {
+		t.Errorf("expected SDK cancels for b,c,d, got %v", robot.cancelled)
+	}
+	if strings.Join(robot.enqueued, ",") != "NNEE,W" {
+		t.Errorf("expected SDK enqueues NNEE,W, got %v", robot.enqueued)
+	}
+}
+
+func TestTaskQueueServiceImpl_RevalidateQueueAfter_KeepsValidTasks(t *testing.T) {
+	robot := &stubRobot{}
+	repository := dao.NewInMemoryTaskRepository()
+	createTaskService := newTestCreateTaskService(stubRegistry(robot),
+		model.NewWarehouseMap(model.DefaultGrid()), repository, manager.NewReservationTable(constant.RobotStepDuration))
+	queueService := NewTaskQueueService(createTaskService)
+
+	now := time.Now()
+	_ = repository.Create(&model.Task{TaskID: "a", RobotID: "0", Commands: "N", Status: model.TaskStatusCancelled,
+		PlannedStart: &model.Position{X: 3, Y: 3}, CreatedAt: now})
+	_ = repository.Create(&model.Task{TaskID: "b", RobotID: "0", Commands: "EE", Status: model.TaskStatusPending,
+		PlannedStart: &model.Position{X: 3, Y: 4}, CreatedAt: now.Add(time.Second)})
+
+	queueService.RevalidateQueueAfter("a")
+
+	b, _ := repository.GetById("b")
+	if b.Status != model.TaskStatusPending || b.SDKTaskID != "" {
+		t.Errorf("expected b to be kept as is, got %s sdk=%q", b.Status, b.SDKTaskID)
+	}
+	if b.PlannedStart == nil || b.PlannedStart.Y != 3 {
+		t.Errorf("expected b to start where a never left, (3,3), got %+v", b.PlannedStart)
+	}
+	if len(robot.cancelled) != 0 || len(robot.enqueued) != 0 {
+		t.Errorf("expected no SDK calls, got cancels %v enqueues %v", robot.cancelled, robot.enqueued)
+	}
+}
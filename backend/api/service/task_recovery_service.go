@@ -0,0 +1,16 @@
+package service
+
+// ITaskRecoveryService reconciles the tasks a persistent store still holds as
+// PENDING when the service starts; no monitor is watching them any more, and
+// they would block their robot's queue forever. Implementations are expected to:
+//   - Ask the robot whether it still has each task, in queue order, and monitor
+//     the ones it does again, reserving their paths.
+//   - Mark the others FAILED as orphaned, recording where the robot really is,
+//     along with every task queued behind them.
+type ITaskRecoveryService interface {
+	// RecoverTasks reconciles every PENDING task and returns how many were
+	// re-attached and how many were orphaned. It only fails if the PENDING
+	// tasks cannot be listed; problems with single tasks are logged, so one bad
+	// record does not stop the service starting.
+	RecoverTasks() (reattached, orphaned int, err error)
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"log"
+
+	"warehouse-robots/backend/api/model"
+)
+
+// orphanedReason is recorded on tasks that could not be re-attached on startup.
+const orphanedReason = "orphaned after restart"
+
+// TaskRecoveryServiceImpl is the default implementation of ITaskRecoveryService.
+// It shares the robot registry, repository, path reservations, task monitor and
+// queue lock of CreateTaskServiceImpl, so re-attached tasks are monitored like
+// the ones created afterwards.
+type TaskRecoveryServiceImpl struct {
+	createTaskService *CreateTaskServiceImpl
+}
+
+// NewTaskRecoveryService constructs a TaskRecoveryServiceImpl on top of the create task service.
+func NewTaskRecoveryService(createTaskService *CreateTaskServiceImpl) ITaskRecoveryService {
+	return &TaskRecoveryServiceImpl{
+		createTaskService: createTaskService,
+	}
+}
+
+// RecoverTasks walks the PENDING tasks robot by robot, oldest first. A robot
+// runs its tasks in order, so once one of them is orphaned the tasks queued
+// behind it are orphaned too: they were planned from where it would have ended.
+func (s *TaskRecoveryServiceImpl) RecoverTasks() (reattached, orphaned int, err error) {
+	c := s.createTaskService
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+
+	pending, err := c.repository.GetByStatus(model.TaskStatusPending)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	lost := make(map[string]bool) // robots with an orphaned task
+	for _, task := range pending {
+		if !lost[task.RobotID] && s.reattach(task) {
+			reattached++
+			continue
+		}
+		lost[task.RobotID] = true
+		s.orphan(task)
+		orphaned++
+	}
+
+	if reattached+orphaned > 0 {
+		log.Printf("task recovery: %d task(s) re-attached, %d orphaned", reattached, orphaned)
+	}
+	return reattached, orphaned, nil
+}
+
+// reattach monitors the task again if its robot still has it in its queue.
+func (s *TaskRecoveryServiceImpl) reattach(task *model.Task) bool {
+	c := s.createTaskService
+
+	robot, err := c.robots.Get(task.RobotID)
+	if err != nil {
+		return false
+	}
+	watcher, ok := robot.(model.TaskWatcher)
+	if !ok {
+		return false
+	}
+	posCh, errCh, ok := watcher.WatchTask(task.SDKID())
+	if !ok {
+		return false
+	}
+
+	if task.PlannedStart != nil {
+		c.reservations.Reserve(task.RobotID, task.TaskID, trajectoryCells(task.PlannedStart, task.Commands))
+	}
+	c.taskMonitor.StartMonitoring(task.TaskID, posCh, errCh)
+
+	log.Printf("task recovery: task %s re-attached to robot %s", task.TaskID, task.RobotID)
+	return true
+}
+
+// orphan marks the task FAILED, first recording the robot's real position so
+// the next task is planned from there. The position is unknown if the robot
+// has left the fleet.
+func (s *TaskRecoveryServiceImpl) orphan(task *model.Task) {
+	c := s.createTaskService
+
+	if robot, err := c.robots.Get(task.RobotID); err == nil {
+		state := robot.CurrentState()
+		position := &model.Position{X: state.X, Y: state.Y, HasCrate: state.HasCrate}
+		if err := c.repository.UpdatePosition(task.TaskID, position, model.TaskStatusPending); err != nil {
+			log.Printf("task recovery: update position of task %s: %v", task.TaskID, err)
+		}
+	}
+	if err := c.repository.UpdateStatus(task.TaskID, model.TaskStatusFailed, orphanedReason); err != nil {
+		log.Printf("task recovery: update task %s: %v", task.TaskID, err)
+	}
+
+	log.Printf("task recovery: task %s of robot %s %s", task.TaskID, task.RobotID, orphanedReason)
+}
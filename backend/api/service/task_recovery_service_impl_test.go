@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/model"
+)
+
+// watchingRobot is a stub robot that still has the tasks in live.
+type watchingRobot struct {
+	stubRobot
+	live map[string]chan model.RobotState
+}
+
+func (r *watchingRobot) WatchTask(taskID string) (chan model.RobotState, chan error, bool) {
+	posCh, ok := r.live[taskID]
+	return posCh, make(chan error, 1), ok
+}
+
+func TestTaskRecoveryServiceImpl_RecoverTasks(t *testing.T) {
+	running := make(chan model.RobotState, 10)
+	watcher := &watchingRobot{
+		stubRobot: stubRobot{state: model.RobotState{X: 0, Y: 1}},
+		// c is still queued, but behind b, which the robot lost
+		live: map[string]chan model.RobotState{"a": running, "c": make(chan model.RobotState)},
+	}
+	plain := &stubRobot{state: model.RobotState{X: 3, Y: 2, HasCrate: true}}
+
+	repository := dao.NewInMemoryTaskRepository()
+	createTaskService := newTestCreateTaskService(stubRegistry(watcher, plain),
+		model.NewWarehouseMap(model.DefaultGrid()), repository, manager.NewReservationTable(constant.RobotStepDuration))
+	recoveryService := NewTaskRecoveryService(createTaskService)
+
+	now := time.Now()
+	tasks := []*model.Task{
+		{TaskID: "a", RobotID: "0", Commands: "NN", PlannedStart: &model.Position{X: 0, Y: 0}},
+		{TaskID: "b", RobotID: "0", Commands: "E", PlannedStart: &model.Position{X: 0, Y: 2}},
+		{TaskID: "c", RobotID: "0", Commands: "S", PlannedStart: &model.Position{X: 1, Y: 2}},
+		// the SDK robot cannot say whether it still has the task
+		{TaskID: "d", RobotID: "1", Commands: "W", PlannedStart: &model.Position{X: 3, Y: 2}},
+		// the robot left the fleet
+		{TaskID: "e", RobotID: "9", Commands: "N", PlannedStart: &model.Position{X: 5, Y: 5}},
+	}
+	for i, task := range tasks {
+		task.Status = model.TaskStatusPending
+		task.CreatedAt = now.Add(time.Duration(i) * time.Second)
+		if err := repository.Create(task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	done := &model.Task{TaskID: "z", RobotID: "0", Commands: "N", Status: model.TaskStatusCompleted, CreatedAt: now}
+	_ = repository.Create(done)
+
+	reattached, orphaned, err := recoveryService.RecoverTasks()
+	if err != nil || reattached != 1 || orphaned != 4 {
+		t.Fatalf("RecoverTasks() = %d, %d, %v, want 1 re-attached and 4 orphaned", reattached, orphaned, err)
+	}
+
+	for _, id := range []string{"b", "c", "d", "e"} {
+		task, _ := repository.GetById(id)
+		if task.Status != model.TaskStatusFailed || task.Error != orphanedReason {
+			t.Errorf("expected task %s to be orphaned, got %+v", id, task)
+		}
+	}
+	if c, _ := repository.GetById("c"); c.CurrentPosition == nil || *c.CurrentPosition != (model.Position{X: 0, Y: 1}) {
+		t.Errorf("expected task c to record robot 0 at (0,1), got %+v", c.CurrentPosition)
+	}
+	if d, _ := repository.GetById("d"); d.CurrentPosition == nil || *d.CurrentPosition != (model.Position{X: 3, Y: 2, HasCrate: true}) {
+		t.Errorf("expected task d to record robot 1 at (3,2) with its crate, got %+v", d.CurrentPosition)
+	}
+	if e, _ := repository.GetById("e"); e.CurrentPosition != nil {
+		t.Errorf("expected no position for a robot that left the fleet, got %+v", e.CurrentPosition)
+	}
+
+	// a is monitored again and finishes as usual
+	running <- model.RobotState{X: 0, Y: 1}
+	running <- model.RobotState{X: 0, Y: 2}
+	close(running)
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		a, _ := repository.GetById("a")
+		if a.Status == model.TaskStatusCompleted {
+			if a.CurrentPosition == nil || a.CurrentPosition.Y != 2 {
+				t.Errorf("expected task a to end at (0,2), got %+v", a.CurrentPosition)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the re-attached task to complete, got %+v", a)
+		}
+	}
+}
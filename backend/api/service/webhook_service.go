@@ -0,0 +1,33 @@
+package service
+
+import (
+	"warehouse-robots/backend/api/dtos"
+)
+
+// IWebhookService manages the webhooks notified when tasks end.
+// Implementations are expected to:
+//   - Accept only absolute http(s) URLs and known event names.
+//   - Generate a signing secret when the caller does not provide one.
+//   - Keep the delivery log of a webhook after it is unregistered.
+type IWebhookService interface {
+	// RegisterWebhook registers a callback URL.
+	//
+	// Returns:
+	//   - WebhookInfo including the signing secret.
+	//
+	// Error Returns:
+	//   - ErrValidation: the URL is not an absolute http(s) URL, or an event name is unknown.
+	RegisterWebhook(req dtos.RegisterWebhookRequest) (*dtos.WebhookInfo, error)
+
+	// DeleteWebhook unregisters a webhook; deliveries in progress still finish.
+	//
+	// Error Returns:
+	//   - ErrWebhookNotFound: no webhook with the ID.
+	DeleteWebhook(webhookID string) error
+
+	// ListDeliveries returns every delivery attempt made to a webhook, oldest first.
+	//
+	// Error Returns:
+	//   - ErrWebhookNotFound: no webhook was ever registered with the ID.
+	ListDeliveries(webhookID string) ([]dtos.WebhookDelivery, error)
+}
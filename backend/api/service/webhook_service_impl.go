@@ -0,0 +1,128 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"warehouse-robots/backend/api/dao"
+	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/model"
+)
+
+// WebhookServiceImpl is the default implementation of IWebhookService.
+// Deliveries themselves are made by the manager.WebhookDispatcher, which
+// reads the webhooks from the same repository.
+type WebhookServiceImpl struct {
+	repository dao.IWebhookRepository
+	sequence   atomic.Int64
+}
+
+// NewWebhookService constructor
+func NewWebhookService(repository dao.IWebhookRepository) IWebhookService {
+	return &WebhookServiceImpl{
+		repository: repository,
+	}
+}
+
+// RegisterWebhook validates and stores the webhook.
+func (s *WebhookServiceImpl) RegisterWebhook(req dtos.RegisterWebhookRequest) (*dtos.WebhookInfo, error) {
+	target, err := url.Parse(req.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL, got %q", model.ErrValidation, req.URL)
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		switch event {
+		case model.WebhookEventTaskCompleted, model.WebhookEventTaskFailed, model.WebhookEventTaskCancelled:
+			events = append(events, event)
+		default:
+			return nil, fmt.Errorf("%w: unknown event %q", model.ErrValidation, event)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = generateSecret(); err != nil {
+			log.Printf("generate webhook secret: %v", err)
+			return nil, model.ErrInternal
+		}
+	}
+
+	webhook := &model.Webhook{
+		URL:       target.String(),
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: time.Now(),
+	}
+	// a persistent repository may hold the IDs of an earlier run; skip those
+	for {
+		webhook.ID = fmt.Sprintf("webhook_%d", s.sequence.Add(1))
+		if err = s.repository.Create(webhook); !errors.Is(err, dao.ErrWebhookExists) {
+			break
+		}
+	}
+	if err != nil {
+		log.Printf("create webhook: %v", err)
+		return nil, model.ErrInternal
+	}
+
+	return &dtos.WebhookInfo{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Secret:    webhook.Secret,
+		CreatedAt: webhook.CreatedAt,
+	}, nil
+}
+
+// DeleteWebhook removes the webhook.
+func (s *WebhookServiceImpl) DeleteWebhook(webhookID string) error {
+	return s.repository.Delete(webhookID)
+}
+
+// ListDeliveries maps the recorded attempts to DTOs. A webhook that has been
+// unregistered is still found through its attempts.
+func (s *WebhookServiceImpl) ListDeliveries(webhookID string) ([]dtos.WebhookDelivery, error) {
+	attempts, err := s.repository.ListAttempts(webhookID)
+	if err != nil {
+		log.Printf("list webhook attempts: %v", err)
+		return nil, model.ErrInternal
+	}
+	if len(attempts) == 0 {
+		if _, err := s.repository.GetById(webhookID); err != nil {
+			return nil, err
+		}
+	}
+
+	deliveries := make([]dtos.WebhookDelivery, 0, len(attempts))
+	for _, attempt := range attempts {
+		deliveries = append(deliveries, dtos.WebhookDelivery{
+			DeliveryID: attempt.DeliveryID,
+			TaskID:     attempt.TaskID,
+			Event:      attempt.Event,
+			Attempt:    attempt.Attempt,
+			StatusCode: attempt.StatusCode,
+			Succeeded:  attempt.Succeeded,
+			Error:      attempt.Error,
+			DurationMs: attempt.Duration.Milliseconds(),
+			At:         attempt.At,
+		})
+	}
+	return deliveries, nil
+}
+
+// generateSecret returns 32 random bytes, hex encoded.
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -1,6 +1,12 @@
 package binder
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"warehouse-robots/backend/api/constant"
 	controller "warehouse-robots/backend/api/controller"
 	"warehouse-robots/backend/api/dao"
 	"warehouse-robots/backend/api/manager"
@@ -15,25 +21,65 @@ import (
 type Container struct {
 	Config *config.Config
 
+	// Warehouse Layer
+	WarehouseMap *model.WarehouseMap
+
 	// SDK Layer
 	RobotSDKService model.Warehouse
 	SDKFactory      *sdkService.RobotSDKFactory
 
 	// Repository Layer
-	TaskRepository dao.ITaskRepository
+	TaskRepository    dao.ITaskRepository
+	CrateRepository   dao.ICrateRepository
+	WebhookRepository dao.IWebhookRepository
 
 	// Manager Layer
-	TaskMonitor *manager.TaskMonitor
+	RobotRegistry    *manager.RobotRegistry
+	ReservationTable *manager.ReservationTable
+	TaskMonitor      *manager.TaskMonitor
+	EventBus         *manager.EventBus
+	TaskEvents       *manager.TaskEventStream
+	OutboxRelay      *manager.OutboxRelay
+	Webhooks         *manager.WebhookDispatcher
 
 	// Service Layer
-	CreateTaskService   service.ICreateTaskService
-	RetrieveTaskService service.IRetrieveTaskService
-	CancelTaskService   service.ICancelTaskService
+	CreateTaskService             service.ICreateTaskService
+	PreviewTaskService            service.IPreviewTaskService
+	BatchMoveService              service.IBatchMoveService
+	TaskQueueService              service.ITaskQueueService
+	TaskRecoveryService           service.ITaskRecoveryService
+	TaskDrainService              service.ITaskDrainService
+	RetrieveTaskService           service.IRetrieveTaskService
+	RetrieveTaskTrajectoryService service.IRetrieveTaskTrajectoryService
+	RetrieveTaskMonitorsService   service.IRetrieveTaskMonitorsService
+	CancelTaskService             service.ICancelTaskService
+	TaskEventService              service.ITaskEventService
+	FleetTelemetryService         service.IFleetTelemetryService
+	RetrieveRobotService          service.IRetrieveRobotService
+	RobotRegistryService          service.IRobotRegistryService
+	RetrieveWarehouseService      service.IRetrieveWarehouseService
+	WebhookService                service.IWebhookService
 
 	// Controller Layer
-	CreateTaskController   controller.ICreateTaskController
-	RetrieveTaskController controller.IRetrieveTaskController
-	CancelTaskController   controller.ICancelTaskController
+	CreateTaskController                controller.ICreateTaskController
+	PreviewTaskController               controller.IPreviewTaskController
+	BatchMoveController                 controller.IBatchMoveController
+	RetrieveTaskController              controller.IRetrieveTaskController
+	RetrieveTaskTrajectoryController    controller.IRetrieveTaskTrajectoryController
+	RetrieveTaskMonitorsController      controller.IRetrieveTaskMonitorsController
+	CancelTaskController                controller.ICancelTaskController
+	TaskEventsController                controller.ITaskEventsController
+	FleetTelemetryController            controller.IFleetTelemetryController
+	RetrieveRobotsController            controller.IRetrieveRobotsController
+	RetrieveRobotController             controller.IRetrieveRobotController
+	RegisterRobotController             controller.IRegisterRobotController
+	DecommissionRobotController         controller.IDecommissionRobotController
+	RetrieveWarehouseController         controller.IRetrieveWarehouseController
+	RetrieveCratesController            controller.IRetrieveCratesController
+	RetrieveCellController              controller.IRetrieveCellController
+	RegisterWebhookController           controller.IRegisterWebhookController
+	DeleteWebhookController             controller.IDeleteWebhookController
+	RetrieveWebhookDeliveriesController controller.IRetrieveWebhookDeliveriesController
 }
 
 // NewContainer creates and wires all dependencies
@@ -42,6 +88,7 @@ func NewContainer(cfg *config.Config) *Container {
 		Config: cfg,
 	}
 
+	container.bindWarehouseLayer()
 	container.bindSDKLayer()
 	container.bindDataLayer()
 	container.bindManagerLayer()
@@ -51,6 +98,11 @@ func NewContainer(cfg *config.Config) *Container {
 	return container
 }
 
+// bindWarehouseLayer sets up the static warehouse map loaded by config
+func (c *Container) bindWarehouseLayer() {
+	c.WarehouseMap = c.Config.Warehouse.Layout()
+}
+
 // bindSDKLayer sets up SDK services
 func (c *Container) bindSDKLayer() {
 	c.SDKFactory = sdkService.NewRobotSDKFactory(c.Config)
@@ -60,27 +112,150 @@ func (c *Container) bindSDKLayer() {
 // bindDataLayer sets up data access layer
 func (c *Container) bindDataLayer() {
 	// Create the shared repository instance
-	c.TaskRepository = dao.NewInMemoryTaskRepository()
+	switch c.Config.Storage.TaskStore {
+	case constant.TaskStoreSQLite:
+		repository, err := dao.NewSQLiteTaskRepository(c.Config.Storage.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to open the task store: %v", err)
+		}
+		c.TaskRepository = repository
+		c.WebhookRepository = dao.NewSQLiteWebhookRepository(repository)
+	default:
+		c.TaskRepository = dao.NewInMemoryTaskRepository()
+		c.WebhookRepository = dao.NewInMemoryWebhookRepository()
+	}
+
+	// Seed the crate inventory from configuration
+	c.CrateRepository = dao.NewInMemoryCrateRepository()
+	for _, seed := range c.Config.Warehouse.Crates {
+		crate := &model.Crate{ID: seed.ID, SKU: seed.SKU, X: seed.X, Y: seed.Y}
+		if err := c.CrateRepository.Place(crate); err != nil {
+			log.Fatalf("Failed to seed crate inventory: %v", err)
+		}
+	}
 }
 
 // bindManagerLayer sets up manager layer
 func (c *Container) bindManagerLayer() {
-	// TaskMonitor needs repository
-	c.TaskMonitor = manager.NewTaskMonitor(c.TaskRepository)
+	// Every SDK robot is registered under its stable ID
+	c.RobotRegistry = manager.NewRobotRegistry()
+	c.RobotRegistry.Discover(c.RobotSDKService)
+
+	// Every robot starts out parked where the SDK reports it
+	c.ReservationTable = manager.NewReservationTable(constant.RobotStepDuration)
+	for _, robot := range c.RobotRegistry.List() {
+		state := robot.Robot.CurrentState()
+		c.ReservationTable.Park(robot.ID, model.Cell{X: state.X, Y: state.Y})
+	}
+
+	// Domain events are published on the bus; anything that reacts to tasks
+	// (streaming, notifications, metrics, audit) subscribes here
+	c.EventBus = manager.NewEventBus()
+
+	// Task events are streamed to clients as they are published
+	c.TaskEvents = manager.NewTaskEventStream()
+	c.TaskEvents.Follow(c.EventBus)
+
+	// Task lifecycle events are recorded in the repository's outbox with each
+	// update and relayed from there, so none is lost between the two
+	outboxConfig := c.Config.Outbox.WithDefaults()
+	c.OutboxRelay = manager.NewOutboxRelay(c.TaskRepository, outboxConfig.PollInterval, int(outboxConfig.BatchSize))
+	for _, sink := range outboxConfig.Sinks {
+		switch sink {
+		case constant.OutboxSinkBus:
+			c.OutboxRelay.AddSink(sink, manager.BusSink(c.EventBus))
+		case constant.OutboxSinkLog:
+			c.OutboxRelay.AddSink(sink, manager.LogSink())
+		}
+	}
+	c.OutboxRelay.Start()
+
+	// A single TaskMonitor watches every task; all services share it
+	c.TaskMonitor = manager.NewTaskMonitor(c.TaskRepository, c.CrateRepository, c.ReservationTable, c.EventBus)
+
+	// Webhooks are notified of the tasks ending
+	webhookConfig := c.Config.Webhook.WithDefaults()
+	c.Webhooks = manager.NewWebhookDispatcher(c.WebhookRepository, c.TaskRepository,
+		int(webhookConfig.MaxAttempts), webhookConfig.InitialBackoff, webhookConfig.Timeout)
+	c.Webhooks.Start(c.EventBus)
 }
 
 // bindServiceLayer sets up service layer
 func (c *Container) bindServiceLayer() {
-	c.CreateTaskService = service.NewCreateTaskService(c.RobotSDKService,
-		c.TaskRepository)
+	createTaskService := service.NewCreateTaskService(c.RobotRegistry,
+		c.WarehouseMap, c.TaskRepository, c.CrateRepository, c.ReservationTable, c.TaskMonitor, c.EventBus)
+	c.CreateTaskService = createTaskService
+	c.PreviewTaskService = service.NewPreviewTaskService(createTaskService)
+	c.BatchMoveService = service.NewBatchMoveService(createTaskService)
+	c.TaskQueueService = service.NewTaskQueueService(createTaskService)
+	c.TaskDrainService = service.NewTaskDrainService(createTaskService)
+
+	// Tasks a previous run left PENDING are re-attached to their robots or
+	// failed before any request is served
+	c.TaskRecoveryService = service.NewTaskRecoveryService(createTaskService)
+	if _, _, err := c.TaskRecoveryService.RecoverTasks(); err != nil {
+		log.Fatalf("Failed to recover unfinished tasks: %v", err)
+	}
+
 	c.RetrieveTaskService = service.NewRetrieveTaskService(c.TaskRepository)
-	c.CancelTaskService = service.NewCancelTaskService(c.RobotSDKService,
+	c.RetrieveTaskTrajectoryService = service.NewRetrieveTaskTrajectoryService(c.TaskRepository)
+	c.RetrieveTaskMonitorsService = service.NewRetrieveTaskMonitorsService(c.TaskMonitor, c.TaskRepository)
+	c.CancelTaskService = service.NewCancelTaskService(c.RobotRegistry,
+		c.TaskRepository, c.TaskMonitor, c.TaskQueueService)
+	c.TaskEventService = service.NewTaskEventService(c.TaskRepository, c.TaskEvents)
+	c.FleetTelemetryService = service.NewFleetTelemetryService(c.TaskEvents)
+	c.RetrieveRobotService = service.NewRetrieveRobotService(c.RobotRegistry,
 		c.TaskRepository)
+	c.RobotRegistryService = service.NewRobotRegistryService(createTaskService,
+		c.RetrieveRobotService, c.RobotSDKService)
+	c.RetrieveWarehouseService = service.NewRetrieveWarehouseService(c.RobotRegistry,
+		c.WarehouseMap, c.CrateRepository)
+	c.WebhookService = service.NewWebhookService(c.WebhookRepository)
 }
 
 // bindControllerLayer sets up controller layer
 func (c *Container) bindControllerLayer() {
 	c.CreateTaskController = controller.NewCreateTaskController(c.CreateTaskService)
+	c.PreviewTaskController = controller.NewPreviewTaskController(c.PreviewTaskService)
+	c.BatchMoveController = controller.NewBatchMoveController(c.BatchMoveService)
 	c.RetrieveTaskController = controller.NewRetrieveTaskController(c.RetrieveTaskService)
+	c.RetrieveTaskTrajectoryController = controller.NewRetrieveTaskTrajectoryController(c.RetrieveTaskTrajectoryService)
+	c.RetrieveTaskMonitorsController = controller.NewRetrieveTaskMonitorsController(c.RetrieveTaskMonitorsService)
 	c.CancelTaskController = controller.NewCancelTaskController(c.CancelTaskService)
+	c.TaskEventsController = controller.NewTaskEventsController(c.TaskEventService)
+	c.FleetTelemetryController = controller.NewFleetTelemetryController(c.FleetTelemetryService,
+		c.Config.CORS.AllowedOrigins)
+	c.RetrieveRobotsController = controller.NewRetrieveRobotsController(c.RetrieveRobotService)
+	c.RetrieveRobotController = controller.NewRetrieveRobotController(c.RetrieveRobotService)
+	c.RegisterRobotController = controller.NewRegisterRobotController(c.RobotRegistryService)
+	c.DecommissionRobotController = controller.NewDecommissionRobotController(c.RobotRegistryService)
+	c.RetrieveWarehouseController = controller.NewRetrieveWarehouseController(c.RetrieveWarehouseService)
+	c.RetrieveCratesController = controller.NewRetrieveCratesController(c.RetrieveWarehouseService)
+	c.RetrieveCellController = controller.NewRetrieveCellController(c.RetrieveWarehouseService)
+	c.RegisterWebhookController = controller.NewRegisterWebhookController(c.WebhookService)
+	c.DeleteWebhookController = controller.NewDeleteWebhookController(c.WebhookService)
+	c.RetrieveWebhookDeliveriesController = controller.NewRetrieveWebhookDeliveriesController(c.WebhookService)
+}
+
+// Shutdown stops the background workers in dependency order, relaying the
+// outbox entries still pending to the bus and letting its subscribers handle
+// them, and then closes the task store if it holds resources. It keeps going
+// when a step fails or the context ends and returns every error.
+func (c *Container) Shutdown(ctx context.Context) error {
+	var errs []error
+	step := func(name string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	step("task monitor", c.TaskMonitor.Shutdown(ctx))
+	step("outbox relay", c.OutboxRelay.Shutdown(ctx))
+	step("event bus", c.EventBus.Close(ctx))
+	step("webhooks", c.Webhooks.Shutdown(ctx))
+	if closer, ok := c.TaskRepository.(io.Closer); ok {
+		step("task store", closer.Close())
+	}
+
+	return errors.Join(errs...)
 }
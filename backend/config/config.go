@@ -1,8 +1,18 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/model"
+	"warehouse-robots/backend/infra/warehouseMap"
 
 	"github.com/joho/godotenv"
 )
@@ -15,12 +25,27 @@ type Config struct {
 	// Robot SDK Configuration
 	Robot RobotConfig
 
+	// Warehouse Configuration
+	Warehouse WarehouseConfig
+
 	// Logging Configuration
 	Log LogConfig
 
 	// CORS Configuration
 	CORS CORSConfig
 
+	// Webhook Configuration
+	Webhook WebhookConfig
+
+	// Outbox Configuration
+	Outbox OutboxConfig
+
+	// Storage Configuration
+	Storage StorageConfig
+
+	// Shutdown Configuration
+	Shutdown ShutdownConfig
+
 	// Environment
 	Environment string
 }
@@ -32,9 +57,43 @@ type ServerConfig struct {
 	AdminPort string
 }
 
-// RobotConfig holds facades SDK-related configuration
+// RobotConfig holds facades SDK-related configuration.
+// Robots lists the robots the mock SDK builds, read from MOCK_ROBOTS or the
+// ScenarioFile; without any the mock has a single robot "robot-1".
 type RobotConfig struct {
-	EnableMock bool
+	EnableMock   bool
+	ScenarioFile string
+	Robots       []RobotSeed
+}
+
+// RobotSeed is a mock robot placed on the floor at startup
+type RobotSeed struct {
+	ID       string `json:"id"`
+	X        uint   `json:"x"`
+	Y        uint   `json:"y"`
+	HasCrate bool   `json:"has_crate"`
+}
+
+// WarehouseConfig holds warehouse floor configuration.
+// Width and Height count cells; the origin is the bottom-left (south-west) cell.
+// MapFile optionally points at a warehouse map file (ASCII grid or JSON); when
+// set, the parsed Map drives the grid, obstacles, crate slots and robot homes.
+type WarehouseConfig struct {
+	Width   uint
+	Height  uint
+	OriginX uint
+	OriginY uint
+	MapFile string
+	Map     *model.WarehouseMap
+	Crates  []CrateSeed
+}
+
+// CrateSeed is a crate placed on the floor at startup
+type CrateSeed struct {
+	X   uint
+	Y   uint
+	ID  string
+	SKU string
 }
 
 // LogConfig holds logging-related configuration
@@ -49,13 +108,117 @@ type CORSConfig struct {
 	AllowedHeaders string
 }
 
-// Load loads configuration from environment variables
+// WebhookConfig holds outbound webhook delivery configuration.
+// A payload is attempted up to MaxAttempts times, waiting InitialBackoff after
+// the first failure and twice as long after each further one; each attempt
+// gives up after Timeout.
+type WebhookConfig struct {
+	MaxAttempts    uint
+	InitialBackoff time.Duration
+	Timeout        time.Duration
+}
+
+// OutboxConfig holds the outbox relay configuration. The relay looks for
+// pending outbox entries every PollInterval, reads up to BatchSize at a time
+// and publishes them to each of the Sinks. Sinks must include
+// constant.OutboxSinkBus, the only way task end events reach the event bus.
+type OutboxConfig struct {
+	Sinks        []string
+	PollInterval time.Duration
+	BatchSize    uint
+}
+
+// StorageConfig selects where tasks are stored: TaskStore is
+// constant.TaskStoreMemory or constant.TaskStoreSQLite, the latter in the
+// database file at SQLitePath.
+type StorageConfig struct {
+	TaskStore  string
+	SQLitePath string
+}
+
+// ShutdownConfig holds the graceful shutdown configuration. On SIGINT or
+// SIGTERM new tasks are refused, running tasks are waited for up to TaskTimeout
+// or cancelled, as TaskPolicy says (see constant.ShutdownTasksWait), and
+// in-flight HTTP requests get HTTPTimeout to finish.
+type ShutdownConfig struct {
+	HTTPTimeout time.Duration
+	TaskTimeout time.Duration
+	TaskPolicy  string
+}
+
+// Load loads configuration from environment variables and the .env file,
+// and exits if the resulting configuration is invalid.
 func Load() *Config {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: .env file not found, using system environment variables")
 	}
 
+	// A map file places its own crates, so the demo crates only apply without one
+	mapFile := getEnv("WAREHOUSE_MAP_FILE", "")
+	defaultCrates := "2,2;5,5;7,3"
+	if mapFile != "" {
+		defaultCrates = ""
+	}
+
+	crates, err := parseCrateSeeds(getEnv("WAREHOUSE_CRATES", defaultCrates))
+	if err != nil {
+		log.Fatalf("Invalid WAREHOUSE_CRATES: %v", err)
+	}
+
+	robots, err := parseRobotSeeds(getEnv("MOCK_ROBOTS", ""))
+	if err != nil {
+		log.Fatalf("Invalid MOCK_ROBOTS: %v", err)
+	}
+
+	width, err := getEnvUint("WAREHOUSE_WIDTH", constant.WarehouseSizeX)
+	if err != nil {
+		log.Fatalf("Invalid WAREHOUSE_WIDTH: %v", err)
+	}
+	height, err := getEnvUint("WAREHOUSE_HEIGHT", constant.WarehouseSizeY)
+	if err != nil {
+		log.Fatalf("Invalid WAREHOUSE_HEIGHT: %v", err)
+	}
+	originX, err := getEnvUint("WAREHOUSE_ORIGIN_X", constant.MinCoordinateX)
+	if err != nil {
+		log.Fatalf("Invalid WAREHOUSE_ORIGIN_X: %v", err)
+	}
+	originY, err := getEnvUint("WAREHOUSE_ORIGIN_Y", constant.MinCoordinateY)
+	if err != nil {
+		log.Fatalf("Invalid WAREHOUSE_ORIGIN_Y: %v", err)
+	}
+
+	webhookAttempts, err := getEnvUint("WEBHOOK_MAX_ATTEMPTS", constant.WebhookMaxAttempts)
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_MAX_ATTEMPTS: %v", err)
+	}
+	webhookBackoff, err := getEnvDuration("WEBHOOK_INITIAL_BACKOFF", constant.WebhookInitialBackoff)
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_INITIAL_BACKOFF: %v", err)
+	}
+	webhookTimeout, err := getEnvDuration("WEBHOOK_TIMEOUT", constant.WebhookTimeout)
+	if err != nil {
+		log.Fatalf("Invalid WEBHOOK_TIMEOUT: %v", err)
+	}
+
+	outboxInterval, err := getEnvDuration("OUTBOX_POLL_INTERVAL", constant.OutboxPollInterval)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_POLL_INTERVAL: %v", err)
+	}
+	outboxBatch, err := getEnvUint("OUTBOX_BATCH_SIZE", constant.OutboxBatchSize)
+	if err != nil {
+		log.Fatalf("Invalid OUTBOX_BATCH_SIZE: %v", err)
+	}
+
+	shutdownHTTPTimeout, err := getEnvDuration("SHUTDOWN_HTTP_TIMEOUT", constant.ShutdownHTTPTimeout)
+	if err != nil {
+		log.Fatalf("Invalid SHUTDOWN_HTTP_TIMEOUT: %v", err)
+	}
+	shutdownTaskTimeout, err := getEnvDuration("SHUTDOWN_TASK_TIMEOUT", constant.ShutdownTaskTimeout)
+	if err != nil {
+		log.Fatalf("Invalid SHUTDOWN_TASK_TIMEOUT: %v", err)
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:      getEnv("PORT", "8080"),
@@ -63,7 +226,17 @@ func Load() *Config {
 			Host:      getEnv("HOST", "localhost"),
 		},
 		Robot: RobotConfig{
-			EnableMock: getEnv("ENABLE_MOCK_ROBOT_SDK", "false") == "true",
+			EnableMock:   getEnv("ENABLE_MOCK_ROBOT_SDK", "false") == "true",
+			ScenarioFile: getEnv("MOCK_SCENARIO_FILE", ""),
+			Robots:       robots,
+		},
+		Warehouse: WarehouseConfig{
+			Width:   width,
+			Height:  height,
+			OriginX: originX,
+			OriginY: originY,
+			MapFile: mapFile,
+			Crates:  crates,
 		},
 		Log: LogConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
@@ -73,12 +246,247 @@ func Load() *Config {
 			AllowedMethods: getEnv("CORS_ALLOWED_METHODS", "GET,POST,DELETE,OPTIONS"),
 			AllowedHeaders: getEnv("CORS_ALLOWED_HEADERS", "Content-Type"),
 		},
+		Webhook: WebhookConfig{
+			MaxAttempts:    webhookAttempts,
+			InitialBackoff: webhookBackoff,
+			Timeout:        webhookTimeout,
+		},
+		Outbox: OutboxConfig{
+			Sinks:        splitList(getEnv("OUTBOX_SINKS", constant.OutboxSinks)),
+			PollInterval: outboxInterval,
+			BatchSize:    outboxBatch,
+		},
+		Storage: StorageConfig{
+			TaskStore:  getEnv("TASK_STORE", constant.TaskStoreMemory),
+			SQLitePath: getEnv("SQLITE_PATH", constant.SQLitePath),
+		},
+		Shutdown: ShutdownConfig{
+			HTTPTimeout: shutdownHTTPTimeout,
+			TaskTimeout: shutdownTaskTimeout,
+			TaskPolicy:  getEnv("SHUTDOWN_TASK_POLICY", constant.ShutdownTaskPolicy),
+		},
 		Environment: getEnv("ENV", "development"),
 	}
 
+	if mapFile != "" {
+		if err := config.Warehouse.loadMap(); err != nil {
+			log.Fatalf("Invalid WAREHOUSE_MAP_FILE: %v", err)
+		}
+	}
+
+	if config.Robot.ScenarioFile != "" {
+		if err := config.Robot.loadScenario(); err != nil {
+			log.Fatalf("Invalid MOCK_SCENARIO_FILE: %v", err)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
 	return config
 }
 
+// loadMap parses MapFile and lets it override the grid settings. Every crate
+// slot on the map gets a crate unless WAREHOUSE_CRATES already names one there.
+func (w *WarehouseConfig) loadMap() error {
+	loaded, err := warehouseMap.LoadFile(w.MapFile, w.Grid())
+	if err != nil {
+		return err
+	}
+
+	w.Map = loaded
+	w.Width, w.Height = loaded.Grid.Width, loaded.Grid.Height
+	w.OriginX, w.OriginY = loaded.Grid.OriginX, loaded.Grid.OriginY
+
+	seeded := make(map[model.Cell]bool, len(w.Crates))
+	for _, crate := range w.Crates {
+		seeded[model.Cell{X: crate.X, Y: crate.Y}] = true
+	}
+	for _, slot := range loaded.CrateSlots {
+		if !seeded[slot] {
+			w.Crates = append(w.Crates, CrateSeed{X: slot.X, Y: slot.Y})
+		}
+	}
+
+	return nil
+}
+
+// scenario is the mock scenario file format, e.g.
+//
+//	{
+//	  "robots": [
+//	    {"id": "robot-1", "x": 0, "y": 0, "has_crate": true},
+//	    {"id": "robot-2", "x": 9, "y": 0}
+//	  ]
+//	}
+type scenario struct {
+	Robots []RobotSeed `json:"robots"`
+}
+
+// loadScenario reads ScenarioFile; its robots are added after any from MOCK_ROBOTS.
+func (r *RobotConfig) loadScenario() error {
+	data, err := os.ReadFile(r.ScenarioFile)
+	if err != nil {
+		return fmt.Errorf("read scenario file: %w", err)
+	}
+
+	var loaded scenario
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&loaded); err != nil {
+		return fmt.Errorf("scenario file %s: %w", r.ScenarioFile, err)
+	}
+
+	r.Robots = append(r.Robots, loaded.Robots...)
+	return nil
+}
+
+// Validate checks the configuration for values the app cannot run with
+func (c *Config) Validate() error {
+	if c.Warehouse.Width == 0 || c.Warehouse.Height == 0 {
+		return fmt.Errorf("warehouse must be at least 1x1, got %dx%d",
+			c.Warehouse.Width, c.Warehouse.Height)
+	}
+
+	layout := c.Warehouse.Layout()
+	grid := layout.Grid
+	for _, crate := range c.Warehouse.Crates {
+		if !grid.Contains(int(crate.X), int(crate.Y)) {
+			return fmt.Errorf("crate at (%d,%d) is outside the warehouse (%d,%d)-(%d,%d)",
+				crate.X, crate.Y, grid.OriginX, grid.OriginY, grid.MaxX(), grid.MaxY())
+		}
+		if obstruction := layout.Obstruction(int(crate.X), int(crate.Y)); obstruction != "" {
+			return fmt.Errorf("crate at (%d,%d) is on a %s", crate.X, crate.Y, obstruction)
+		}
+	}
+
+	// two robots can never share an ID or a cell
+	ids := make(map[string]bool, len(c.Robot.Robots))
+	cells := make(map[model.Cell]string, len(c.Robot.Robots))
+	for _, robot := range c.Robot.Robots {
+		if robot.ID == "" {
+			return fmt.Errorf("robot at (%d,%d) has no id", robot.X, robot.Y)
+		}
+		if ids[robot.ID] {
+			return fmt.Errorf("robot %s is configured more than once", robot.ID)
+		}
+		ids[robot.ID] = true
+
+		if !grid.Contains(int(robot.X), int(robot.Y)) {
+			return fmt.Errorf("robot %s at (%d,%d) is outside the warehouse (%d,%d)-(%d,%d)",
+				robot.ID, robot.X, robot.Y, grid.OriginX, grid.OriginY, grid.MaxX(), grid.MaxY())
+		}
+		if obstruction := layout.Obstruction(int(robot.X), int(robot.Y)); obstruction != "" {
+			return fmt.Errorf("robot %s at (%d,%d) is on a %s", robot.ID, robot.X, robot.Y, obstruction)
+		}
+		cell := model.Cell{X: robot.X, Y: robot.Y}
+		if other, taken := cells[cell]; taken {
+			return fmt.Errorf("robots %s and %s both start at (%d,%d)", other, robot.ID, robot.X, robot.Y)
+		}
+		cells[cell] = robot.ID
+	}
+
+	switch c.Storage.TaskStore {
+	case "", constant.TaskStoreMemory, constant.TaskStoreSQLite:
+	default:
+		return fmt.Errorf("unknown task store %q, expected %s or %s",
+			c.Storage.TaskStore, constant.TaskStoreMemory, constant.TaskStoreSQLite)
+	}
+
+	switch c.Shutdown.TaskPolicy {
+	case "", constant.ShutdownTasksWait, constant.ShutdownTasksCancel:
+	default:
+		return fmt.Errorf("unknown shutdown task policy %q, expected %s or %s",
+			c.Shutdown.TaskPolicy, constant.ShutdownTasksWait, constant.ShutdownTasksCancel)
+	}
+
+	for _, sink := range c.Outbox.Sinks {
+		if sink != constant.OutboxSinkBus && sink != constant.OutboxSinkLog {
+			return fmt.Errorf("unknown outbox sink %q, expected %s or %s",
+				sink, constant.OutboxSinkBus, constant.OutboxSinkLog)
+		}
+	}
+	// task end events only reach the webhooks and event streams through the bus
+	if len(c.Outbox.Sinks) > 0 && !slices.Contains(c.Outbox.Sinks, constant.OutboxSinkBus) {
+		return fmt.Errorf("outbox sinks %q must include %s", strings.Join(c.Outbox.Sinks, ","), constant.OutboxSinkBus)
+	}
+
+	return nil
+}
+
+// Layout returns the warehouse map, or an empty map over Grid when no map
+// file was loaded.
+func (w WarehouseConfig) Layout() *model.WarehouseMap {
+	if w.Map != nil {
+		return w.Map
+	}
+	return model.NewWarehouseMap(w.Grid())
+}
+
+// Grid returns the configured warehouse floor.
+// Configs built without Load (e.g. in tests) have no size and get the default floor.
+func (w WarehouseConfig) Grid() model.Grid {
+	if w.Map != nil {
+		return w.Map.Grid
+	}
+	if w.Width == 0 || w.Height == 0 {
+		return model.DefaultGrid()
+	}
+
+	return model.Grid{
+		OriginX: w.OriginX,
+		OriginY: w.OriginY,
+		Width:   w.Width,
+		Height:  w.Height,
+	}
+}
+
+// WithDefaults fills in the defaults for settings left at zero, e.g. in configs
+// built without Load.
+func (w WebhookConfig) WithDefaults() WebhookConfig {
+	if w.MaxAttempts == 0 {
+		w.MaxAttempts = constant.WebhookMaxAttempts
+	}
+	if w.InitialBackoff == 0 {
+		w.InitialBackoff = constant.WebhookInitialBackoff
+	}
+	if w.Timeout == 0 {
+		w.Timeout = constant.WebhookTimeout
+	}
+	return w
+}
+
+// WithDefaults fills in the defaults for settings left at zero, e.g. in configs
+// built without Load.
+func (o OutboxConfig) WithDefaults() OutboxConfig {
+	if len(o.Sinks) == 0 {
+		o.Sinks = splitList(constant.OutboxSinks)
+	}
+	if o.PollInterval == 0 {
+		o.PollInterval = constant.OutboxPollInterval
+	}
+	if o.BatchSize == 0 {
+		o.BatchSize = constant.OutboxBatchSize
+	}
+	return o
+}
+
+// WithDefaults fills in the defaults for settings left at zero, e.g. in configs
+// built without Load.
+func (s ShutdownConfig) WithDefaults() ShutdownConfig {
+	if s.HTTPTimeout == 0 {
+		s.HTTPTimeout = constant.ShutdownHTTPTimeout
+	}
+	if s.TaskTimeout == 0 {
+		s.TaskTimeout = constant.ShutdownTaskTimeout
+	}
+	if s.TaskPolicy == "" {
+		s.TaskPolicy = constant.ShutdownTaskPolicy
+	}
+	return s
+}
+
 // Helper functions to get environment variables with default values
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -86,3 +494,119 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvUint reads an unsigned integer environment variable with a default value
+func getEnvUint(key string, defaultValue uint) (uint, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(parsed), nil
+}
+
+// getEnvDuration reads a duration environment variable such as "500ms" with a default value
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("must be positive, got %s", value)
+	}
+	return parsed, nil
+}
+
+// splitList splits a comma separated list, dropping blank entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCrateSeeds parses a semicolon separated list of crates, each written as
+// "x,y[,id[,sku]]", e.g. "2,2,crate-1,SKU-001;5,5".
+func parseCrateSeeds(value string) ([]CrateSeed, error) {
+	var seeds []CrateSeed
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ",")
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("crate %q must be x,y[,id[,sku]]", entry)
+		}
+
+		x, err := strconv.ParseUint(strings.TrimSpace(fields[0]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("crate %q has invalid x: %v", entry, err)
+		}
+		y, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("crate %q has invalid y: %v", entry, err)
+		}
+
+		seed := CrateSeed{X: uint(x), Y: uint(y)}
+		if len(fields) > 2 {
+			seed.ID = strings.TrimSpace(fields[2])
+		}
+		if len(fields) > 3 {
+			seed.SKU = strings.TrimSpace(fields[3])
+		}
+		seeds = append(seeds, seed)
+	}
+
+	return seeds, nil
+}
+
+// parseRobotSeeds parses a semicolon separated list of robots, each written as
+// "id,x,y[,has_crate]", e.g. "robot-1,0,0,true;robot-2,9,0".
+func parseRobotSeeds(value string) ([]RobotSeed, error) {
+	var seeds []RobotSeed
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ",")
+		if len(fields) < 3 || len(fields) > 4 {
+			return nil, fmt.Errorf("robot %q must be id,x,y[,has_crate]", entry)
+		}
+
+		seed := RobotSeed{ID: strings.TrimSpace(fields[0])}
+		x, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("robot %q has invalid x: %v", entry, err)
+		}
+		y, err := strconv.ParseUint(strings.TrimSpace(fields[2]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("robot %q has invalid y: %v", entry, err)
+		}
+		seed.X, seed.Y = uint(x), uint(y)
+
+		if len(fields) > 3 {
+			if seed.HasCrate, err = strconv.ParseBool(strings.TrimSpace(fields[3])); err != nil {
+				return nil, fmt.Errorf("robot %q has invalid has_crate: %v", entry, err)
+			}
+		}
+		seeds = append(seeds, seed)
+	}
+
+	return seeds, nil
+}
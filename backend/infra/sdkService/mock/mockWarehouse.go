@@ -3,7 +3,9 @@ package mock
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 	"warehouse-robots/backend/api/constant"
 	"warehouse-robots/backend/api/model"
@@ -13,20 +15,47 @@ import (
 type MockWarehouse struct {
 	robots   []model.Robot
 	robotMap map[string]*MockRobot
+	floor    *mockFloor
 }
 
 // for each command, this is the delay in between.
-const stepDelay = 2 * time.Second
+const stepDelay = constant.RobotStepDuration
 
-func NewMockWarehouse() model.Warehouse {
-	robot1 := NewMockRobot("0", model.RobotState{X: 0, Y: 0, HasCrate: true})
+// RobotSeed places a mock robot on the floor when the warehouse starts.
+type RobotSeed struct {
+	ID    string
+	State model.RobotState
+}
+
+// NewMockWarehouse creates a mock warehouse laid out by the given map whose floor
+// starts with a crate on each of the given (x, y) cells and a robot on each seed's
+// cell. Seeds are expected to be valid: unique IDs on distinct, free cells.
+// Without seeds there is a single robot "robot-1" holding a crate, starting on its home
+// cell from the map or the grid origin if the map has none.
+func NewMockWarehouse(layout *model.WarehouseMap, crateCells [][2]uint, seeds []RobotSeed) model.Warehouse {
+	floor := newMockFloor(layout, crateCells)
+
+	if len(seeds) == 0 {
+		start := model.Cell{X: layout.Grid.OriginX, Y: layout.Grid.OriginY}
+		if home, ok := layout.RobotHome("robot-1"); ok {
+			start = home
+		}
+		seeds = []RobotSeed{{ID: "robot-1", State: model.RobotState{X: start.X, Y: start.Y, HasCrate: true}}}
+	}
 
-	return &MockWarehouse{
-		robots: []model.Robot{robot1},
-		robotMap: map[string]*MockRobot{
-			"0": robot1,
-		},
+	warehouse := &MockWarehouse{
+		robots:   make([]model.Robot, 0, len(seeds)),
+		robotMap: make(map[string]*MockRobot, len(seeds)),
+		floor:    floor,
 	}
+	for _, seed := range seeds {
+		robot := NewMockRobot(seed.ID, seed.State, floor)
+		floor.occupied[[2]uint{seed.State.X, seed.State.Y}] = seed.ID
+		warehouse.robots = append(warehouse.robots, robot)
+		warehouse.robotMap[seed.ID] = robot
+	}
+
+	return warehouse
 }
 
 // Robots returns all robots in the warehouse
@@ -34,10 +63,66 @@ func (w *MockWarehouse) Robots() []model.Robot {
 	return w.robots
 }
 
+// mockFloor is the physical state of the warehouse floor shared by all mock robots:
+// where the crates are and which robot stands on which cell.
+type mockFloor struct {
+	layout   *model.WarehouseMap
+	mu       sync.Mutex
+	crates   map[[2]uint]bool
+	occupied map[[2]uint]string
+}
+
+func newMockFloor(layout *model.WarehouseMap, crateCells [][2]uint) *mockFloor {
+	crates := make(map[[2]uint]bool, len(crateCells))
+	for _, cell := range crateCells {
+		crates[cell] = true
+	}
+	return &mockFloor{layout: layout, crates: crates, occupied: make(map[[2]uint]string)}
+}
+
+// moveRobot moves the robot between two cells, failing if another robot
+// stands on the destination.
+func (f *mockFloor) moveRobot(robotID string, fromX, fromY, toX, toY uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if other, ok := f.occupied[[2]uint{toX, toY}]; ok && other != robotID {
+		return fmt.Errorf("robot %s is in the way at (%d,%d)", other, toX, toY)
+	}
+	delete(f.occupied, [2]uint{fromX, fromY})
+	f.occupied[[2]uint{toX, toY}] = robotID
+	return nil
+}
+
+// takeCrate removes the crate from the cell, failing if the cell is empty.
+func (f *mockFloor) takeCrate(x, y uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.crates[[2]uint{x, y}] {
+		return fmt.Errorf("no crate to grab at (%d,%d)", x, y)
+	}
+	delete(f.crates, [2]uint{x, y})
+	return nil
+}
+
+// putCrate places a crate on the cell, failing if the cell already holds one.
+func (f *mockFloor) putCrate(x, y uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.crates[[2]uint{x, y}] {
+		return fmt.Errorf("cell (%d,%d) already holds a crate", x, y)
+	}
+	f.crates[[2]uint{x, y}] = true
+	return nil
+}
+
 // MockRobot implements the sdk.Robot interface with realistic behavior
 type MockRobot struct {
-	id           string
-	state        model.RobotState
+	id    string
+	state model.RobotState
+	floor *mockFloor
+	// mu guards the task queue so only one processTaskQueue runs at a time,
+	// and the state so it can be read while a task moves the robot
+	mu           sync.Mutex
 	currentTask  *MockTask
 	taskQueue    []*MockTask
 	allTasks     map[string]*MockTask
@@ -59,10 +144,11 @@ type MockTask struct {
 }
 
 // NewMockRobot creates a new mock facades
-func NewMockRobot(id string, initialState model.RobotState) *MockRobot {
+func NewMockRobot(id string, initialState model.RobotState, floor *mockFloor) *MockRobot {
 	return &MockRobot{
 		id:           id,
 		state:        initialState,
+		floor:        floor,
 		currentTask:  nil,
 		taskQueue:    make([]*MockTask, 0),
 		allTasks:     make(map[string]*MockTask),
@@ -76,6 +162,9 @@ func (r *MockRobot) EnqueueTask(commands string) (taskID string, position chan m
 	posCh := make(chan model.RobotState, 10)
 	errCh := make(chan error, 1)
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	// Check if we've reached the maximum queue size (5 tasks total: 1 running + 4 queued)
 	totalTasks := len(r.taskQueue)
 	if r.currentTask != nil && !r.isTaskFinished(r.currentTask) {
@@ -106,8 +195,10 @@ func (r *MockRobot) EnqueueTask(commands string) (taskID string, position chan m
 	}
 
 	r.taskQueue = append(r.taskQueue, task)
+	r.allTasks[taskID] = task
 
 	if !r.isProcessing {
+		r.isProcessing = true
 		go r.processTaskQueue()
 	}
 
@@ -115,12 +206,12 @@ func (r *MockRobot) EnqueueTask(commands string) (taskID string, position chan m
 }
 
 func (r *MockRobot) processTaskQueue() {
-	r.isProcessing = true
-	defer func() { r.isProcessing = false }()
-
 	for {
+		r.mu.Lock()
 		if len(r.taskQueue) == 0 {
 			r.currentTask = nil
+			r.isProcessing = false
+			r.mu.Unlock()
 			break
 		}
 
@@ -128,6 +219,7 @@ func (r *MockRobot) processTaskQueue() {
 		task := r.taskQueue[0]
 		r.taskQueue = r.taskQueue[1:] // Remove from queue
 		r.currentTask = task
+		r.mu.Unlock()
 
 		// Execute the task
 		r.executeTask(task, task.PositionChan, task.ErrorChan)
@@ -145,7 +237,7 @@ func (r *MockRobot) executeTask(task *MockTask, posCh chan model.RobotState, err
 		}
 	}()
 
-	task.Status = "IN_PROGRESS"
+	r.setStatus(task, "IN_PROGRESS")
 
 	// Send initial position
 	posCh <- r.state
@@ -154,7 +246,7 @@ func (r *MockRobot) executeTask(task *MockTask, posCh chan model.RobotState, err
 		// Check for cancellation
 		select {
 		case <-task.Cancel:
-			task.Status = "CANCELLED"
+			r.cancelled(task, errCh)
 			return
 		default:
 		}
@@ -163,20 +255,72 @@ func (r *MockRobot) executeTask(task *MockTask, posCh chan model.RobotState, err
 		select {
 		case <-time.After(stepDelay):
 		case <-task.Cancel:
-			task.Status = "CANCELLED"
+			r.cancelled(task, errCh)
 			return
 		}
 
 		// Execute command (with boundary checks)
+		x, y := int(r.state.X), int(r.state.Y)
 		switch cmd {
 		case 'N':
-			r.state.Y += constant.RobotMoveUnit
+			y += constant.RobotMoveUnit
 		case 'S':
-			r.state.Y -= constant.RobotMoveUnit
+			y -= constant.RobotMoveUnit
 		case 'E':
-			r.state.X += constant.RobotMoveUnit
+			x += constant.RobotMoveUnit
 		case 'W':
-			r.state.X -= constant.RobotMoveUnit
+			x -= constant.RobotMoveUnit
+		}
+		if !r.floor.layout.Grid.Contains(x, y) {
+			errCh <- fmt.Errorf("robot %s cannot move %c from (%d,%d): outside the warehouse", r.id, cmd, r.state.X, r.state.Y)
+			r.setStatus(task, "FAILED")
+			return
+		}
+		if obstruction := r.floor.layout.Obstruction(x, y); obstruction != "" {
+			errCh <- fmt.Errorf("robot %s cannot move %c from (%d,%d): %s in the way", r.id, cmd, r.state.X, r.state.Y, obstruction)
+			r.setStatus(task, "FAILED")
+			return
+		}
+		if uint(x) != r.state.X || uint(y) != r.state.Y {
+			if err := r.floor.moveRobot(r.id, r.state.X, r.state.Y, uint(x), uint(y)); err != nil {
+				errCh <- fmt.Errorf("robot %s cannot move %c from (%d,%d): %v", r.id, cmd, r.state.X, r.state.Y, err)
+				r.setStatus(task, "FAILED")
+				return
+			}
+		}
+		r.mu.Lock()
+		r.state.X, r.state.Y = uint(x), uint(y)
+		r.mu.Unlock()
+
+		switch cmd {
+		case 'G':
+			if r.state.HasCrate {
+				errCh <- fmt.Errorf("robot %s cannot grab: already holding a crate", r.id)
+				r.setStatus(task, "FAILED")
+				return
+			}
+			if err := r.floor.takeCrate(r.state.X, r.state.Y); err != nil {
+				errCh <- err
+				r.setStatus(task, "FAILED")
+				return
+			}
+			r.mu.Lock()
+			r.state.HasCrate = true
+			r.mu.Unlock()
+		case 'D':
+			if !r.state.HasCrate {
+				errCh <- fmt.Errorf("robot %s cannot drop: not holding a crate", r.id)
+				r.setStatus(task, "FAILED")
+				return
+			}
+			if err := r.floor.putCrate(r.state.X, r.state.Y); err != nil {
+				errCh <- err
+				r.setStatus(task, "FAILED")
+				return
+			}
+			r.mu.Lock()
+			r.state.HasCrate = false
+			r.mu.Unlock()
 		}
 
 		// Update remaining commands
@@ -190,21 +334,80 @@ func (r *MockRobot) executeTask(task *MockTask, posCh chan model.RobotState, err
 		posCh <- r.state
 	}
 
-	task.Status = "COMPLETED"
+	r.setStatus(task, "COMPLETED")
 }
 
-// CancelTask cancels a task unconditionally if it exists.
+// CancelTask cancels a queued or running task. A queued task is dropped from
+// the queue; a running one stops between two commands, and CancelTask returns
+// once it has, so CurrentState is where the robot stopped.
 func (r *MockRobot) CancelTask(taskID string) error {
+	r.mu.Lock()
+	task, exists := r.allTasks[taskID]
+	if !exists {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s", model.ErrSDKTaskUnknown, taskID)
+	}
+	if r.isTaskFinished(task) {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s is %s", model.ErrSDKTaskFinished, taskID, task.Status)
+	}
+	for i, queued := range r.taskQueue {
+		if queued == task {
+			r.taskQueue = slices.Delete(r.taskQueue, i, i+1)
+			task.Status = "CANCELLED"
+			r.mu.Unlock()
+			task.ErrorChan <- model.ErrSDKTaskCancelled
+			close(task.PositionChan)
+			close(task.ErrorChan)
+			return nil
+		}
+	}
+	r.mu.Unlock()
+
+	// Running: executeTask picks the signal up before its next command
+	select {
+	case task.Cancel <- true:
+	default:
+	}
+	select {
+	case <-task.Done:
+	case <-time.After(2 * stepDelay):
+		return fmt.Errorf("robot %s did not stop task %s", r.id, taskID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if task.Status != "CANCELLED" {
+		return fmt.Errorf("%w: %s is %s", model.ErrSDKTaskFinished, taskID, task.Status)
+	}
 	return nil
 }
 
 // CurrentState returns the current state of the facades
 func (r *MockRobot) CurrentState() model.RobotState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.state
 }
 
+// WatchTask implements model.TaskWatcher: it hands out the channels of a task
+// that is still queued or running.
+func (r *MockRobot) WatchTask(taskID string) (position chan model.RobotState, err chan error, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, exists := r.allTasks[taskID]
+	if !exists || r.isTaskFinished(task) {
+		return nil, nil, false
+	}
+	return task.PositionChan, task.ErrorChan, true
+}
+
 // GetTaskStatus returns the current task status (helper method)
 func (r *MockRobot) GetTaskStatus(taskID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.currentTask == nil || r.currentTask.ID != taskID {
 		return "", errors.New("task not found")
 	}
@@ -222,7 +425,21 @@ func (r *MockRobot) GetID() string {
 	return r.id
 }
 
-// isTaskFinished checks if a task is in a finished state
+// setStatus records the task's status; it is read under the same lock.
+func (r *MockRobot) setStatus(task *MockTask, status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task.Status = status
+}
+
+// cancelled ends a running task that was cancelled, reporting it after the
+// positions already sent.
+func (r *MockRobot) cancelled(task *MockTask, errCh chan error) {
+	r.setStatus(task, "CANCELLED")
+	errCh <- model.ErrSDKTaskCancelled
+}
+
+// isTaskFinished checks if a task is in a finished state. Callers must hold mu.
 func (r *MockRobot) isTaskFinished(task *MockTask) bool {
 	if task == nil {
 		return true
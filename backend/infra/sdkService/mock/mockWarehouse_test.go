@@ -0,0 +1,113 @@
+package mock
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestMockWarehouse_RobotsCannotShareACell(t *testing.T) {
+	warehouse := NewMockWarehouse(model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}), nil, []RobotSeed{
+		{ID: "a", State: model.RobotState{X: 0, Y: 0}},
+		{ID: "b", State: model.RobotState{X: 1, Y: 0}},
+	})
+	robots := warehouse.Robots()
+	if len(robots) != 2 {
+		t.Fatalf("expected 2 robots, got %d", len(robots))
+	}
+
+	taskID, posCh, errCh := robots[0].EnqueueTask("E")
+	if !strings.HasPrefix(taskID, "task_a_") {
+		t.Errorf("expected the task ID to name robot a, got %q", taskID)
+	}
+
+	select {
+	case err := <-errCh:
+		if err == nil || !strings.Contains(err.Error(), "robot b is in the way at (1,0)") {
+			t.Errorf("expected robot b to block the move, got %v", err)
+		}
+	case <-time.After(2*stepDelay + time.Second):
+		t.Fatal("timed out waiting for the move to fail")
+	}
+	for range posCh {
+	}
+
+	if state := robots[0].CurrentState(); state.X != 0 || state.Y != 0 {
+		t.Errorf("expected robot a to stay at (0,0), got (%d,%d)", state.X, state.Y)
+	}
+}
+
+func TestMockRobot_WatchTask(t *testing.T) {
+	warehouse := NewMockWarehouse(model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}), nil, []RobotSeed{
+		{ID: "a", State: model.RobotState{X: 0, Y: 0}},
+	})
+	robot := warehouse.Robots()[0].(model.TaskWatcher)
+
+	taskID, _, _ := robot.EnqueueTask("")
+	posCh, _, ok := robot.WatchTask(taskID)
+	if !ok {
+		t.Fatal("expected a queued task to be watchable")
+	}
+	for range posCh {
+	}
+
+	if _, _, ok := robot.WatchTask(taskID); ok {
+		t.Error("expected a finished task not to be watchable")
+	}
+	if _, _, ok := robot.WatchTask("task_a_99"); ok {
+		t.Error("expected an unknown task not to be watchable")
+	}
+}
+
+func TestMockRobot_CancelTask(t *testing.T) {
+	warehouse := NewMockWarehouse(model.NewWarehouseMap(model.Grid{Width: 3, Height: 3}), nil, []RobotSeed{
+		{ID: "a", State: model.RobotState{X: 0, Y: 0}},
+	})
+	robot := warehouse.Robots()[0]
+
+	running, runningPos, runningErr := robot.EnqueueTask("NN")
+	queued, queuedPos, queuedErr := robot.EnqueueTask("E")
+
+	// a queued task never starts
+	if err := robot.CancelTask(queued); err != nil {
+		t.Fatalf("cancel queued task: %v", err)
+	}
+	if err := <-queuedErr; !errors.Is(err, model.ErrSDKTaskCancelled) {
+		t.Errorf("expected the queued task to report its cancellation, got %v", err)
+	}
+	if _, ok := <-queuedPos; ok {
+		t.Error("expected a cancelled queued task to report no position")
+	}
+
+	// a running task stops where it got to
+	<-runningPos // start cell
+	select {
+	case position := <-runningPos:
+		if position.Y != 1 {
+			t.Fatalf("expected the first step to reach (0,1), got (%d,%d)", position.X, position.Y)
+		}
+	case <-time.After(2*stepDelay + time.Second):
+		t.Fatal("timed out waiting for the first step")
+	}
+	if err := robot.CancelTask(running); err != nil {
+		t.Fatalf("cancel running task: %v", err)
+	}
+	if state := robot.CurrentState(); state.X != 0 || state.Y != 1 {
+		t.Errorf("expected the robot to stop at (0,1), got (%d,%d)", state.X, state.Y)
+	}
+	if err := <-runningErr; !errors.Is(err, model.ErrSDKTaskCancelled) {
+		t.Errorf("expected the running task to report its cancellation, got %v", err)
+	}
+	if _, ok := <-runningPos; ok {
+		t.Error("expected no position after the cancellation")
+	}
+
+	if err := robot.CancelTask(running); !errors.Is(err, model.ErrSDKTaskFinished) {
+		t.Errorf("expected a finished task not to be cancellable, got %v", err)
+	}
+	if err := robot.CancelTask("task_a_99"); !errors.Is(err, model.ErrSDKTaskUnknown) {
+		t.Errorf("expected an unknown task to be rejected, got %v", err)
+	}
+}
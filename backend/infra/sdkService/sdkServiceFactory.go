@@ -20,9 +20,30 @@ func NewRobotSDKFactory(cfg *config.Config) *RobotSDKFactory {
 // CreateRobotSDKService creates either mock or real SDK service based on configuration
 func (f *RobotSDKFactory) CreateRobotSDKService() model.Warehouse {
 	if f.config.Robot.EnableMock {
-		return mockSdk.NewMockWarehouse()
+		return mockSdk.NewMockWarehouse(f.config.Warehouse.Layout(), f.crateCells(), f.robotSeeds())
 	}
 
 	// Return real implementation when available
-	return mockSdk.NewMockWarehouse()
+	return mockSdk.NewMockWarehouse(f.config.Warehouse.Layout(), f.crateCells(), f.robotSeeds())
+}
+
+// crateCells lists the cells of the configured crates for seeding the mock floor
+func (f *RobotSDKFactory) crateCells() [][2]uint {
+	cells := make([][2]uint, 0, len(f.config.Warehouse.Crates))
+	for _, crate := range f.config.Warehouse.Crates {
+		cells = append(cells, [2]uint{crate.X, crate.Y})
+	}
+	return cells
+}
+
+// robotSeeds converts the configured robots for the mock SDK
+func (f *RobotSDKFactory) robotSeeds() []mockSdk.RobotSeed {
+	seeds := make([]mockSdk.RobotSeed, 0, len(f.config.Robot.Robots))
+	for _, robot := range f.config.Robot.Robots {
+		seeds = append(seeds, mockSdk.RobotSeed{
+			ID:    robot.ID,
+			State: model.RobotState{X: robot.X, Y: robot.Y, HasCrate: robot.HasCrate},
+		})
+	}
+	return seeds
 }
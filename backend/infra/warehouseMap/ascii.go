@@ -0,0 +1,210 @@
+package warehouseMap
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The ASCII format is a list of directives followed by a "map" line and the
+// grid itself, drawn with north at the top:
+//
+//	// comments start with two slashes
+//	origin 0 0
+//	zone "charging bay" 8 0 9 1
+//	map
+//	#........#
+//	#.SS..SS.#
+//	#1..C...D#
+//
+// Directives:
+//   - origin <x> <y>                          coordinate of the bottom-left cell (default 0 0)
+//   - zone <name> <minX> <minY> <maxX> <maxY> named no-go rectangle, corners inclusive;
+//     names containing spaces are double-quoted
+//
+// Grid legend:
+//   - '.' floor
+//   - '#' wall
+//   - 'S' shelf
+//   - 'C' crate slot, starts with a crate on it
+//   - 'D' charging dock
+//   - '1'-'9' home cell of robot "robot-N", the IDs the robot registry gives
+//
+// Every grid row must be the same width. The grid ends at the end of the file;
+// trailing blank lines are ignored.
+
+// token is a directive word and the column it starts at.
+type token struct {
+	text   string
+	column int
+}
+
+// parseASCII parses the ASCII map format into a rawLayout.
+func parseASCII(data []byte) (*rawLayout, error) {
+	var errs ErrorList
+	layout := &rawLayout{relative: true}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	mapLine := 0
+	for i, line := range lines {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+
+		tokens, err := tokenize(line, lineNo)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		switch tokens[0].text {
+		case "map":
+			if len(tokens) > 1 {
+				errs.add(Position{lineNo, tokens[1].column}, "unexpected %q after map", tokens[1].text)
+			}
+			mapLine = lineNo
+		case "origin":
+			values, ok := parseInts(tokens[0], tokens[1:], 2, lineNo, &errs)
+			if ok {
+				layout.originX, layout.originY = values[0], values[1]
+				layout.hasOrigin = true
+				layout.originPos = Position{lineNo, tokens[0].column}
+			}
+		case "zone":
+			if len(tokens) < 2 {
+				errs.add(Position{lineNo, tokens[0].column}, "zone needs a name and 4 coordinates")
+				continue
+			}
+			values, ok := parseInts(tokens[0], tokens[2:], 4, lineNo, &errs)
+			if ok {
+				layout.zones = append(layout.zones, rawZone{
+					name: tokens[1].text,
+					minX: values[0], minY: values[1], maxX: values[2], maxY: values[3],
+					pos: Position{lineNo, tokens[0].column},
+				})
+			}
+		default:
+			errs.add(Position{lineNo, tokens[0].column}, "unknown directive %q", tokens[0].text)
+		}
+
+		if mapLine != 0 {
+			break
+		}
+	}
+
+	if mapLine == 0 {
+		errs.add(Position{len(lines), 1}, `missing "map" line before the grid`)
+		return nil, errs
+	}
+
+	parseGrid(layout, lines, mapLine, &errs)
+
+	return layout, errs.err()
+}
+
+// parseGrid reads the grid rows that follow the "map" line.
+func parseGrid(layout *rawLayout, lines []string, mapLine int, errs *ErrorList) {
+	rows := lines[mapLine:]
+	for len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		errs.add(Position{mapLine, 1}, "map has no rows")
+		return
+	}
+
+	width := utf8.RuneCountInString(rows[0])
+	layout.width, layout.height = width, len(rows)
+	layout.hasSize = true
+	layout.sizePos = Position{mapLine + 1, 1}
+
+	for r, row := range rows {
+		lineNo := mapLine + r + 1
+		if rowWidth := utf8.RuneCountInString(row); rowWidth != width {
+			column := rowWidth + 1
+			if rowWidth > width {
+				column = width + 1
+			}
+			errs.add(Position{lineNo, column}, "row is %d cells wide, expected %d", rowWidth, width)
+			continue
+		}
+
+		// north is at the top, so the first row has the largest y
+		y := len(rows) - 1 - r
+		column := 0
+		for _, ch := range row {
+			column++
+			x := column - 1
+			pos := Position{lineNo, column}
+
+			switch {
+			case ch == '.':
+			case ch == '#':
+				layout.features = append(layout.features, rawFeature{kind: featureWall, x: x, y: y, pos: pos})
+			case ch == 'S':
+				layout.features = append(layout.features, rawFeature{kind: featureShelf, x: x, y: y, pos: pos})
+			case ch == 'C':
+				layout.features = append(layout.features, rawFeature{kind: featureCrateSlot, x: x, y: y, pos: pos})
+			case ch == 'D':
+				layout.features = append(layout.features, rawFeature{kind: featureChargingDock, x: x, y: y, pos: pos})
+			case ch >= '1' && ch <= '9':
+				layout.features = append(layout.features, rawFeature{kind: featureRobotHome, x: x, y: y, robotID: "robot-" + string(ch), pos: pos})
+			default:
+				errs.add(pos, "unknown cell %q", ch)
+			}
+		}
+	}
+}
+
+// tokenize splits a directive line into words; double-quoted words may contain spaces.
+func tokenize(line string, lineNo int) ([]token, *ParseError) {
+	var tokens []token
+	i := 0
+	for i < len(line) {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		if line[i] == '"' {
+			end := strings.IndexByte(line[i+1:], '"')
+			if end < 0 {
+				return nil, &ParseError{Pos: Position{lineNo, start + 1}, Msg: "unterminated quoted name"}
+			}
+			tokens = append(tokens, token{text: line[i+1 : i+1+end], column: start + 1})
+			i += end + 2
+			continue
+		}
+
+		for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			i++
+		}
+		tokens = append(tokens, token{text: line[start:i], column: start + 1})
+	}
+	return tokens, nil
+}
+
+// parseInts parses exactly n integer arguments of a directive.
+func parseInts(directive token, args []token, n int, lineNo int, errs *ErrorList) ([]int, bool) {
+	if len(args) != n {
+		errs.add(Position{lineNo, directive.column}, "%s needs %d numbers, got %d", directive.text, n, len(args))
+		return nil, false
+	}
+
+	values := make([]int, n)
+	ok := true
+	for i, arg := range args {
+		value, err := strconv.Atoi(arg.text)
+		if err != nil {
+			errs.add(Position{lineNo, arg.column}, "%q is not a number", arg.text)
+			ok = false
+			continue
+		}
+		values[i] = value
+	}
+	return values, ok
+}
@@ -0,0 +1,47 @@
+package warehouseMap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position is a 1-based line and column in a map file.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// ParseError is a single problem found while parsing or validating a map file.
+type ParseError struct {
+	Pos Position
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Pos.Line, e.Pos.Column, e.Msg)
+}
+
+// ErrorList collects every problem found in a map file so they can all be
+// fixed in one go rather than one restart at a time.
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	messages := make([]string, 0, len(l))
+	for _, err := range l {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// add appends a formatted error at the given position.
+func (l *ErrorList) add(pos Position, format string, args ...interface{}) {
+	*l = append(*l, &ParseError{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// err returns the list as an error, or nil if it is empty.
+func (l ErrorList) err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
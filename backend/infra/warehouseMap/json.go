@@ -0,0 +1,205 @@
+package warehouseMap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// jsonLayout is the JSON map format, e.g.
+//
+//	{
+//	  "width": 10, "height": 10, "origin": {"x": 0, "y": 0},
+//	  "walls": [{"x": 0, "y": 9}],
+//	  "shelves": [{"x": 4, "y": 4}, {"x": 4, "y": 5}],
+//	  "blocked": [{"x": 6, "y": 6}],
+//	  "crate_slots": [{"x": 2, "y": 2}],
+//	  "charging_docks": [{"x": 9, "y": 0}],
+//	  "robot_homes": [{"id": "robot-1", "x": 0, "y": 0}],
+//	  "no_go_zones": [{"name": "charging bay", "min": {"x": 8, "y": 0}, "max": {"x": 9, "y": 1}}]
+//	}
+//
+// Every field is optional; width, height and origin default to the configured grid.
+type jsonLayout struct {
+	Width         *int            `json:"width"`
+	Height        *int            `json:"height"`
+	Origin        *jsonCell       `json:"origin"`
+	Walls         []jsonCell      `json:"walls"`
+	Shelves       []jsonCell      `json:"shelves"`
+	Blocked       []jsonCell      `json:"blocked"`
+	CrateSlots    []jsonCell      `json:"crate_slots"`
+	ChargingDocks []jsonCell      `json:"charging_docks"`
+	RobotHomes    []jsonRobotHome `json:"robot_homes"`
+	NoGoZones     []jsonZone      `json:"no_go_zones"`
+}
+
+type jsonCell struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type jsonRobotHome struct {
+	ID string `json:"id"`
+	X  int    `json:"x"`
+	Y  int    `json:"y"`
+}
+
+type jsonZone struct {
+	Name string   `json:"name"`
+	Min  jsonCell `json:"min"`
+	Max  jsonCell `json:"max"`
+}
+
+// parseJSON parses the JSON map format into a rawLayout. Unknown fields are
+// rejected so typos do not silently drop obstacles.
+func parseJSON(data []byte) (*rawLayout, error) {
+	var file jsonLayout
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return nil, ErrorList{&ParseError{Pos: positionAt(data, jsonErrorOffset(err, dec)), Msg: err.Error()}}
+	}
+
+	offsets := valueOffsets(data)
+	pos := func(path string) Position {
+		return positionAt(data, offsets[path])
+	}
+
+	layout := &rawLayout{}
+	if file.Width != nil || file.Height != nil {
+		if file.Width == nil || file.Height == nil {
+			return nil, ErrorList{&ParseError{Pos: pos(""), Msg: "width and height must be given together"}}
+		}
+		layout.width, layout.height = *file.Width, *file.Height
+		layout.hasSize = true
+		layout.sizePos = pos("width")
+	}
+	if file.Origin != nil {
+		layout.originX, layout.originY = file.Origin.X, file.Origin.Y
+		layout.hasOrigin = true
+		layout.originPos = pos("origin")
+	}
+
+	addCells := func(kind featureKind, field string, cells []jsonCell) {
+		for i, cell := range cells {
+			layout.features = append(layout.features, rawFeature{
+				kind: kind, x: cell.X, y: cell.Y,
+				pos: pos(fmt.Sprintf("%s[%d]", field, i)),
+			})
+		}
+	}
+	addCells(featureWall, "walls", file.Walls)
+	addCells(featureShelf, "shelves", file.Shelves)
+	addCells(featureBlocked, "blocked", file.Blocked)
+	addCells(featureCrateSlot, "crate_slots", file.CrateSlots)
+	addCells(featureChargingDock, "charging_docks", file.ChargingDocks)
+
+	for i, home := range file.RobotHomes {
+		layout.features = append(layout.features, rawFeature{
+			kind: featureRobotHome, x: home.X, y: home.Y, robotID: home.ID,
+			pos: pos(fmt.Sprintf("robot_homes[%d]", i)),
+		})
+	}
+
+	for i, zone := range file.NoGoZones {
+		layout.zones = append(layout.zones, rawZone{
+			name: zone.Name,
+			minX: zone.Min.X, minY: zone.Min.Y, maxX: zone.Max.X, maxY: zone.Max.Y,
+			pos: pos(fmt.Sprintf("no_go_zones[%d]", i)),
+		})
+	}
+
+	return layout, nil
+}
+
+// jsonErrorOffset returns the byte offset a decode error refers to.
+func jsonErrorOffset(err error, dec *json.Decoder) int64 {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) && syntaxErr.Offset > 0 {
+		// the offset is just past the offending character
+		return syntaxErr.Offset - 1
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Offset
+	}
+	// unknown fields carry no offset; the decoder stops just past them
+	return dec.InputOffset()
+}
+
+// valueOffsets walks the JSON document and records the byte offset at which
+// each value starts, keyed by path such as "walls[2]" or "origin".
+func valueOffsets(data []byte) map[string]int64 {
+	offsets := make(map[string]int64)
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		offsets[path] = skipSeparators(data, dec.InputOffset())
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				child := fmt.Sprint(key)
+				if path != "" {
+					child = path + "." + child
+				}
+				if err := walk(child); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+
+	// the document already decoded once, so errors here are not expected
+	_ = walk("")
+	return offsets
+}
+
+// skipSeparators advances past whitespace, colons and commas to the next value.
+func skipSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// positionAt converts a byte offset into a 1-based line and column.
+func positionAt(data []byte, offset int64) Position {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line, column := 1, 1
+	for _, b := range data[:offset] {
+		if b == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return Position{Line: line, Column: column}
+}
@@ -0,0 +1,85 @@
+package warehouseMap
+
+import "warehouse-robots/backend/api/model"
+
+// featureKind is what occupies a cell in a map file.
+type featureKind int
+
+const (
+	featureWall featureKind = iota
+	featureShelf
+	featureBlocked
+	featureCrateSlot
+	featureChargingDock
+	featureRobotHome
+)
+
+func (k featureKind) String() string {
+	switch k {
+	case featureWall:
+		return "wall"
+	case featureShelf:
+		return "shelf"
+	case featureBlocked:
+		return "blocked cell"
+	case featureCrateSlot:
+		return "crate slot"
+	case featureChargingDock:
+		return "charging dock"
+	default:
+		return "robot home"
+	}
+}
+
+// rawFeature is a feature as written in the file, before validation.
+// Coordinates are signed so out-of-range values survive until the validator
+// can report them.
+type rawFeature struct {
+	kind    featureKind
+	x, y    int
+	robotID string
+	pos     Position
+}
+
+// rawZone is a no-go zone as written in the file, before validation.
+type rawZone struct {
+	name                   string
+	minX, minY, maxX, maxY int
+	pos                    Position
+}
+
+// rawLayout is the format-independent result of parsing a map file.
+// Both the ASCII and JSON parsers produce it and the validator turns it into a
+// model.WarehouseMap.
+type rawLayout struct {
+	width, height    int
+	hasSize          bool
+	sizePos          Position
+	originX, originY int
+	hasOrigin        bool
+	originPos        Position
+	features         []rawFeature
+	zones            []rawZone
+
+	// relative is set when feature coordinates are offsets from the origin,
+	// as in ASCII grids, rather than absolute warehouse coordinates.
+	relative bool
+}
+
+// defaults fills in the size and origin the file left out from the fallback
+// grid and converts relative feature coordinates to absolute ones.
+func (l *rawLayout) defaults(fallback model.Grid) {
+	if !l.hasSize {
+		l.width, l.height = int(fallback.Width), int(fallback.Height)
+	}
+	if !l.hasOrigin {
+		l.originX, l.originY = int(fallback.OriginX), int(fallback.OriginY)
+	}
+	if l.relative {
+		for i := range l.features {
+			l.features[i].x += l.originX
+			l.features[i].y += l.originY
+		}
+		l.relative = false
+	}
+}
@@ -0,0 +1,42 @@
+package warehouseMap
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"warehouse-robots/backend/api/model"
+)
+
+// LoadFile reads and validates a warehouse map file. Files whose first
+// non-blank character is '{' are read as JSON, everything else as the ASCII
+// grid format. The fallback grid supplies the size and origin when the file
+// does not define them.
+func LoadFile(path string, fallback model.Grid) (*model.WarehouseMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read map file: %w", err)
+	}
+
+	warehouseMap, err := Parse(data, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("map file %s:\n%w", path, err)
+	}
+	return warehouseMap, nil
+}
+
+// Parse parses and validates map file contents. Problems are returned as an
+// ErrorList with the line and column of every offending entry.
+func Parse(data []byte, fallback model.Grid) (*model.WarehouseMap, error) {
+	var layout *rawLayout
+	var err error
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		layout, err = parseJSON(data)
+	} else {
+		layout, err = parseASCII(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return validate(layout, fallback)
+}
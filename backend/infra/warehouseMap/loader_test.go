@@ -0,0 +1,109 @@
+package warehouseMap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"warehouse-robots/backend/api/model"
+)
+
+func TestLoadFile_DemoMapsAgree(t *testing.T) {
+	asciiMap, err := LoadFile("../../maps/demo.map", model.DefaultGrid())
+	if err != nil {
+		t.Fatalf("LoadFile(demo.map) unexpected error = %v", err)
+	}
+
+	jsonMap, err := LoadFile("../../maps/demo.json", model.DefaultGrid())
+	if err != nil {
+		t.Fatalf("LoadFile(demo.json) unexpected error = %v", err)
+	}
+
+	if asciiMap.Grid != jsonMap.Grid {
+		t.Errorf("grids differ: ascii=%+v json=%+v", asciiMap.Grid, jsonMap.Grid)
+	}
+	if !reflect.DeepEqual(asciiMap.Shelves, jsonMap.Shelves) {
+		t.Errorf("shelves differ: ascii=%v json=%v", asciiMap.Shelves, jsonMap.Shelves)
+	}
+	if len(asciiMap.CrateSlots) != 4 || len(jsonMap.CrateSlots) != 4 {
+		t.Errorf("expected 4 crate slots, got ascii=%d json=%d", len(asciiMap.CrateSlots), len(jsonMap.CrateSlots))
+	}
+	if !reflect.DeepEqual(asciiMap.RobotHomes, jsonMap.RobotHomes) {
+		t.Errorf("robot homes differ: ascii=%v json=%v", asciiMap.RobotHomes, jsonMap.RobotHomes)
+	}
+	if !reflect.DeepEqual(asciiMap.Zones, jsonMap.Zones) {
+		t.Errorf("zones differ: ascii=%v json=%v", asciiMap.Zones, jsonMap.Zones)
+	}
+}
+
+func TestParse_ASCIICoordinates(t *testing.T) {
+	data := []byte("origin 1 2\nmap\n#S\n1D\n")
+
+	warehouseMap, err := Parse(data, model.DefaultGrid())
+	if err != nil {
+		t.Fatalf("Parse() unexpected error = %v", err)
+	}
+
+	if warehouseMap.Grid != (model.Grid{OriginX: 1, OriginY: 2, Width: 2, Height: 2}) {
+		t.Errorf("unexpected grid %+v", warehouseMap.Grid)
+	}
+	if !warehouseMap.Walls[model.Cell{X: 1, Y: 3}] {
+		t.Errorf("expected wall at the north-west cell (1,3)")
+	}
+	if !warehouseMap.Shelves[model.Cell{X: 2, Y: 3}] {
+		t.Errorf("expected shelf at (2,3)")
+	}
+	if home, ok := warehouseMap.RobotHome("robot-1"); !ok || home != (model.Cell{X: 1, Y: 2}) {
+		t.Errorf("expected robot-1 home at the origin, got %v %v", home, ok)
+	}
+	if len(warehouseMap.ChargingDocks) != 1 || warehouseMap.ChargingDocks[0] != (model.Cell{X: 2, Y: 2}) {
+		t.Errorf("expected charging dock at (2,2), got %v", warehouseMap.ChargingDocks)
+	}
+}
+
+func TestParse_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Position
+	}{
+		{"unknown_cell", "map\n..\n.Q\n", []Position{{3, 2}}},
+		{"ragged_row", "map\n...\n..\n", []Position{{3, 3}}},
+		{"unknown_directive", "size 3 3\nmap\n.\n", []Position{{1, 1}}},
+		{"bad_number", "origin 0 x\nmap\n.\n", []Position{{1, 10}}},
+		{"missing_map", "origin 0 0\n", []Position{{2, 1}}},
+		{"duplicate_home", "map\n1.\n.1\n", []Position{{3, 2}}},
+		{"zone_outside", "zone dock 0 0 5 5\nmap\n..\n..\n", []Position{{1, 1}}},
+		{"zone_covers_home", "zone \"home bay\" 0 0 0 0\nmap\n1.\n", []Position{{1, 1}}},
+		{"home_zero", "map\n0.\n", []Position{{2, 1}}},
+		{"several_errors", "map\n.Q\n..X\n", []Position{{2, 2}, {3, 3}}},
+		{"json_syntax", "{\n  \"width\": 3,\n  \"height\" 3\n}", []Position{{3, 12}}},
+		{"json_unknown_field", "{\"width\": 2, \"height\": 2, \"walls\": [], \"wals\": []}", nil},
+		{"json_outside", "{\n  \"width\": 2, \"height\": 2,\n  \"walls\": [\n    {\"x\": 0, \"y\": 0},\n    {\"x\": 5, \"y\": 0}\n  ]\n}", []Position{{5, 5}}},
+		{"json_overlap", "{\n  \"walls\": [{\"x\": 1, \"y\": 1}],\n  \"crate_slots\": [{\"x\": 1, \"y\": 1}]\n}", []Position{{3, 19}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), model.DefaultGrid())
+			if err == nil {
+				t.Fatalf("Parse() expected error but got none")
+			}
+
+			var errs ErrorList
+			if !errors.As(err, &errs) {
+				t.Fatalf("Parse() expected ErrorList but got %T: %v", err, err)
+			}
+			if tt.want == nil {
+				return
+			}
+
+			got := make([]Position, 0, len(errs))
+			for _, parseErr := range errs {
+				got = append(got, parseErr.Pos)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() error positions = %v, want %v\n%v", got, tt.want, err)
+			}
+		})
+	}
+}
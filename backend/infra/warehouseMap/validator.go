@@ -0,0 +1,136 @@
+package warehouseMap
+
+import (
+	"sort"
+	"warehouse-robots/backend/api/model"
+)
+
+// validate checks a parsed layout and builds the warehouse map from it.
+// Every problem is reported with the position of the offending entry:
+//   - the grid must be at least 1x1 and the origin non-negative
+//   - every feature and zone must lie inside the grid
+//   - a cell holds at most one feature
+//   - robot homes need a unique, non-empty ID
+//   - zones need a unique name, min <= max, and must not cover a crate slot,
+//     charging dock or robot home
+func validate(layout *rawLayout, fallback model.Grid) (*model.WarehouseMap, error) {
+	var errs ErrorList
+	layout.defaults(fallback)
+
+	if layout.width < 1 || layout.height < 1 {
+		errs.add(layout.sizePos, "warehouse must be at least 1x1, got %dx%d", layout.width, layout.height)
+		return nil, errs
+	}
+	if layout.originX < 0 || layout.originY < 0 {
+		errs.add(layout.originPos, "origin (%d,%d) must not be negative", layout.originX, layout.originY)
+		return nil, errs
+	}
+
+	grid := model.Grid{
+		OriginX: uint(layout.originX),
+		OriginY: uint(layout.originY),
+		Width:   uint(layout.width),
+		Height:  uint(layout.height),
+	}
+	warehouseMap := model.NewWarehouseMap(grid)
+
+	occupied := make(map[model.Cell]rawFeature)
+	homes := make(map[string]rawFeature)
+	for _, feature := range layout.features {
+		if !grid.Contains(feature.x, feature.y) {
+			errs.add(feature.pos, "%s at (%d,%d) is outside the warehouse (%d,%d)-(%d,%d)",
+				feature.kind, feature.x, feature.y, grid.OriginX, grid.OriginY, grid.MaxX(), grid.MaxY())
+			continue
+		}
+
+		cell := model.Cell{X: uint(feature.x), Y: uint(feature.y)}
+		if other, exists := occupied[cell]; exists {
+			errs.add(feature.pos, "%s at (%d,%d) overlaps %s at line %d, column %d",
+				feature.kind, cell.X, cell.Y, other.kind, other.pos.Line, other.pos.Column)
+			continue
+		}
+		occupied[cell] = feature
+
+		switch feature.kind {
+		case featureWall:
+			warehouseMap.Walls[cell] = true
+		case featureShelf:
+			warehouseMap.Shelves[cell] = true
+		case featureBlocked:
+			warehouseMap.Blocked[cell] = true
+		case featureCrateSlot:
+			warehouseMap.CrateSlots = append(warehouseMap.CrateSlots, cell)
+		case featureChargingDock:
+			warehouseMap.ChargingDocks = append(warehouseMap.ChargingDocks, cell)
+		case featureRobotHome:
+			if feature.robotID == "" {
+				errs.add(feature.pos, "robot home at (%d,%d) has no robot id", cell.X, cell.Y)
+				continue
+			}
+			if other, exists := homes[feature.robotID]; exists {
+				errs.add(feature.pos, "robot %q already has a home at line %d, column %d",
+					feature.robotID, other.pos.Line, other.pos.Column)
+				continue
+			}
+			homes[feature.robotID] = feature
+			warehouseMap.RobotHomes = append(warehouseMap.RobotHomes, model.RobotHome{RobotID: feature.robotID, Cell: cell})
+		}
+	}
+
+	zoneNames := make(map[string]rawZone)
+	for _, zone := range layout.zones {
+		if zone.name == "" {
+			errs.add(zone.pos, "no-go zone has no name")
+			continue
+		}
+		if other, exists := zoneNames[zone.name]; exists {
+			errs.add(zone.pos, "no-go zone %q is already defined at line %d, column %d",
+				zone.name, other.pos.Line, other.pos.Column)
+			continue
+		}
+		zoneNames[zone.name] = zone
+
+		if zone.minX > zone.maxX || zone.minY > zone.maxY {
+			errs.add(zone.pos, "no-go zone %q has min corner (%d,%d) above max corner (%d,%d)",
+				zone.name, zone.minX, zone.minY, zone.maxX, zone.maxY)
+			continue
+		}
+		if !grid.Contains(zone.minX, zone.minY) || !grid.Contains(zone.maxX, zone.maxY) {
+			errs.add(zone.pos, "no-go zone %q (%d,%d)-(%d,%d) is outside the warehouse (%d,%d)-(%d,%d)",
+				zone.name, zone.minX, zone.minY, zone.maxX, zone.maxY,
+				grid.OriginX, grid.OriginY, grid.MaxX(), grid.MaxY())
+			continue
+		}
+
+		noGoZone := model.NoGoZone{
+			Name: zone.name,
+			Min:  model.Cell{X: uint(zone.minX), Y: uint(zone.minY)},
+			Max:  model.Cell{X: uint(zone.maxX), Y: uint(zone.maxY)},
+		}
+		for cell, feature := range occupied {
+			switch feature.kind {
+			case featureCrateSlot, featureChargingDock, featureRobotHome:
+				if noGoZone.Contains(int(cell.X), int(cell.Y)) {
+					errs.add(zone.pos, "no-go zone %q covers %s at (%d,%d)", zone.name, feature.kind, cell.X, cell.Y)
+				}
+			}
+		}
+		warehouseMap.Zones = append(warehouseMap.Zones, noGoZone)
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Pos.Line != errs[j].Pos.Line {
+				return errs[i].Pos.Line < errs[j].Pos.Line
+			}
+			return errs[i].Pos.Column < errs[j].Pos.Column
+		})
+		return nil, errs
+	}
+
+	sort.Slice(warehouseMap.RobotHomes, func(i, j int) bool {
+		return warehouseMap.RobotHomes[i].RobotID < warehouseMap.RobotHomes[j].RobotID
+	})
+
+	return warehouseMap, nil
+}
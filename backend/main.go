@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"warehouse-robots/backend/api/constant"
 	"warehouse-robots/backend/api/middleware"
+	"warehouse-robots/backend/api/model"
 	"warehouse-robots/backend/binder"
 	"warehouse-robots/backend/config"
 )
@@ -23,8 +28,24 @@ func main() {
 	mux := http.NewServeMux()
 
 	mux.HandleFunc(constant.RouteCreateTask, container.CreateTaskController.Handle)
+	mux.HandleFunc(constant.RoutePreviewTask, container.PreviewTaskController.Handle)
+	mux.HandleFunc(constant.RouteBatchMove, container.BatchMoveController.Handle)
+	mux.HandleFunc(constant.RouteFleetTelemetry, container.FleetTelemetryController.Handle)
 	mux.HandleFunc(constant.RouteGetTaskById, container.RetrieveTaskController.Handle)
 	mux.HandleFunc(constant.RouteDeleteTaskById, container.CancelTaskController.Handle)
+	mux.HandleFunc(constant.RouteTaskEvents, container.TaskEventsController.Handle)
+	mux.HandleFunc(constant.RouteTaskTrajectory, container.RetrieveTaskTrajectoryController.Handle)
+	mux.HandleFunc(constant.RouteGetTaskMonitors, container.RetrieveTaskMonitorsController.Handle)
+	mux.HandleFunc(constant.RouteGetRobots, container.RetrieveRobotsController.Handle)
+	mux.HandleFunc(constant.RouteGetRobotById, container.RetrieveRobotController.Handle)
+	mux.HandleFunc(constant.RouteRegisterRobot, container.RegisterRobotController.Handle)
+	mux.HandleFunc(constant.RouteDecommissionRobot, container.DecommissionRobotController.Handle)
+	mux.HandleFunc(constant.RouteRegisterWebhook, container.RegisterWebhookController.Handle)
+	mux.HandleFunc(constant.RouteDeleteWebhook, container.DeleteWebhookController.Handle)
+	mux.HandleFunc(constant.RouteWebhookDeliveries, container.RetrieveWebhookDeliveriesController.Handle)
+	mux.HandleFunc(constant.RouteGetWarehouse, container.RetrieveWarehouseController.Handle)
+	mux.HandleFunc(constant.RouteGetCrates, container.RetrieveCratesController.Handle)
+	mux.HandleFunc(constant.RouteGetCell, container.RetrieveCellController.Handle)
 
 	// Apply middleware stack with configuration
 	handler := middleware.Chain(mux,
@@ -35,8 +56,73 @@ func main() {
 
 	// Start server with configured address
 	address := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("Starting server on %s", address)
-	if err := http.ListenAndServe(address, handler); err != nil {
+	server := &http.Server{Addr: address, Handler: handler}
+
+	// SIGINT or SIGTERM starts a graceful shutdown; a second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Starting server on %s", address)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Server failed to start: %v", err)
+	case <-ctx.Done():
+		stop()
+	}
+
+	shutdown(server, container, cfg.Shutdown.WithDefaults())
+}
+
+// shutdown refuses new tasks and winds down the running ones while the server
+// still answers status requests and event streams, then drains the in-flight
+// HTTP requests, stops the background workers, closes the task store and logs
+// what was left running.
+func shutdown(server *http.Server, container *binder.Container, cfg config.ShutdownConfig) {
+	log.Printf("Shutting down: refusing new tasks, running tasks: %s (up to %s)", cfg.TaskPolicy, cfg.TaskTimeout)
+	container.TaskDrainService.StopAcceptingTasks()
+
+	taskCtx, cancelTasks := context.WithTimeout(context.Background(), cfg.TaskTimeout)
+	report, err := container.TaskDrainService.DrainTasks(taskCtx, cfg.TaskPolicy)
+	cancelTasks()
+	if err != nil {
+		log.Printf("Shutdown: draining tasks failed: %v", err)
+	}
+
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), cfg.HTTPTimeout)
+	defer cancelHTTP()
+	if err := server.Shutdown(httpCtx); err != nil {
+		log.Printf("Shutdown: closing the HTTP connections still open: %v", err)
+		_ = server.Close()
+	}
+
+	workerCtx, cancelWorkers := context.WithTimeout(context.Background(), cfg.HTTPTimeout)
+	defer cancelWorkers()
+	if err := container.Shutdown(workerCtx); err != nil {
+		log.Printf("Shutdown: %v", err)
+	}
+
+	logDrainReport(report)
+}
+
+// logDrainReport prints what became of the tasks that were running at shutdown.
+func logDrainReport(report *model.TaskDrainReport) {
+	if report == nil {
+		log.Printf("Shutdown complete; the running tasks are unknown")
+		return
+	}
+
+	log.Printf("Shutdown complete: %d task(s) finished, %d cancelled, %d left running",
+		report.Finished, len(report.Cancelled), len(report.Running))
+	for _, task := range report.Running {
+		at := "not started"
+		if position := task.CurrentPosition; position != nil {
+			at = fmt.Sprintf("at (%d,%d)", position.X, position.Y)
+		}
+		log.Printf("  left running: task %s on robot %s, %s", task.TaskID, task.RobotID, at)
 	}
 }
@@ -1,15 +1,28 @@
 package test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+	"warehouse-robots/backend/api/constant"
+	"warehouse-robots/backend/api/dao"
 	"warehouse-robots/backend/api/dtos"
+	"warehouse-robots/backend/api/manager"
+	"warehouse-robots/backend/api/middleware"
+	"warehouse-robots/backend/api/model"
 	"warehouse-robots/backend/binder"
 	"warehouse-robots/backend/config"
+
+	"github.com/gorilla/websocket"
 )
 
 func TestIntegration_CreateTask_HappyFlow(t *testing.T) {
@@ -26,8 +39,8 @@ func TestIntegration_CreateTask_HappyFlow(t *testing.T) {
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/api/robots/0/tasks", bytes.NewBuffer(jsonBody))
-	req.SetPathValue("robotId", "0")
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
 	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
@@ -49,7 +62,7 @@ func TestIntegration_CreateTask_HappyFlow(t *testing.T) {
 		t.Error("Expected task ID to be set")
 	}
 
-	if createResponse.RobotID != "0" {
+	if createResponse.RobotID != "robot-1" {
 		t.Errorf("Expected robot ID '0', got '%s'", createResponse.RobotID)
 	}
 
@@ -107,8 +120,8 @@ func TestIntegration_CreateTask_BoundaryViolation(t *testing.T) {
 	}
 	jsonBody, _ := json.Marshal(requestBody)
 
-	req := httptest.NewRequest("POST", "/api/robots/0/tasks", bytes.NewBuffer(jsonBody))
-	req.SetPathValue("robotId", "0")
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
 	req.Header.Set("Content-Type", "application/json")
 
 	w := httptest.NewRecorder()
@@ -191,8 +204,8 @@ func TestIntegration_CreateTask_InvalidCommands(t *testing.T) {
 			requestBody := dtos.CreateTaskRequest{Commands: tc.commands}
 			jsonBody, _ := json.Marshal(requestBody)
 
-			req := httptest.NewRequest("POST", "/api/robots/0/tasks", bytes.NewBuffer(jsonBody))
-			req.SetPathValue("robotId", "0")
+			req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+			req.SetPathValue("robotId", "robot-1")
 			req.Header.Set("Content-Type", "application/json")
 
 			w := httptest.NewRecorder()
@@ -277,42 +290,1582 @@ func TestIntegration_CreateTask_RobotBusy(t *testing.T) {
 
 	container := binder.NewContainer(cfg)
 
-	// Create first task
-	requestBody1 := dtos.CreateTaskRequest{Commands: "NN"}
-	jsonBody1, _ := json.Marshal(requestBody1)
+	// Fill the robot's queue, each task starting where the previous one will end
+	for i := 0; i < 5; i++ {
+		requestBody := dtos.CreateTaskRequest{Commands: "N"}
+		jsonBody, _ := json.Marshal(requestBody)
+
+		req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", "robot-1")
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Errorf("Expected task %d to be queued, got %d: %s", i+1, w.Code, w.Body.String())
+			return
+		}
+	}
+
+	// One more task than the queue can hold (should be rejected while the robot is busy)
+	requestBody := dtos.CreateTaskRequest{Commands: "N"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status code %d for full queue, got %d", http.StatusTooManyRequests, w.Code)
+		t.Errorf("Response: %s", w.Body.String())
+	}
+}
+
+func TestIntegration_CreateTask_QueuedFromProjectedEnd(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	testCases := []struct {
+		commands string
+		expected int
+	}{
+		{"NN", http.StatusCreated},   // (0,0) -> (0,2)
+		{"SS", http.StatusCreated},   // queued, validated from (0,2) -> (0,0)
+		{"S", http.StatusBadRequest}, // validated from (0,0), leaves the warehouse
+		{"E9", http.StatusCreated},   // (0,0) -> (9,0)
+		{"E", http.StatusBadRequest}, // validated from (9,0), leaves the warehouse
+	}
+
+	var taskIDs []string
+	for _, tc := range testCases {
+		requestBody := dtos.CreateTaskRequest{Commands: tc.commands}
+		jsonBody, _ := json.Marshal(requestBody)
+
+		req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", "robot-1")
+		req.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+
+		if w.Code != tc.expected {
+			t.Errorf("Expected status code %d for %q, got %d: %s", tc.expected, tc.commands, w.Code, w.Body.String())
+			return
+		}
+
+		if w.Code == http.StatusCreated {
+			var taskInfo dtos.TaskInfo
+			if err := json.Unmarshal(w.Body.Bytes(), &taskInfo); err != nil {
+				t.Errorf("Failed to unmarshal create response: %v", err)
+				return
+			}
+			taskIDs = append(taskIDs, taskInfo.TaskID)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/robots/robot-1", nil)
+	req.SetPathValue("robotId", "robot-1")
+
+	w := httptest.NewRecorder()
+	container.RetrieveRobotController.Handle(w, req)
+
+	var robotInfo dtos.RobotInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &robotInfo); err != nil {
+		t.Errorf("Failed to unmarshal robot response: %v", err)
+		return
+	}
+
+	if robotInfo.ActiveTaskID != taskIDs[0] {
+		t.Errorf("Expected active task '%s', got '%s'", taskIDs[0], robotInfo.ActiveTaskID)
+	}
+
+	if len(robotInfo.QueuedTaskIDs) != 2 || robotInfo.QueuedTaskIDs[0] != taskIDs[1] || robotInfo.QueuedTaskIDs[1] != taskIDs[2] {
+		t.Errorf("Expected queued tasks %v, got %v", taskIDs[1:], robotInfo.QueuedTaskIDs)
+	}
+}
+
+func TestIntegration_GetRobot_ActiveTask(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	requestBody := dtos.CreateTaskRequest{Commands: "NN"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
+
+	if createW.Code != http.StatusCreated {
+		t.Errorf("Expected task creation to succeed, got %d", createW.Code)
+		return
+	}
+
+	var createResponse dtos.TaskInfo
+	if err := json.Unmarshal(createW.Body.Bytes(), &createResponse); err != nil {
+		t.Errorf("Failed to unmarshal create response: %v", err)
+		return
+	}
+
+	req := httptest.NewRequest("GET", "/api/robots/robot-1", nil)
+	req.SetPathValue("robotId", "robot-1")
+
+	w := httptest.NewRecorder()
+	container.RetrieveRobotController.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		return
+	}
+
+	var robotInfo dtos.RobotInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &robotInfo); err != nil {
+		t.Errorf("Failed to unmarshal robot response: %v", err)
+		return
+	}
+
+	if robotInfo.ID != "robot-1" {
+		t.Errorf("Expected robot ID '0', got '%s'", robotInfo.ID)
+	}
+
+	if robotInfo.ActiveTaskID != createResponse.TaskID {
+		t.Errorf("Expected active task '%s', got '%s'", createResponse.TaskID, robotInfo.ActiveTaskID)
+	}
+}
+
+func TestIntegration_GetRobots(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	req := httptest.NewRequest("GET", "/api/robots", nil)
+
+	w := httptest.NewRecorder()
+	container.RetrieveRobotsController.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		return
+	}
+
+	var robotInfos []dtos.RobotInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &robotInfos); err != nil {
+		t.Errorf("Failed to unmarshal robots response: %v", err)
+		return
+	}
+
+	if len(robotInfos) != 1 {
+		t.Errorf("Expected 1 robot, got %d", len(robotInfos))
+		return
+	}
+
+	if robotInfos[0].ActiveTaskID != "" {
+		t.Errorf("Expected idle robot, got active task '%s'", robotInfos[0].ActiveTaskID)
+	}
+}
+
+func TestIntegration_GetRobot_NotFound(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	req := httptest.NewRequest("GET", "/api/robots/999", nil)
+	req.SetPathValue("robotId", "999")
+
+	w := httptest.NewRecorder()
+	container.RetrieveRobotController.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestIntegration_CrateInventory(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Warehouse: config.WarehouseConfig{
+			Crates: []config.CrateSeed{{X: 2, Y: 2, ID: "crate-1", SKU: "SKU-001"}},
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	req := httptest.NewRequest("GET", "/api/warehouse/crates", nil)
+
+	w := httptest.NewRecorder()
+	container.RetrieveCratesController.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		return
+	}
+
+	var crateInfos []dtos.CrateInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &crateInfos); err != nil {
+		t.Errorf("Failed to unmarshal crates response: %v", err)
+		return
+	}
+
+	if len(crateInfos) != 1 || crateInfos[0].ID != "crate-1" {
+		t.Errorf("Expected seeded crate 'crate-1', got %+v", crateInfos)
+	}
+
+	cellReq := httptest.NewRequest("GET", "/api/warehouse/cells/2/2", nil)
+	cellReq.SetPathValue("x", "2")
+	cellReq.SetPathValue("y", "2")
+
+	cellW := httptest.NewRecorder()
+	container.RetrieveCellController.Handle(cellW, cellReq)
+
+	if cellW.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, cellW.Code)
+		return
+	}
+
+	var cellInfo dtos.CellInfo
+	if err := json.Unmarshal(cellW.Body.Bytes(), &cellInfo); err != nil {
+		t.Errorf("Failed to unmarshal cell response: %v", err)
+		return
+	}
+
+	if cellInfo.Crate == nil || cellInfo.Crate.SKU != "SKU-001" {
+		t.Errorf("Expected crate with SKU 'SKU-001' on cell, got %+v", cellInfo.Crate)
+	}
+
+	// The mock robot already carries a crate, so grabbing again is rejected
+	requestBody := dtos.CreateTaskRequest{Commands: "NG"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
+
+	if createW.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for impossible grab, got %d", http.StatusBadRequest, createW.Code)
+	}
+}
+
+func TestIntegration_ConfiguredWarehouseSize(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Warehouse: config.WarehouseConfig{
+			Width:   3,
+			Height:  4,
+			OriginX: 1,
+			OriginY: 1,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	req := httptest.NewRequest("GET", "/api/warehouse", nil)
+
+	w := httptest.NewRecorder()
+	container.RetrieveWarehouseController.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		return
+	}
+
+	var warehouseInfo dtos.WarehouseInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &warehouseInfo); err != nil {
+		t.Errorf("Failed to unmarshal warehouse response: %v", err)
+		return
+	}
+
+	if warehouseInfo.MaxX != 3 || warehouseInfo.MaxY != 4 {
+		t.Errorf("Expected max (3,4), got (%d,%d)", warehouseInfo.MaxX, warehouseInfo.MaxY)
+	}
+
+	// The robot starts at the origin (1,1), so four moves north leave a 4 cell tall floor
+	requestBody := dtos.CreateTaskRequest{Commands: "NNNN"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
+
+	if createW.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for boundary violation, got %d", http.StatusBadRequest, createW.Code)
+	}
+}
+
+func TestIntegration_CreateTask_Goto(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.DefaultGrid())
+	warehouseMap.Blocked[model.Cell{X: 1, Y: 0}] = true
+
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Warehouse: config.WarehouseConfig{
+			Map: warehouseMap,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// (1,0) is blocked, so the planner has to go around it
+	requestBody := dtos.CreateTaskRequest{Goto: &dtos.CellRef{X: 2, Y: 0}}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
+
+	if createW.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusCreated, createW.Code, createW.Body.String())
+		return
+	}
+
+	var createResponse dtos.TaskInfo
+	if err := json.Unmarshal(createW.Body.Bytes(), &createResponse); err != nil {
+		t.Errorf("Failed to unmarshal create response: %v", err)
+		return
+	}
+
+	if createResponse.Commands != "NEES" {
+		t.Errorf("Expected planned commands 'NEES', got '%s'", createResponse.Commands)
+	}
+
+	getReq := httptest.NewRequest("GET", "/api/tasks/"+createResponse.TaskID, nil)
+	getReq.SetPathValue("taskId", createResponse.TaskID)
+
+	getW := httptest.NewRecorder()
+	container.RetrieveTaskController.Handle(getW, getReq)
+
+	var taskInfo dtos.TaskInfo
+	if err := json.Unmarshal(getW.Body.Bytes(), &taskInfo); err != nil {
+		t.Errorf("Failed to unmarshal task response: %v", err)
+		return
+	}
+
+	if taskInfo.Goto == nil || taskInfo.Goto.X != 2 || taskInfo.Goto.Y != 0 {
+		t.Errorf("Expected goto target (2,0), got %+v", taskInfo.Goto)
+	}
+
+	if taskInfo.Commands != "NEES" {
+		t.Errorf("Expected stored commands 'NEES', got '%s'", taskInfo.Commands)
+	}
+}
+
+func TestIntegration_CreateTask_GotoNoPath(t *testing.T) {
+	warehouseMap := model.NewWarehouseMap(model.DefaultGrid())
+	warehouseMap.Walls[model.Cell{X: 1, Y: 0}] = true
+	warehouseMap.Walls[model.Cell{X: 0, Y: 1}] = true
+
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Warehouse: config.WarehouseConfig{
+			Map: warehouseMap,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// The robot starts boxed in at (0,0)
+	requestBody := dtos.CreateTaskRequest{Goto: &dtos.CellRef{X: 5, Y: 5}}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
 
-	req1 := httptest.NewRequest("POST", "/api/robots/0/tasks", bytes.NewBuffer(jsonBody1))
-	req1.SetPathValue("robotId", "0")
-	req1.Header.Set("Content-Type", "application/json")
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
 
-	w1 := httptest.NewRecorder()
-	container.CreateTaskController.Handle(w1, req1)
+	if createW.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, createW.Code)
+		return
+	}
 
-	if w1.Code != http.StatusCreated {
-		t.Errorf("Expected first task creation to succeed, got %d", w1.Code)
+	var errorResponse dtos.ErrorResponse
+	if err := json.Unmarshal(createW.Body.Bytes(), &errorResponse); err != nil {
+		t.Errorf("Failed to unmarshal error response: %v", err)
 		return
 	}
 
-	// Try to create second task immediately (should be rejected if robot is busy)
-	requestBody2 := dtos.CreateTaskRequest{Commands: "SS"}
-	jsonBody2, _ := json.Marshal(requestBody2)
+	if errorResponse.Code != "NO_PATH" {
+		t.Errorf("Expected error code 'NO_PATH', got '%s'", errorResponse.Code)
+	}
+}
+
+func TestIntegration_CreateTask_CompactCommands(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	requestBody := dtos.CreateTaskRequest{Commands: "N2, (E N)2"}
+	jsonBody, _ := json.Marshal(requestBody)
 
-	req2 := httptest.NewRequest("POST", "/api/robots/0/tasks", bytes.NewBuffer(jsonBody2))
-	req2.SetPathValue("robotId", "0")
-	req2.Header.Set("Content-Type", "application/json")
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
 
-	w2 := httptest.NewRecorder()
-	container.CreateTaskController.Handle(w2, req2)
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
 
-	// This should fail with a conflict, bad request, or too many requests
-	if w2.Code == http.StatusCreated {
-		t.Error("Expected second task creation to fail due to robot being busy")
+	if createW.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusCreated, createW.Code, createW.Body.String())
 		return
 	}
 
-	if w2.Code != http.StatusBadRequest && w2.Code != http.StatusConflict && w2.Code != http.StatusTooManyRequests {
-		t.Errorf("Expected status code %d, %d, or %d for busy robot, got %d",
-			http.StatusBadRequest, http.StatusConflict, http.StatusTooManyRequests, w2.Code)
-		t.Errorf("Response: %s", w2.Body.String())
+	var createResponse dtos.TaskInfo
+	if err := json.Unmarshal(createW.Body.Bytes(), &createResponse); err != nil {
+		t.Errorf("Failed to unmarshal create response: %v", err)
+		return
+	}
+
+	if createResponse.Commands != "NNENEN" {
+		t.Errorf("Expected expanded commands 'NNENEN', got '%s'", createResponse.Commands)
+	}
+}
+
+func TestIntegration_CreateTask_CommandSyntaxError(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	requestBody := dtos.CreateTaskRequest{Commands: "N2(E"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	createReq := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	createReq.SetPathValue("robotId", "robot-1")
+	createReq.Header.Set("Content-Type", "application/json")
+
+	createW := httptest.NewRecorder()
+	container.CreateTaskController.Handle(createW, createReq)
+
+	if createW.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, createW.Code)
+		return
+	}
+
+	var errorResponse dtos.ErrorResponse
+	if err := json.Unmarshal(createW.Body.Bytes(), &errorResponse); err != nil {
+		t.Errorf("Failed to unmarshal error response: %v", err)
+		return
+	}
+
+	if errorResponse.Code != "VALIDATION_ERROR" {
+		t.Errorf("Expected error code 'VALIDATION_ERROR', got '%s'", errorResponse.Code)
+	}
+
+	if errorResponse.Details != "position 3: unclosed '('" {
+		t.Errorf("Expected details to point at the open group, got '%s'", errorResponse.Details)
+	}
+}
+
+func TestIntegration_PreviewTask(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	requestBody := dtos.CreateTaskRequest{Commands: "N2E"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks:preview", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.PreviewTaskController.Handle(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		return
+	}
+
+	var preview dtos.TaskPreview
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Errorf("Failed to unmarshal preview response: %v", err)
+		return
+	}
+
+	if preview.Commands != "NNE" || preview.PathLength != 3 || len(preview.Trajectory) != 4 {
+		t.Errorf("Expected 3 step preview of 'NNE', got %+v", preview)
+	}
+
+	if preview.FinalPosition.X != 1 || preview.FinalPosition.Y != 2 {
+		t.Errorf("Expected final position (1,2), got %+v", preview.FinalPosition)
+	}
+
+	if preview.EstimatedDurationMs != 6000 {
+		t.Errorf("Expected estimated duration 6000ms, got %d", preview.EstimatedDurationMs)
+	}
+
+	// A preview must not enqueue anything, so the robot stays idle
+	robotReq := httptest.NewRequest("GET", "/api/robots/robot-1", nil)
+	robotReq.SetPathValue("robotId", "robot-1")
+
+	robotW := httptest.NewRecorder()
+	container.RetrieveRobotController.Handle(robotW, robotReq)
+
+	var robotInfo dtos.RobotInfo
+	if err := json.Unmarshal(robotW.Body.Bytes(), &robotInfo); err != nil {
+		t.Errorf("Failed to unmarshal robot response: %v", err)
+		return
+	}
+
+	if robotInfo.ActiveTaskID != "" {
+		t.Errorf("Expected no active task after preview, got '%s'", robotInfo.ActiveTaskID)
+	}
+}
+
+func TestIntegration_PreviewTask_BoundaryViolation(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	requestBody := dtos.CreateTaskRequest{Commands: "S"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks:preview", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.PreviewTaskController.Handle(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestIntegration_CreateTask_PathConflict(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// Another robot is parked at (0,2)
+	container.ReservationTable.Park("other", model.Cell{X: 0, Y: 2})
+
+	requestBody := dtos.CreateTaskRequest{Commands: "NNN"}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+		return
+	}
+
+	var errorResponse dtos.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errorResponse); err != nil {
+		t.Errorf("Failed to unmarshal error response: %v", err)
+		return
+	}
+
+	if errorResponse.Code != "PATH_CONFLICT" {
+		t.Errorf("Expected error code 'PATH_CONFLICT', got '%s'", errorResponse.Code)
+	}
+
+	if errorResponse.Details != "step 1 enters robot other's reserved cell at (0,2)" {
+		t.Errorf("Unexpected details '%s'", errorResponse.Details)
+	}
+
+	// Going around the parked robot is fine
+	requestBody = dtos.CreateTaskRequest{Commands: "ENN"}
+	jsonBody, _ = json.Marshal(requestBody)
+
+	req = httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	req.Header.Set("Content-Type", "application/json")
+
+	w = httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+func TestIntegration_BatchMove(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// Another robot is parked right in front of robot-1
+	container.ReservationTable.Park("other", model.Cell{X: 1, Y: 0})
+
+	requestBody := dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "robot-1", Goto: dtos.CellRef{X: 2, Y: 0}},
+	}}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/fleet/moves", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.BatchMoveController.Handle(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var plan dtos.BatchMovePlan
+	if err := json.Unmarshal(w.Body.Bytes(), &plan); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(plan.Tasks) != 1 || plan.Tasks[0].Commands != "NEES" || plan.Tasks[0].TaskID == "" {
+		t.Errorf("Expected one task driving NEES around the parked robot, got %+v", plan.Tasks)
+	}
+	if plan.Makespan != 4 || plan.EstimatedDurationMs != 8000 {
+		t.Errorf("Expected makespan 4 (8000ms), got %d (%dms)", plan.Makespan, plan.EstimatedDurationMs)
+	}
+
+	task, err := container.TaskRepository.GetById(plan.Tasks[0].TaskID)
+	if err != nil || task.Target == nil || task.Target.X != 2 || task.Target.Y != 0 {
+		t.Errorf("Expected the task to be stored as a goto (2,0), got %+v (%v)", task, err)
+	}
+}
+
+func TestIntegration_BatchMove_Atomic(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// The second robot does not exist, so the first must not move either
+	requestBody := dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "robot-1", Goto: dtos.CellRef{X: 2, Y: 2}},
+		{RobotID: "7", Goto: dtos.CellRef{X: 3, Y: 3}},
+	}}
+	jsonBody, _ := json.Marshal(requestBody)
+
+	req := httptest.NewRequest("POST", "/api/fleet/moves", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	container.BatchMoveController.Handle(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	tasks, _ := container.TaskRepository.GetByRobotId("robot-1")
+	if len(tasks) != 0 {
+		t.Errorf("Expected no task for robot-1, got %d", len(tasks))
+	}
+	if len(container.ReservationTable.Reservations()) != 0 {
+		t.Errorf("Expected no reservations, got %+v", container.ReservationTable.Reservations())
+	}
+}
+
+func TestIntegration_MockFleet(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+			Robots: []config.RobotSeed{
+				{ID: "a", X: 0, Y: 0, HasCrate: true},
+				{ID: "b", X: 1, Y: 0},
+			},
+		},
+		Warehouse: config.WarehouseConfig{Width: 10, Height: 10},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected a valid fleet, got %v", err)
+	}
+
+	container := binder.NewContainer(cfg)
+
+	req := httptest.NewRequest("GET", "/api/robots", nil)
+	w := httptest.NewRecorder()
+	container.RetrieveRobotsController.Handle(w, req)
+
+	var robotInfos []dtos.RobotInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &robotInfos); err != nil {
+		t.Fatalf("Failed to unmarshal robots response: %v", err)
+	}
+	if len(robotInfos) != 2 || robotInfos[0].ID != "a" || robotInfos[1].ID != "b" {
+		t.Fatalf("Expected robots a and b by their serials, got %+v", robotInfos)
+	}
+	if robotInfos[1].Position.X != 1 || !robotInfos[0].Position.HasCrate {
+		t.Fatalf("Expected robots at (0,0) with a crate and (1,0), got %+v", robotInfos)
+	}
+
+	// Robot b cannot drive into robot a
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "W"})
+	req = httptest.NewRequest("POST", "/api/robots/b/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "b")
+	w = httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	// Swapping places is planned around each other
+	jsonBody, _ = json.Marshal(dtos.BatchMoveRequest{Moves: []dtos.BatchMove{
+		{RobotID: "a", Goto: dtos.CellRef{X: 1, Y: 0}},
+		{RobotID: "b", Goto: dtos.CellRef{X: 0, Y: 0}},
+	}})
+	req = httptest.NewRequest("POST", "/api/fleet/moves", bytes.NewBuffer(jsonBody))
+	w = httptest.NewRecorder()
+	container.BatchMoveController.Handle(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var plan dtos.BatchMovePlan
+	if err := json.Unmarshal(w.Body.Bytes(), &plan); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if plan.Tasks[0].TaskID != "task_a_1" || plan.Tasks[1].TaskID != "task_b_1" {
+		t.Errorf("Expected one task per mock robot, got %+v", plan.Tasks)
+	}
+}
+
+func TestIntegration_MockFleet_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		robots []config.RobotSeed
+	}{
+		{"same_cell", []config.RobotSeed{{ID: "a"}, {ID: "b"}}},
+		{"same_id", []config.RobotSeed{{ID: "a"}, {ID: "a", X: 1}}},
+		{"outside_grid", []config.RobotSeed{{ID: "a", X: 99}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Robot:     config.RobotConfig{EnableMock: true, Robots: tt.robots},
+				Warehouse: config.WarehouseConfig{Width: 10, Height: 10},
+			}
+			if err := cfg.Validate(); err == nil {
+				t.Errorf("Expected an invalid fleet")
+			}
+		})
+	}
+}
+
+func TestIntegration_DecommissionAndRegisterRobot(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	decommission := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", "/api/robots/robot-1", nil)
+		req.SetPathValue("robotId", "robot-1")
+		w := httptest.NewRecorder()
+		container.DecommissionRobotController.Handle(w, req)
+		return w
+	}
+	register := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/robots", bytes.NewBufferString(`{"id":"robot-1"}`))
+		w := httptest.NewRecorder()
+		container.RegisterRobotController.Handle(w, req)
+		return w
+	}
+
+	// A robot with a pending task stays in service
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "N"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	if w := decommission(); w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d while busy, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	// Wait for the task to finish
+	time.Sleep(2500 * time.Millisecond)
+
+	if w := decommission(); w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/robots", nil)
+	w = httptest.NewRecorder()
+	container.RetrieveRobotsController.Handle(w, req)
+	if w.Body.String() != "[]\n" {
+		t.Errorf("Expected no robots in service, got %s", w.Body.String())
+	}
+
+	if w := register(); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if w := register(); w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d for a robot in service, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	// The robot comes back under the same ID, where it stopped
+	req = httptest.NewRequest("GET", "/api/robots/robot-1", nil)
+	req.SetPathValue("robotId", "robot-1")
+	w = httptest.NewRecorder()
+	container.RetrieveRobotController.Handle(w, req)
+
+	var robotInfo dtos.RobotInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &robotInfo); err != nil {
+		t.Fatalf("Failed to unmarshal robot response: %v", err)
+	}
+	if robotInfo.ID != "robot-1" || robotInfo.Position.Y != 1 {
+		t.Errorf("Expected robot-1 at (0,1), got %+v", robotInfo)
+	}
+}
+
+func TestIntegration_TaskEvents(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// stream through the middleware, which wraps the response writer
+	mux := http.NewServeMux()
+	mux.HandleFunc(constant.RouteTaskEvents, container.TaskEventsController.Handle)
+	server := httptest.NewServer(middleware.Chain(mux, middleware.LoggingMiddleware, middleware.JSONMiddleware))
+	defer server.Close()
+
+	type sseEvent struct {
+		id   string
+		name string
+		data dtos.TaskEvent
+	}
+	stream := func(taskID, lastEventID string) (int, []sseEvent) {
+		req, _ := http.NewRequest("GET", server.URL+"/api/tasks/"+taskID+"/events", nil)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Failed to open event stream: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+			t.Errorf("Expected Content-Type text/event-stream, got %q", contentType)
+		}
+
+		var events []sseEvent
+		var current sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				events = append(events, current)
+				current = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				current.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				current.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.data); err != nil {
+					t.Fatalf("Failed to unmarshal event data %q: %v", line, err)
+				}
+			}
+		}
+		return resp.StatusCode, events
+	}
+
+	if code, _ := stream("missing", ""); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown task, got %d", http.StatusNotFound, code)
+	}
+
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "N"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	// the stream follows the task live until it ends
+	code, events := stream(task.TaskID, "")
+	if code != http.StatusOK || len(events) < 3 {
+		t.Fatalf("Expected status, position and end events, got %d: %+v", code, events)
+	}
+	for i, event := range events {
+		if event.id != strconv.Itoa(i+1) || event.data.ID != int64(i+1) || event.data.TaskID != task.TaskID {
+			t.Errorf("Expected event %d of task %s, got %+v", i+1, task.TaskID, event)
+		}
+	}
+	if events[0].name != "status" || events[0].data.Status != dtos.TaskStatusPending {
+		t.Errorf("Expected the stream to open with the PENDING status, got %+v", events[0])
+	}
+	last := events[len(events)-1]
+	if last.name != "end" || last.data.Status != dtos.TaskStatusCompleted {
+		t.Errorf("Expected a COMPLETED end event, got %+v", last)
+	}
+	arrived := events[len(events)-2]
+	if arrived.name != "position" || arrived.data.Position == nil || arrived.data.Position.Y != 1 {
+		t.Errorf("Expected the last position at (0,1), got %+v", arrived)
+	}
+
+	// a client that reconnects only gets what it missed
+	code, resumed := stream(task.TaskID, "1")
+	if code != http.StatusOK || len(resumed) != len(events)-1 || resumed[0].id != "2" {
+		t.Errorf("Expected events 2..%d after resuming, got %d: %+v", len(events), code, resumed)
+	}
+
+	if code, _ := stream(task.TaskID, "abc"); code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an invalid Last-Event-ID, got %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestIntegration_FleetTelemetry(t *testing.T) {
+	cfg := &config.Config{
+		Warehouse: config.WarehouseConfig{Width: 10, Height: 10},
+		Robot: config.RobotConfig{
+			EnableMock: true,
+			Robots:     []config.RobotSeed{{ID: "a", X: 0, Y: 0}, {ID: "b", X: 5, Y: 0}},
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(constant.RouteFleetTelemetry, container.FleetTelemetryController.Handle)
+	server := httptest.NewServer(middleware.Chain(mux, middleware.LoggingMiddleware, middleware.JSONMiddleware))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/fleet/telemetry"
+
+	if _, resp, err := websocket.DefaultDialer.Dial(url+"?types=teleport", nil); err == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected an unknown event type to be rejected with %d, got %v", http.StatusBadRequest, resp)
+	}
+
+	// follow robot a's positions and task ends only
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?robots=a&types=position,end", nil)
+	if err != nil {
+		t.Fatalf("Failed to open telemetry socket: %v", err)
+	}
+	defer conn.Close()
+
+	for _, robotID := range []string{"a", "b"} {
+		jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "N"})
+		req := httptest.NewRequest("POST", "/api/robots/"+robotID+"/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", robotID)
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var events []dtos.TaskEvent
+	for {
+		var event dtos.TaskEvent
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("Failed to read telemetry after %+v: %v", events, err)
+		}
+		events = append(events, event)
+		if event.Type == "end" {
+			break
+		}
+	}
+
+	for _, event := range events {
+		if event.RobotID != "a" || (event.Type != "position" && event.Type != "end") {
+			t.Errorf("Expected only position and end events of robot a, got %+v", event)
+		}
+	}
+	last := events[len(events)-1]
+	if last.Status != dtos.TaskStatusCompleted {
+		t.Errorf("Expected robot a's task to complete, got %+v", last)
+	}
+	if arrived := events[len(events)-2]; arrived.Position == nil || arrived.Position.Y != 1 {
+		t.Errorf("Expected robot a's last position at (0,1), got %+v", arrived)
+	}
+
+	// an invalid filter is answered without closing the socket
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"types":["teleport"]}`)); err != nil {
+		t.Fatalf("Failed to send filter: %v", err)
+	}
+	var reply dtos.ErrorResponse
+	if err := conn.ReadJSON(&reply); err != nil || reply.Code != "VALIDATION_ERROR" {
+		t.Errorf("Expected a validation error reply, got %+v (%v)", reply, err)
+	}
+}
+
+func TestIntegration_Webhooks(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Webhook: config.WebhookConfig{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	// the receiver fails the first delivery, then accepts
+	payloads := make(chan model.WebhookPayload, 10)
+	var calls int
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got := r.Header.Get(manager.WebhookSignatureHeader); got != manager.Sign("s3cret", body) {
+			t.Errorf("Expected a valid signature, got %q", got)
+		}
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var payload model.WebhookPayload
+		_ = json.Unmarshal(body, &payload)
+		payloads <- payload
+	}))
+	defer receiver.Close()
+
+	register := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/webhooks", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		container.RegisterWebhookController.Handle(w, req)
+		return w
+	}
+	deliveries := func(webhookID string) (int, []dtos.WebhookDelivery) {
+		req := httptest.NewRequest("GET", "/api/webhooks/"+webhookID+"/deliveries", nil)
+		req.SetPathValue("webhookId", webhookID)
+		w := httptest.NewRecorder()
+		container.RetrieveWebhookDeliveriesController.Handle(w, req)
+		var result []dtos.WebhookDelivery
+		_ = json.Unmarshal(w.Body.Bytes(), &result)
+		return w.Code, result
+	}
+
+	if w := register(`{"url":"ftp://example.com"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for a non-http URL, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := register(`{"url":"` + receiver.URL + `","events":["task.exploded"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d for an unknown event, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w := register(`{"url":"` + receiver.URL + `","secret":"s3cret","events":["task.completed"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var webhook dtos.WebhookInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &webhook)
+
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "N"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w = httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	select {
+	case payload := <-payloads:
+		if payload.Event != "task.completed" || payload.TaskID != task.TaskID || payload.Status != model.TaskStatusCompleted {
+			t.Errorf("Expected task.completed for %s, got %+v", task.TaskID, payload)
+		}
+		if payload.FinalPosition == nil || payload.FinalPosition.Y != 1 {
+			t.Errorf("Expected the final position (0,1), got %+v", payload.FinalPosition)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the webhook to be notified")
+	}
+
+	// the log shows the failed attempt and the retry that got through, which
+	// is recorded once the receiver has answered
+	code, log := deliveries(webhook.ID)
+	for deadline := time.Now().Add(time.Second); len(log) < 2 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+		code, log = deliveries(webhook.ID)
+	}
+	if code != http.StatusOK || len(log) != 2 {
+		t.Fatalf("Expected 2 delivery attempts, got %d: %+v", code, log)
+	}
+	if log[0].Succeeded || log[0].StatusCode != http.StatusInternalServerError || !log[1].Succeeded || log[1].Attempt != 2 {
+		t.Errorf("Expected a failed attempt then a successful retry, got %+v", log)
+	}
+
+	// the log outlives the webhook
+	req = httptest.NewRequest("DELETE", "/api/webhooks/"+webhook.ID, nil)
+	req.SetPathValue("webhookId", webhook.ID)
+	w = httptest.NewRecorder()
+	container.DeleteWebhookController.Handle(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if code, log := deliveries(webhook.ID); code != http.StatusOK || len(log) != 2 {
+		t.Errorf("Expected the delivery log to remain, got %d: %+v", code, log)
+	}
+	if code, _ := deliveries("webhook_404"); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown webhook, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestIntegration_SQLiteTaskStore(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Storage: config.StorageConfig{
+			TaskStore:  constant.TaskStoreSQLite,
+			SQLitePath: filepath.Join(t.TempDir(), "tasks.db"),
+		},
+	}
+
+	create := func(container *binder.Container, commands string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: commands})
+		req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", "robot-1")
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+		return w
+	}
+
+	container := binder.NewContainer(cfg)
+	w := create(container, "NN")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(20 * time.Millisecond) {
+		stored, err := container.TaskRepository.GetById(task.TaskID)
+		if err == nil && stored.Status == model.TaskStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the task to complete, got %+v (%v)", stored, err)
+		}
+	}
+	_ = container.OutboxRelay.Shutdown(context.Background())
+	_ = container.TaskRepository.(io.Closer).Close()
+
+	// after a restart the mock robot is back at (0,0), but the stored history
+	// still plans the next task from (0,2), where the last one ended
+	restarted := binder.NewContainer(cfg)
+	defer restarted.TaskRepository.(io.Closer).Close()
+	defer restarted.OutboxRelay.Shutdown(context.Background())
+
+	if w := create(restarted, "SS"); w.Code != http.StatusCreated {
+		t.Errorf("Expected status code %d for a task planned from (0,2), got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+}
+
+func TestIntegration_TaskTrajectory(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	trajectory := func(taskID string) (int, dtos.TaskTrajectory) {
+		req := httptest.NewRequest("GET", "/api/tasks/"+taskID+"/trajectory", nil)
+		req.SetPathValue("taskId", taskID)
+		w := httptest.NewRecorder()
+		container.RetrieveTaskTrajectoryController.Handle(w, req)
+		var response dtos.TaskTrajectory
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	if code, _ := trajectory("missing"); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown task, got %d", http.StatusNotFound, code)
+	}
+
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "NN"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	var response dtos.TaskTrajectory
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(20 * time.Millisecond) {
+		var code int
+		code, response = trajectory(task.TaskID)
+		if code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+		}
+		if response.Status == dtos.TaskStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the task to complete, got %+v", response)
+		}
+	}
+
+	if response.TaskID != task.TaskID || response.RobotID != "robot-1" {
+		t.Errorf("Expected the trajectory of task %s on robot-1, got %+v", task.TaskID, response)
+	}
+	if len(response.Points) == 0 {
+		t.Fatal("Expected the recorded positions")
+	}
+	for i, point := range response.Points {
+		if point.Step != i {
+			t.Errorf("Expected point %d to be step %d, got %+v", i, i, point)
+		}
+		if i > 0 && point.At.Before(response.Points[i-1].At) {
+			t.Errorf("Expected points in time order, got %+v", response.Points)
+		}
+	}
+	if last := response.Points[len(response.Points)-1]; last.X != 0 || last.Y != 2 {
+		t.Errorf("Expected the robot to end at (0,2), got %+v", last)
+	}
+
+	if len(response.Transitions) != 2 ||
+		response.Transitions[0].Status != dtos.TaskStatusPending ||
+		response.Transitions[1].Status != dtos.TaskStatusCompleted {
+		t.Errorf("Expected PENDING then COMPLETED, got %+v", response.Transitions)
+	}
+}
+
+func TestIntegration_RecoverTasksAfterRestart(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Storage: config.StorageConfig{
+			TaskStore:  constant.TaskStoreSQLite,
+			SQLitePath: filepath.Join(t.TempDir(), "tasks.db"),
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "NNN"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	// the process goes away while the task is still running
+	_ = container.OutboxRelay.Shutdown(context.Background())
+	_ = container.TaskRepository.(io.Closer).Close()
+
+	// the restarted mock SDK knows nothing of the task and its robot is back at (0,0)
+	restarted := binder.NewContainer(cfg)
+	defer restarted.TaskRepository.(io.Closer).Close()
+	defer restarted.OutboxRelay.Shutdown(context.Background())
+
+	stored, err := restarted.TaskRepository.GetById(task.TaskID)
+	if err != nil {
+		t.Fatalf("Expected the task to survive the restart: %v", err)
+	}
+	if stored.Status != model.TaskStatusFailed || stored.Error != "orphaned after restart" {
+		t.Errorf("Expected the task to be FAILED as orphaned, got %s %q", stored.Status, stored.Error)
+	}
+	if stored.CurrentPosition == nil || stored.CurrentPosition.X != 0 || stored.CurrentPosition.Y != 0 {
+		t.Errorf("Expected the robot's real position (0,0), got %+v", stored.CurrentPosition)
+	}
+
+	// the next task is planned from where the robot really is, not from (0,3)
+	jsonBody, _ = json.Marshal(dtos.CreateTaskRequest{Commands: "E"})
+	req = httptest.NewRequest("POST", "/api/robots/robot-1/tasks:preview", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w = httptest.NewRecorder()
+	restarted.PreviewTaskController.Handle(w, req)
+	var preview dtos.TaskPreview
+	_ = json.Unmarshal(w.Body.Bytes(), &preview)
+	if w.Code != http.StatusOK || len(preview.Trajectory) == 0 || preview.Trajectory[0].Y != 0 {
+		t.Errorf("Expected the next task to start at (0,0), got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestIntegration_GracefulShutdown(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+		Storage: config.StorageConfig{
+			TaskStore:  constant.TaskStoreSQLite,
+			SQLitePath: filepath.Join(t.TempDir(), "tasks.db"),
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+	create := func(commands string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: commands})
+		req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", "robot-1")
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+		return w
+	}
+
+	w := create("N")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	container.TaskDrainService.StopAcceptingTasks()
+	if w := create("E"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d while shutting down, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	report, err := container.TaskDrainService.DrainTasks(ctx, constant.ShutdownTasksWait)
+	if err != nil {
+		t.Fatalf("Failed to drain tasks: %v", err)
+	}
+	if report.Finished != 1 || len(report.Running) != 0 {
+		t.Errorf("Expected the running task to finish, got %+v", report)
+	}
+	if stored, _ := container.TaskRepository.GetById(task.TaskID); stored.Status != model.TaskStatusCompleted {
+		t.Errorf("Expected the task to be COMPLETED, got %s", stored.Status)
+	}
+
+	if err := container.Shutdown(ctx); err != nil {
+		t.Fatalf("Failed to shut down: %v", err)
+	}
+	if _, err := container.TaskRepository.GetById(task.TaskID); err == nil {
+		t.Error("Expected the task store to be closed")
+	}
+
+	reopened, err := dao.NewSQLiteTaskRepository(cfg.Storage.SQLitePath)
+	if err != nil {
+		t.Fatalf("Failed to reopen the task store: %v", err)
+	}
+	defer reopened.Close()
+	if pending, _ := reopened.PendingOutbox(10); len(pending) != 0 {
+		t.Errorf("Expected the outbox to be relayed before exiting, got %d pending entries", len(pending))
+	}
+}
+
+func TestIntegration_TaskMonitors(t *testing.T) {
+	cfg := &config.Config{
+		Robot: config.RobotConfig{
+			EnableMock: true,
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	monitors := func() []dtos.TaskMonitorInfo {
+		req := httptest.NewRequest("GET", "/api/monitors", nil)
+		w := httptest.NewRecorder()
+		container.RetrieveTaskMonitorsController.Handle(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response []dtos.TaskMonitorInfo
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return response
+	}
+
+	if list := monitors(); len(list) != 0 {
+		t.Errorf("Expected no monitors before any task, got %+v", list)
+	}
+
+	jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: "NN"})
+	req := httptest.NewRequest("POST", "/api/robots/robot-1/tasks", bytes.NewBuffer(jsonBody))
+	req.SetPathValue("robotId", "robot-1")
+	w := httptest.NewRecorder()
+	container.CreateTaskController.Handle(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	var task dtos.TaskInfo
+	_ = json.Unmarshal(w.Body.Bytes(), &task)
+
+	list := monitors()
+	if len(list) != 1 || list[0].TaskID != task.TaskID || list[0].RobotID != "robot-1" {
+		t.Fatalf("Expected the monitor of task %s on robot-1, got %+v", task.TaskID, list)
+	}
+	if list[0].AgeMs < 0 || list[0].RemainingTimeoutMs <= 0 ||
+		list[0].RemainingTimeoutMs > constant.TaskMonitorTimeout.Milliseconds() {
+		t.Errorf("Expected a fresh monitor with its timeout ahead, got %+v", list[0])
+	}
+
+	// the cancel service stops the monitor the create service started
+	req = httptest.NewRequest("DELETE", "/api/tasks/"+task.TaskID, nil)
+	req.SetPathValue("taskId", task.TaskID)
+	w = httptest.NewRecorder()
+	container.CancelTaskController.Handle(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if list := monitors(); len(list) != 0 {
+		t.Errorf("Expected no monitors after cancelling, got %+v", list)
+	}
+
+	// nothing is left watching the robot, so its next report does not bring the task back
+	time.Sleep(constant.RobotStepDuration + 500*time.Millisecond)
+	if stored, _ := container.TaskRepository.GetById(task.TaskID); stored.Status != model.TaskStatusCancelled {
+		t.Errorf("Expected the task to stay CANCELLED, got %s", stored.Status)
+	}
+}
+
+func TestIntegration_CancelRunningTask(t *testing.T) {
+	cfg := &config.Config{
+		Warehouse: config.WarehouseConfig{Width: 10, Height: 10},
+		Robot: config.RobotConfig{
+			EnableMock: true,
+			Robots:     []config.RobotSeed{{ID: "a", X: 0, Y: 0}},
+		},
+	}
+
+	container := binder.NewContainer(cfg)
+
+	createTask := func(commands string) dtos.TaskInfo {
+		jsonBody, _ := json.Marshal(dtos.CreateTaskRequest{Commands: commands})
+		req := httptest.NewRequest("POST", "/api/robots/a/tasks", bytes.NewBuffer(jsonBody))
+		req.SetPathValue("robotId", "a")
+		w := httptest.NewRecorder()
+		container.CreateTaskController.Handle(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var task dtos.TaskInfo
+		_ = json.Unmarshal(w.Body.Bytes(), &task)
+		return task
+	}
+	waitFor := func(taskID string, done func(*model.Task) bool) *model.Task {
+		deadline := time.Now().Add(3 * constant.RobotStepDuration)
+		for {
+			stored, _ := container.TaskRepository.GetById(taskID)
+			if done(stored) {
+				return stored
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for task %s, got %+v", taskID, stored)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	running := createTask("NNNN")
+	queued := createTask("E") // planned from (0,4)
+
+	waitFor(running.TaskID, func(task *model.Task) bool {
+		return task.CurrentPosition != nil && task.CurrentPosition.Y >= 1
+	})
+
+	req := httptest.NewRequest("DELETE", "/api/tasks/"+running.TaskID, nil)
+	req.SetPathValue("taskId", running.TaskID)
+	w := httptest.NewRecorder()
+	container.CancelTaskController.Handle(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+
+	// the robot stopped where the task says it did
+	robot, _ := container.RobotRegistry.Get("a")
+	state := robot.CurrentState()
+	stopped, _ := container.TaskRepository.GetById(running.TaskID)
+	if stopped.Status != model.TaskStatusCancelled || stopped.CurrentPosition == nil ||
+		stopped.CurrentPosition.X != state.X || stopped.CurrentPosition.Y != state.Y || state.Y == 4 {
+		t.Fatalf("Expected the task CANCELLED at the robot's stop cell (%d,%d) short of (0,4), got %s at %+v",
+			state.X, state.Y, stopped.Status, stopped.CurrentPosition)
+	}
+
+	// the next task was validated from there and runs from there
+	next := waitFor(queued.TaskID, func(task *model.Task) bool { return task.Status == model.TaskStatusCompleted })
+	if next.PlannedStart == nil || next.PlannedStart.Y != state.Y {
+		t.Errorf("Expected the queued task re-planned from (0,%d), got %+v", state.Y, next.PlannedStart)
+	}
+	if next.CurrentPosition == nil || next.CurrentPosition.X != 1 || next.CurrentPosition.Y != state.Y {
+		t.Errorf("Expected the queued task to end at (1,%d), got %+v", state.Y, next.CurrentPosition)
+	}
+
+	// cancelling it again is rejected without touching the robot
+	req = httptest.NewRequest("DELETE", "/api/tasks/"+running.TaskID, nil)
+	req.SetPathValue("taskId", running.TaskID)
+	w = httptest.NewRecorder()
+	container.CancelTaskController.Handle(w, req)
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
 	}
 }
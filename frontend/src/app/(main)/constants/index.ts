@@ -1,2 +1,2 @@
 // hard coded for now
-export const ROBOT_ID = "0";
+export const ROBOT_ID = "robot-1";